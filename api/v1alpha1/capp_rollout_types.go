@@ -0,0 +1,203 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RolloutStrategyCanary shifts traffic to a new revision in steps, analyzing it at every step.
+	RolloutStrategyCanary = "canary"
+
+	// RolloutStrategyBlueGreen deploys a new revision without traffic until it is promoted.
+	RolloutStrategyBlueGreen = "blueGreen"
+
+	// PromoteRevisionAnnotation is the annotation of a Capp which holds the revisionNumber of the CappRevision
+	// whose Knative Revision should receive all traffic when using the blueGreen strategy.
+	PromoteRevisionAnnotation = "rcs.dana.io/promote-revision"
+)
+
+// RolloutPhase is the phase of the rollout of a new revision of a Capp.
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing means a new revision is being rolled out.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+
+	// RolloutPhaseSucceeded means the latest rollout was completed and all traffic goes to the stable revision.
+	RolloutPhaseSucceeded RolloutPhase = "Succeeded"
+
+	// RolloutPhaseAwaitingPromotion means a new revision is deployed without traffic and awaits promotion.
+	RolloutPhaseAwaitingPromotion RolloutPhase = "AwaitingPromotion"
+
+	// RolloutPhaseRolledBack means the latest rollout failed its analysis and all traffic went back to the stable revision.
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+// RolloutSpec defines how new revisions of the Capp are rolled out.
+type RolloutSpec struct {
+	// Strategy is the rollout strategy.
+	// Possible values: "canary", "blueGreen".
+	// +kubebuilder:validation:Enum=canary;blueGreen
+	Strategy string `json:"strategy"`
+
+	// Canary defines the canary rollout. It is required when Strategy is "canary".
+	// +optional
+	Canary *CanarySpec `json:"canary,omitempty"`
+
+	// BlueGreen defines the blue/green rollout. It can only be set when Strategy is "blueGreen".
+	// +optional
+	BlueGreen *BlueGreenSpec `json:"blueGreen,omitempty"`
+}
+
+// BlueGreenSpec defines a rollout which deploys a new revision without traffic, reachable at a tagged
+// preview URL, until it is promoted using the PromoteRevisionAnnotation.
+type BlueGreenSpec struct {
+	// ScaleDownDelaySeconds is how long the previously active revision is kept warm after a promotion,
+	// during which the promotion can be aborted instantly by promoting the previous revision back.
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ScaleDownDelaySeconds int64 `json:"scaleDownDelaySeconds,omitempty"`
+}
+
+// CanarySpec defines a rollout which shifts traffic to a new revision in steps.
+type CanarySpec struct {
+	// Steps are the percentages of traffic sent to the new revision at every step, in increasing order.
+	// Once the last step passes its analysis, all traffic is sent to the new revision.
+	// +kubebuilder:validation:MinItems=1
+	Steps []int64 `json:"steps"`
+
+	// StepIntervalSeconds is the duration of every step, at the end of which the analysis runs.
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StepIntervalSeconds int64 `json:"stepIntervalSeconds,omitempty"`
+
+	// Analysis defines the metrics checked at the end of every step.
+	// If not set, the steps advance on schedule without any checks.
+	// +optional
+	Analysis *AnalysisSpec `json:"analysis,omitempty"`
+}
+
+// AnalysisSpec defines the metrics checked during a rollout.
+// The metrics are queried from the Prometheus-compatible query API configured on the operator.
+type AnalysisSpec struct {
+	// Metrics are the metrics checked at the end of every step. The analysis fails
+	// if any of them crosses its threshold or its query fails.
+	// +kubebuilder:validation:MinItems=1
+	Metrics []AnalysisMetric `json:"metrics"`
+
+	// FailureLimit is the number of consecutive failed analyses of a step which are tolerated.
+	// A failed analysis is retried until the limit is crossed, after which the new revision is rolled back.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=2
+	// +optional
+	FailureLimit int32 `json:"failureLimit,omitempty"`
+}
+
+// AnalysisMetric defines a metric checked during a rollout.
+type AnalysisMetric struct {
+	// Name is the name of the metric, e.g. "error-rate".
+	Name string `json:"name"`
+
+	// Query is a PromQL query returning a single value. It is a Go template which can reference
+	// {{ .Namespace }}, {{ .Capp }} and {{ .Revision }}, the name of the new Knative Revision.
+	Query string `json:"query"`
+
+	// Max is the highest value of the query result which passes the analysis, e.g. "0.05".
+	Max resource.Quantity `json:"max"`
+}
+
+// RolloutStatus shows the state of the rollout of new revisions of the Capp.
+type RolloutStatus struct {
+	// Phase is the phase of the latest rollout.
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+
+	// StableRevision is the name of the Knative Revision which receives the traffic not sent to the new revision.
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// CanaryRevision is the name of the Knative Revision being rolled out.
+	// +optional
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+
+	// PreviewRevision is the name of the Knative Revision awaiting promotion.
+	// +optional
+	PreviewRevision string `json:"previewRevision,omitempty"`
+
+	// PreviousRevision is the name of the previously active Knative Revision, which is kept warm
+	// until ScaleDownDelaySeconds have passed since the promotion.
+	// +optional
+	PreviousRevision string `json:"previousRevision,omitempty"`
+
+	// PromotionTime is the time of the latest promotion.
+	// +optional
+	PromotionTime metav1.Time `json:"promotionTime,omitempty"`
+
+	// FailedRevision is the name of the last Knative Revision which was rolled back.
+	// +optional
+	FailedRevision string `json:"failedRevision,omitempty"`
+
+	// CurrentStep is the 1-based index of the current step of the rollout.
+	// +optional
+	CurrentStep int `json:"currentStep,omitempty"`
+
+	// CanaryPercent is the percentage of traffic currently sent to the CanaryRevision.
+	// +optional
+	CanaryPercent int64 `json:"canaryPercent,omitempty"`
+
+	// LastStepTime is the time at which the current step started.
+	// +optional
+	LastStepTime metav1.Time `json:"lastStepTime,omitempty"`
+
+	// AnalysisResults are the results of the latest analysis.
+	// +optional
+	AnalysisResults []AnalysisResult `json:"analysisResults,omitempty"`
+
+	// AnalysisFailures is the number of consecutive failed analyses of the current step.
+	// +optional
+	AnalysisFailures int32 `json:"analysisFailures,omitempty"`
+
+	// LastAnalysisTime is the time at which the latest analysis finished.
+	// +optional
+	LastAnalysisTime metav1.Time `json:"lastAnalysisTime,omitempty"`
+
+	// Message is a human-readable description of the state of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AnalysisResult is the result of checking a metric during a rollout.
+type AnalysisResult struct {
+	// Name is the name of the metric.
+	Name string `json:"name"`
+
+	// Value is the value of the metric, or empty if the query returned no data.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Passed indicates whether the value was within the threshold.
+	Passed bool `json:"passed"`
+
+	// Message describes why the check failed or returned no data.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
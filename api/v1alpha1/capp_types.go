@@ -20,11 +20,14 @@ import (
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	dnsrecordsetv1alpha1 "github.com/dana-team/provider-dns/apis/recordset/v1alpha1"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // CappSpec defines the desired state of Capp.
@@ -54,6 +57,11 @@ type CappSpec struct {
 	// +optional
 	RouteSpec RouteSpec `json:"routeSpec,omitempty"`
 
+	// RolloutSpec defines how new revisions of the Capp are rolled out.
+	// If not set, all traffic goes to every new revision once it is ready.
+	// +optional
+	RolloutSpec *RolloutSpec `json:"rolloutSpec,omitempty"`
+
 	// LogSpec defines the configuration for shipping Capp logs.
 	LogSpec LogSpec `json:"logSpec,omitempty"`
 
@@ -82,6 +90,14 @@ type NFSVolume struct {
 	Capacity corev1.ResourceList `json:"capacity"`
 }
 
+const (
+	// RouteVisibilityExternal makes the Capp route reachable from outside the cluster.
+	RouteVisibilityExternal = "external"
+
+	// RouteVisibilityClusterLocal makes the Capp route reachable only from within the cluster.
+	RouteVisibilityClusterLocal = "cluster-local"
+)
+
 // RouteSpec defines the route specification for the Capp.
 type RouteSpec struct {
 	// Hostname is a custom DNS name for the Capp route.
@@ -92,14 +108,75 @@ type RouteSpec struct {
 	// +optional
 	TlsEnabled bool `json:"tlsEnabled,omitempty"`
 
+	// TagHostnamesEnabled gives every tagged traffic target of the Capp route its own hostname,
+	// "<tag>-<hostname>", with its own DNS record, DomainMapping and Certificate. It requires Hostname to be set.
+	// +optional
+	TagHostnamesEnabled bool `json:"tagHostnamesEnabled,omitempty"`
+
+	// AdditionalHostnames are custom DNS names for the Capp route in addition to Hostname, such as
+	// vanity or legacy names. Each gets its own DNS record and DomainMapping, and they are all added
+	// to the Certificate of Hostname. They require Hostname to be set.
+	// +optional
+	AdditionalHostnames []string `json:"additionalHostnames,omitempty"`
+
+	// Visibility is the visibility of the Capp route. A "cluster-local" Capp is only reachable from within
+	// the cluster on its internal URL, and can not have custom hostnames. Defaults to "external".
+	// +kubebuilder:validation:Enum=external;cluster-local
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
 	// TrafficTarget holds a single entry of the routing table for the Capp route.
+	// Deprecated: use TrafficTargets instead. It is moved into TrafficTargets by the webhook.
 	// +optional
 	TrafficTarget knativev1.TrafficTarget `json:"trafficTarget,omitempty"`
 
+	// TrafficTargets holds the entries of the routing table for the Capp route. The percentages
+	// of the entries must add up to 100. If empty, all traffic goes to the latest ready revision.
+	// +optional
+	TrafficTargets []CappTrafficTarget `json:"trafficTargets,omitempty"`
+
 	// RouteTimeoutSeconds is the maximum duration in seconds
 	// that the request instance is allowed to respond to a request.
 	// +optional
 	RouteTimeoutSeconds *int64 `json:"routeTimeoutSeconds,omitempty"`
+
+	// DNSRecordTTLSeconds is the TTL in seconds of the DNS records of the hostnames of the Capp.
+	// Defaults to the ttl of the DNS ConfigMap, or to the default of the DNS backend if it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DNSRecordTTLSeconds *int64 `json:"dnsRecordTTLSeconds,omitempty"`
+
+	// CertificateIssuerRef is the issuer of the Certificate of the Capp route. It requires TlsEnabled
+	// to be set, and must be an Issuer in the namespace of the Capp. Defaults to the issuer of the zone
+	// of Hostname from the DNS ConfigMap.
+	// +optional
+	CertificateIssuerRef *CertificateIssuerReference `json:"certificateIssuerRef,omitempty"`
+}
+
+// CertificateIssuerReference references an Issuer of a Certificate in the namespace of the Capp.
+type CertificateIssuerReference struct {
+	// Name is the name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind is the kind of the issuer, which must be "Issuer", as a Capp may not use the cluster-scoped
+	// issuers directly. Defaults to "Issuer".
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group is the API group of the issuer. Defaults to "cert-manager.io".
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CappTrafficTarget holds a single entry of the routing table for the Capp route. It points either at
+// a CappRevision, a Knative Revision or the latest ready revision.
+type CappTrafficTarget struct {
+	// CappRevisionNumber is the RevisionNumber of the CappRevision whose Knative Revision receives the traffic.
+	// +optional
+	CappRevisionNumber int `json:"cappRevisionNumber,omitempty"`
+
+	knativev1.TrafficTarget `json:",inline"`
 }
 
 // LogSpec defines the configuration for shipping Capp logs.
@@ -188,12 +265,108 @@ type RouteStatus struct {
 	// CertificateObjectStatus is the status of the underlying Certificate object
 	// +optional
 	CertificateObjectStatus cmapi.CertificateStatus `json:"certificateObjectStatus,omitempty"`
+
+	// HTTPRouteObjectStatus is the status of the underlying HTTPRoute object, when the hostname
+	// of the Capp is routed using the Gateway API
+	// +optional
+	HTTPRouteObjectStatus gatewayv1.HTTPRouteStatus `json:"httpRouteObjectStatus,omitempty"`
+
+	// OpenShiftRouteObjectStatus is the status of the underlying OpenShift Route object, when the hostname
+	// of the Capp is exposed using OpenShift Routes
+	// +optional
+	OpenShiftRouteObjectStatus routev1.RouteStatus `json:"openShiftRouteObjectStatus,omitempty"`
+
+	// Hostnames is the status of every hostname of the Capp route.
+	// +optional
+	Hostnames []HostnameStatus `json:"hostnames,omitempty"`
+}
+
+// HostnameStatus shows the state of the objects of a single hostname of the Capp route.
+// The readiness of an object which is not required for the hostname is left empty.
+type HostnameStatus struct {
+	// Hostname is the fully qualified hostname.
+	Hostname string `json:"hostname"`
+
+	// Tag is the traffic tag the hostname is routed to, if any.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// URL is the URL the Capp is reachable on through the hostname.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// NotAllowed is set when the namespace of the Capp is not allowed to use the zone of the hostname.
+	// The DNS record, routes and Certificate of the hostname are not managed while it is set.
+	// +optional
+	NotAllowed bool `json:"notAllowed,omitempty"`
+
+	// DomainMappingReady is the status of the Ready condition of the DomainMapping of the hostname.
+	// +optional
+	DomainMappingReady metav1.ConditionStatus `json:"domainMappingReady,omitempty"`
+
+	// HTTPRouteReady is the readiness of the HTTPRoute of the hostname, when the hostname is routed using the Gateway API.
+	// +optional
+	HTTPRouteReady metav1.ConditionStatus `json:"httpRouteReady,omitempty"`
+
+	// RouteAdmitted is the admission status of the OpenShift Route of the hostname, when the hostname is
+	// exposed using OpenShift Routes.
+	// +optional
+	RouteAdmitted metav1.ConditionStatus `json:"routeAdmitted,omitempty"`
+
+	// DNSRecordReady is the status of the Ready condition of the DNS record of the hostname.
+	// +optional
+	DNSRecordReady metav1.ConditionStatus `json:"dnsRecordReady,omitempty"`
+
+	// ClaimedBy is the Capp or CappRouter which claimed the hostname first, when it is not this Capp, or the
+	// cluster whose owner ID is in the ownership TXT record of the hostname, when it is another cluster.
+	// The DNS record of the hostname is not managed for the Capp while it is claimed by another.
+	// +optional
+	ClaimedBy string `json:"claimedBy,omitempty"`
+
+	// CertificateReady is the status of the Ready condition of the Certificate which covers the hostname.
+	// +optional
+	CertificateReady metav1.ConditionStatus `json:"certificateReady,omitempty"`
 }
 
 type DNSRecordObjectStatus struct {
-	// CNAMERecordObjectStatus is the status of the underlying ARecordSet object
+	// Backend is the DNS backend which manages the DNS record, either provider-dns or external-dns.
+	// +optional
+	Backend string `json:"backend,omitempty"`
+
+	// RecordTypes are the types of the DNS records of the hostname, e.g. CNAME, A, AAAA or TXT.
+	// +optional
+	RecordTypes []string `json:"recordTypes,omitempty"`
+
+	// CNAMERecordObjectStatus is the status of the underlying CNAMERecord object
 	// +optional
 	CNAMERecordObjectStatus dnsrecordv1alpha1.CNAMERecordStatus `json:"cnameRecordObjectStatus,omitempty"`
+
+	// ARecordSetObjectStatus is the status of the underlying ARecordSet object.
+	// +optional
+	ARecordSetObjectStatus dnsrecordsetv1alpha1.ARecordSetStatus `json:"aRecordSetObjectStatus,omitempty"`
+
+	// AAAARecordSetObjectStatus is the status of the underlying AAAARecordSet object.
+	// +optional
+	AAAARecordSetObjectStatus dnsrecordsetv1alpha1.AAAARecordSetStatus `json:"aaaaRecordSetObjectStatus,omitempty"`
+
+	// TXTRecordSetObjectStatus is the status of the underlying TXTRecordSet object, which holds the ownership record.
+	// +optional
+	TXTRecordSetObjectStatus dnsrecordsetv1alpha1.TXTRecordSetStatus `json:"txtRecordSetObjectStatus,omitempty"`
+
+	// DNSEndpointObjectStatus is the status of the underlying external-dns DNSEndpoint object.
+	// +optional
+	DNSEndpointObjectStatus DNSEndpointObjectStatus `json:"dnsEndpointObjectStatus,omitempty"`
+}
+
+// DNSEndpointObjectStatus shows the state of an external-dns DNSEndpoint object.
+type DNSEndpointObjectStatus struct {
+	// Generation is the generation of the DNSEndpoint.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// ObservedGeneration is the generation of the DNSEndpoint which was last processed by external-dns.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // VolumesStatus shows the state of the Volumes objects linked to the Capp.
@@ -210,8 +383,67 @@ type NFSVolumeStatus struct {
 	NFSPVCStatus nfspvcv1alpha1.NfsPvcStatus `json:"nfsPvcStatus,omitempty"`
 }
 
+const (
+	// ConditionTypeReady is the aggregated condition of a Capp, which is true
+	// only when all the subsystems required by the Capp are ready.
+	ConditionTypeReady = "Ready"
+
+	// ConditionTypeKnativeServiceReady reflects the readiness of the Knative Service of the Capp.
+	ConditionTypeKnativeServiceReady = "KnativeServiceReady"
+
+	// ConditionTypeDomainMappingReady reflects the readiness of the DomainMapping of the Capp.
+	ConditionTypeDomainMappingReady = "DomainMappingReady"
+
+	// ConditionTypeHTTPRouteReady reflects the readiness of the HTTPRoute of the Capp.
+	ConditionTypeHTTPRouteReady = "HTTPRouteReady"
+
+	// ConditionTypeRouteAdmitted reflects whether the OpenShift Route of the Capp was admitted by the router.
+	ConditionTypeRouteAdmitted = "RouteAdmitted"
+
+	// ConditionTypeDNSRecordReady reflects the readiness of the DNS record of the Capp.
+	ConditionTypeDNSRecordReady = "DNSRecordReady"
+
+	// ConditionTypeCertificateReady reflects the readiness of the Certificate of the Capp.
+	ConditionTypeCertificateReady = "CertificateReady"
+
+	// ConditionTypeVolumesReady reflects the readiness of the volumes of the Capp.
+	ConditionTypeVolumesReady = "VolumesReady"
+
+	// ConditionTypeLoggingReady reflects the readiness of the logging objects of the Capp.
+	ConditionTypeLoggingReady = "LoggingReady"
+
+	// ConditionTypeHostnameNotAllowed reflects whether the namespace of the Capp is not allowed to use the zone
+	// of any of its hostnames, in which case the hostname is not managed.
+	ConditionTypeHostnameNotAllowed = "HostnameNotAllowed"
+)
+
 // CappStatus defines the observed state of Capp.
 type CappStatus struct {
+	// ObservedGeneration is the generation of the Capp spec which was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// URL is the effective public URL of the Capp. It is the custom hostname
+	// if one is set, and otherwise the URL of the Knative Service.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// InternalURL is the cluster-internal URL of the Capp.
+	// +optional
+	InternalURL string `json:"internalURL,omitempty"`
+
+	// LatestCreatedRevisionName is the name of the last revision created for the Capp.
+	// +optional
+	LatestCreatedRevisionName string `json:"latestCreatedRevisionName,omitempty"`
+
+	// LatestReadyRevisionName is the name of the last revision of the Capp which became ready.
+	// +optional
+	LatestReadyRevisionName string `json:"latestReadyRevisionName,omitempty"`
+
+	// Traffic holds the current split of traffic between the revisions of the Capp.
+	// +optional
+	Traffic []knativev1.TrafficTarget `json:"traffic,omitempty"`
+
 	// ApplicationLinks contains relevant information about
 	// the cluster that the Capp is deployed in.
 	// +optional
@@ -241,8 +473,15 @@ type CappStatus struct {
 	// +optional
 	VolumesStatus VolumesStatus `json:"volumesStatus,omitempty"`
 
+	// RolloutStatus shows the state of the rollout of new revisions of the Capp.
+	// +optional
+	RolloutStatus *RolloutStatus `json:"rolloutStatus,omitempty"`
+
 	// Conditions contain details about the current state of the Capp.
+	// The Ready condition aggregates the conditions of all the subsystems required by the Capp.
 	// +optional
+	// +listType=map
+	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
@@ -250,7 +489,15 @@ type CappStatus struct {
 // +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".status.applicationLinks.site",description="cluster of the resource"
 // +kubebuilder:printcolumn:name="Custom URL",type="string",JSONPath=".spec.routeSpec.hostname",description="shorten url"
 // +kubebuilder:printcolumn:name="AutoScale Type",type="string",JSONPath=".spec.scaleMetric",description="autoscale metric"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="effective public url"
+// +kubebuilder:printcolumn:name="Internal URL",type="string",JSONPath=".status.internalURL",description="cluster-internal url",priority=1
+// +kubebuilder:printcolumn:name="Latest Created",type="string",JSONPath=".status.latestCreatedRevisionName",description="latest created revision",priority=1
+// +kubebuilder:printcolumn:name="Latest Ready",type="string",JSONPath=".status.latestReadyRevisionName",description="latest ready revision"
+// +kubebuilder:printcolumn:name="Rollout",type="string",JSONPath=".status.rolloutStatus.phase",description="phase of the latest rollout",priority=1
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="readiness of the capp"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",description="reason of the readiness of the capp"
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 
 // Capp is the Schema for the capps API.
 type Capp struct {
@@ -20,6 +20,31 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// RollbackToRevisionAnnotation is set on a Capp to the RevisionNumber of one of its
+// CappRevisions in order to roll the Capp back to the template of that CappRevision.
+// It is removed from the Capp once the rollback is handled.
+const RollbackToRevisionAnnotation = "rcs.dana.io/rollback-to-revision"
+
+// RevisionsToKeepAnnotation is set on a Capp to override the number of unpinned
+// CappRevisions kept for it.
+const RevisionsToKeepAnnotation = "rcs.dana.io/revisions-to-keep"
+
+// RevisionMaxAgeAnnotation is set on a Capp to a duration (e.g. "720h") after which its
+// unpinned CappRevisions are pruned, overriding the global setting.
+const RevisionMaxAgeAnnotation = "rcs.dana.io/revision-max-age"
+
+// ChangeCauseAnnotation is set on a Capp to describe the cause of a change. It is recorded
+// in the status of the CappRevision created for the change.
+const ChangeCauseAnnotation = "kubernetes.io/change-cause"
+
+// LastModifiedByAnnotation is set on a Capp at admission time to the JSON encoded ModifiedBy
+// of the last change to its spec, labels or annotations. It is recorded in the status of the
+// CappRevision created for the change.
+const LastModifiedByAnnotation = "rcs.dana.io/last-modified-by"
+
+// PinnedRevisionLabel is set to "true" on a CappRevision to exclude it from pruning.
+const PinnedRevisionLabel = "rcs.dana.io/pinned"
+
 // CappRevisionSpec defines the desired state of CappRevision
 type CappRevisionSpec struct {
 	// RevisionNumber represent the revision number of Capp
@@ -31,6 +56,38 @@ type CappRevisionSpec struct {
 
 // CappRevisionStatus defines the observed state of CappRevision
 type CappRevisionStatus struct {
+	// ChangedFields lists the top-level fields of the Capp which changed compared with the previous CappRevision
+	// +optional
+	ChangedFields []string `json:"changedFields,omitempty"`
+
+	// ChangeCause is the value of the kubernetes.io/change-cause annotation of the Capp at the time of the change
+	// +optional
+	ChangeCause string `json:"changeCause,omitempty"`
+
+	// KnativeRevisionName is the name of the Knative Revision which served the Capp once the change was applied
+	// +optional
+	KnativeRevisionName string `json:"knativeRevisionName,omitempty"`
+
+	// KnativeRevisionReady indicates whether the Knative Revision became Ready
+	// +optional
+	KnativeRevisionReady bool `json:"knativeRevisionReady,omitempty"`
+
+	// ModifiedBy describes who made the change to the Capp
+	// +optional
+	ModifiedBy *ModifiedBy `json:"modifiedBy,omitempty"`
+}
+
+// ModifiedBy describes who made a change to a Capp and when
+type ModifiedBy struct {
+	// Username is the name of the user who made the change
+	Username string `json:"username"`
+
+	// Groups are the groups of the user who made the change
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// Timestamp is the time at which the change was admitted
+	Timestamp metav1.Time `json:"timestamp"`
 }
 
 // CappTemplate template of Capp.
@@ -49,6 +106,12 @@ type CappTemplate struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".spec.revisionNumber",description="revision number of the capp"
+// +kubebuilder:printcolumn:name="Knative Revision",type="string",JSONPath=".status.knativeRevisionName",description="knative revision which served the capp"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.knativeRevisionReady",description="readiness of the knative revision"
+// +kubebuilder:printcolumn:name="Changed",type="string",JSONPath=".status.changedFields",description="fields changed since the previous revision",priority=1
+// +kubebuilder:printcolumn:name="Modified By",type="string",JSONPath=".status.modifiedBy.username",description="user who made the change",priority=1
+// +kubebuilder:printcolumn:name="Change Cause",type="string",JSONPath=".status.changeCause",description="cause of the change"
 
 // CappRevision is the Schema for the CappRevisions API
 type CappRevision struct {
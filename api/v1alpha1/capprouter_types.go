@@ -0,0 +1,130 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeBackendsReady reflects whether all the Capps a CappRouter routes to are ready.
+	ConditionTypeBackendsReady = "BackendsReady"
+)
+
+// CappRouterSpec defines the desired state of CappRouter
+type CappRouterSpec struct {
+	// Hostname is the hostname which is shared by the routes of the CappRouter. It is either a name
+	// in the zone of the operator or a fully qualified hostname in that zone.
+	// +kubebuilder:validation:MinLength=1
+	Hostname string `json:"hostname"`
+
+	// TlsEnabled determines whether the hostname is served over HTTPS, using a Certificate issued for it.
+	// +optional
+	TlsEnabled bool `json:"tlsEnabled,omitempty"`
+
+	// Routes maps path prefixes of the hostname to Capps in the namespace of the CappRouter.
+	// Requests are routed to the Capp of the longest matching path prefix.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:XValidation:rule="self.all(r, self.exists_one(o, o.pathPrefix == r.pathPrefix))",message="pathPrefix must be unique"
+	Routes []CappRouterRoute `json:"routes"`
+}
+
+// CappRouterRoute routes the requests of a path prefix to a Capp.
+type CappRouterRoute struct {
+	// PathPrefix is the path prefix of the requests which are routed to the Capp, such as "/orders".
+	// +kubebuilder:validation:Pattern=`^/[^?#\s]*$`
+	// +kubebuilder:validation:MaxLength=1024
+	PathPrefix string `json:"pathPrefix"`
+
+	// CappName is the name of the Capp which serves the path prefix.
+	// +kubebuilder:validation:MinLength=1
+	CappName string `json:"cappName"`
+}
+
+// CappRouterStatus defines the observed state of CappRouter
+type CappRouterStatus struct {
+	// Hostname is the fully qualified hostname of the CappRouter.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// URL is the URL the CappRouter is reachable on.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// DNSBackend is the DNS backend which manages the DNS record of the hostname, either provider-dns or external-dns.
+	// +optional
+	DNSBackend string `json:"dnsBackend,omitempty"`
+
+	// Routes is the status of the backend of every route of the CappRouter.
+	// +optional
+	Routes []CappRouterRouteStatus `json:"routes,omitempty"`
+
+	// Conditions reflect the readiness of the DNS record, Certificate, HTTPRoute and backends of the CappRouter,
+	// aggregated into the Ready condition.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the CappRouter which the status was built from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// CappRouterRouteStatus is the status of the backend of a route of a CappRouter.
+type CappRouterRouteStatus struct {
+	// PathPrefix is the path prefix of the route.
+	PathPrefix string `json:"pathPrefix"`
+
+	// CappName is the name of the Capp the route points at.
+	CappName string `json:"cappName"`
+
+	// Ready is the status of the Ready condition of the Capp, or False if the Capp does not exist.
+	Ready metav1.ConditionStatus `json:"ready"`
+
+	// Message explains why the Capp is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Hostname",type="string",JSONPath=".status.hostname",description="hostname of the capprouter"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="readiness of the capprouter"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CappRouter is the Schema for the CappRouters API. It routes the path prefixes of a shared
+// hostname to different Capps, and owns the DNS record and Certificate of the hostname.
+type CappRouter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CappRouterSpec   `json:"spec,omitempty"`
+	Status CappRouterStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CappRouterList contains a list of CappRouter
+type CappRouterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CappRouter `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CappRouter{}, &CappRouterList{})
+}
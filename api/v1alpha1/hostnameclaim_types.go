@@ -0,0 +1,99 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeHostnameConflict reflects whether any hostname of a Capp or CappRouter was claimed first
+	// by another Capp or CappRouter, in which case its DNS record is not managed for it.
+	ConditionTypeHostnameConflict = "HostnameConflict"
+)
+
+// HostnameClaimSpec defines the owner of a hostname.
+type HostnameClaimSpec struct {
+	// Owner is the Capp or CappRouter which claimed the hostname first.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="owner is immutable"
+	Owner HostnameClaimOwner `json:"owner"`
+}
+
+// HostnameClaimOwner references the Capp or CappRouter which claimed a hostname.
+type HostnameClaimOwner struct {
+	// Kind is the kind of the owner.
+	// +kubebuilder:validation:Enum=Capp;CappRouter
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the owner.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the owner.
+	Name string `json:"name"`
+}
+
+// HostnameClaimStatus defines the ownership of a hostname by the clusters which share the DNS records of its zone.
+type HostnameClaimStatus struct {
+	// OwnershipVerified is set once the ownership TXT record of the hostname was looked up after it was claimed,
+	// which is only done when the txtOwnerID is set in the DNS ConfigMap.
+	// +optional
+	OwnershipVerified bool `json:"ownershipVerified,omitempty"`
+
+	// ClusterOwner is the owner ID of another cluster whose operator wrote the ownership TXT record of the hostname,
+	// in which case its objects are not managed.
+	// +optional
+	ClusterOwner string `json:"clusterOwner,omitempty"`
+}
+
+// String returns the kind, namespace and name of the owner.
+func (o HostnameClaimOwner) String() string {
+	return fmt.Sprintf("%s %s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.owner.kind",description="kind of the owner of the hostname"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.owner.namespace",description="namespace of the owner of the hostname"
+// +kubebuilder:printcolumn:name="Owner",type="string",JSONPath=".spec.owner.name",description="name of the owner of the hostname"
+// +kubebuilder:printcolumn:name="Cluster Owner",type="string",JSONPath=".status.clusterOwner",description="owner ID of another cluster which owns the hostname"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HostnameClaim is the Schema for the HostnameClaims API. It is named after a hostname and records the
+// Capp or CappRouter which claimed the hostname first, as the DNS records of a hostname are shared by the cluster.
+type HostnameClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostnameClaimSpec   `json:"spec,omitempty"`
+	Status HostnameClaimStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HostnameClaimList contains a list of HostnameClaim
+type HostnameClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostnameClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HostnameClaim{}, &HostnameClaimList{})
+}
@@ -24,8 +24,62 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisMetric) DeepCopyInto(out *AnalysisMetric) {
+	*out = *in
+	out.Max = in.Max.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisMetric.
+func (in *AnalysisMetric) DeepCopy() *AnalysisMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisResult) DeepCopyInto(out *AnalysisResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisResult.
+func (in *AnalysisResult) DeepCopy() *AnalysisResult {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisSpec) DeepCopyInto(out *AnalysisSpec) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AnalysisMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisSpec.
+func (in *AnalysisSpec) DeepCopy() *AnalysisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationLinks) DeepCopyInto(out *ApplicationLinks) {
 	*out = *in
@@ -41,6 +95,46 @@ func (in *ApplicationLinks) DeepCopy() *ApplicationLinks {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenSpec) DeepCopyInto(out *BlueGreenSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenSpec.
+func (in *BlueGreenSpec) DeepCopy() *BlueGreenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(AnalysisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySpec.
+func (in *CanarySpec) DeepCopy() *CanarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Capp) DeepCopyInto(out *Capp) {
 	*out = *in
@@ -106,7 +200,7 @@ func (in *CappRevision) DeepCopyInto(out *CappRevision) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRevision.
@@ -178,6 +272,16 @@ func (in *CappRevisionSpec) DeepCopy() *CappRevisionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappRevisionStatus) DeepCopyInto(out *CappRevisionStatus) {
 	*out = *in
+	if in.ChangedFields != nil {
+		in, out := &in.ChangedFields, &out.ChangedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModifiedBy != nil {
+		in, out := &in.ModifiedBy, &out.ModifiedBy
+		*out = new(ModifiedBy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRevisionStatus.
@@ -190,11 +294,152 @@ func (in *CappRevisionStatus) DeepCopy() *CappRevisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappRouter) DeepCopyInto(out *CappRouter) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRouter.
+func (in *CappRouter) DeepCopy() *CappRouter {
+	if in == nil {
+		return nil
+	}
+	out := new(CappRouter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CappRouter) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappRouterList) DeepCopyInto(out *CappRouterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CappRouter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRouterList.
+func (in *CappRouterList) DeepCopy() *CappRouterList {
+	if in == nil {
+		return nil
+	}
+	out := new(CappRouterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CappRouterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappRouterRoute) DeepCopyInto(out *CappRouterRoute) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRouterRoute.
+func (in *CappRouterRoute) DeepCopy() *CappRouterRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(CappRouterRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappRouterRouteStatus) DeepCopyInto(out *CappRouterRouteStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRouterRouteStatus.
+func (in *CappRouterRouteStatus) DeepCopy() *CappRouterRouteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CappRouterRouteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappRouterSpec) DeepCopyInto(out *CappRouterSpec) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]CappRouterRoute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRouterSpec.
+func (in *CappRouterSpec) DeepCopy() *CappRouterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CappRouterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappRouterStatus) DeepCopyInto(out *CappRouterStatus) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]CappRouterRouteStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRouterStatus.
+func (in *CappRouterStatus) DeepCopy() *CappRouterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CappRouterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappSpec) DeepCopyInto(out *CappSpec) {
 	*out = *in
 	in.ConfigurationSpec.DeepCopyInto(&out.ConfigurationSpec)
 	in.RouteSpec.DeepCopyInto(&out.RouteSpec)
+	if in.RolloutSpec != nil {
+		in, out := &in.RolloutSpec, &out.RolloutSpec
+		*out = new(RolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.LogSpec = in.LogSpec
 	in.VolumesSpec.DeepCopyInto(&out.VolumesSpec)
 }
@@ -212,6 +457,13 @@ func (in *CappSpec) DeepCopy() *CappSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappStatus) DeepCopyInto(out *CappStatus) {
 	*out = *in
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = make([]servingv1.TrafficTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.ApplicationLinks = in.ApplicationLinks
 	in.KnativeObjectStatus.DeepCopyInto(&out.KnativeObjectStatus)
 	if in.RevisionInfo != nil {
@@ -225,6 +477,11 @@ func (in *CappStatus) DeepCopyInto(out *CappStatus) {
 	in.LoggingStatus.DeepCopyInto(&out.LoggingStatus)
 	in.RouteStatus.DeepCopyInto(&out.RouteStatus)
 	in.VolumesStatus.DeepCopyInto(&out.VolumesStatus)
+	if in.RolloutStatus != nil {
+		in, out := &in.RolloutStatus, &out.RolloutStatus
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -274,10 +531,65 @@ func (in *CappTemplate) DeepCopy() *CappTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappTrafficTarget) DeepCopyInto(out *CappTrafficTarget) {
+	*out = *in
+	in.TrafficTarget.DeepCopyInto(&out.TrafficTarget)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappTrafficTarget.
+func (in *CappTrafficTarget) DeepCopy() *CappTrafficTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(CappTrafficTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerReference) DeepCopyInto(out *CertificateIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerReference.
+func (in *CertificateIssuerReference) DeepCopy() *CertificateIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSEndpointObjectStatus) DeepCopyInto(out *DNSEndpointObjectStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSEndpointObjectStatus.
+func (in *DNSEndpointObjectStatus) DeepCopy() *DNSEndpointObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSEndpointObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSRecordObjectStatus) DeepCopyInto(out *DNSRecordObjectStatus) {
 	*out = *in
+	if in.RecordTypes != nil {
+		in, out := &in.RecordTypes, &out.RecordTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.CNAMERecordObjectStatus.DeepCopyInto(&out.CNAMERecordObjectStatus)
+	in.ARecordSetObjectStatus.DeepCopyInto(&out.ARecordSetObjectStatus)
+	in.AAAARecordSetObjectStatus.DeepCopyInto(&out.AAAARecordSetObjectStatus)
+	in.TXTRecordSetObjectStatus.DeepCopyInto(&out.TXTRecordSetObjectStatus)
+	out.DNSEndpointObjectStatus = in.DNSEndpointObjectStatus
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordObjectStatus.
@@ -290,6 +602,126 @@ func (in *DNSRecordObjectStatus) DeepCopy() *DNSRecordObjectStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameClaim) DeepCopyInto(out *HostnameClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameClaim.
+func (in *HostnameClaim) DeepCopy() *HostnameClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostnameClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameClaimList) DeepCopyInto(out *HostnameClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostnameClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameClaimList.
+func (in *HostnameClaimList) DeepCopy() *HostnameClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostnameClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameClaimOwner) DeepCopyInto(out *HostnameClaimOwner) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameClaimOwner.
+func (in *HostnameClaimOwner) DeepCopy() *HostnameClaimOwner {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameClaimOwner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameClaimSpec) DeepCopyInto(out *HostnameClaimSpec) {
+	*out = *in
+	out.Owner = in.Owner
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameClaimSpec.
+func (in *HostnameClaimSpec) DeepCopy() *HostnameClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameClaimStatus) DeepCopyInto(out *HostnameClaimStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameClaimStatus.
+func (in *HostnameClaimStatus) DeepCopy() *HostnameClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameStatus) DeepCopyInto(out *HostnameStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameStatus.
+func (in *HostnameStatus) DeepCopy() *HostnameStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogSpec) DeepCopyInto(out *LogSpec) {
 	*out = *in
@@ -329,6 +761,27 @@ func (in *LoggingStatus) DeepCopy() *LoggingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModifiedBy) DeepCopyInto(out *ModifiedBy) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModifiedBy.
+func (in *ModifiedBy) DeepCopy() *ModifiedBy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModifiedBy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NFSVolume) DeepCopyInto(out *NFSVolume) {
 	*out = *in
@@ -383,15 +836,85 @@ func (in *RevisionInfo) DeepCopy() *RevisionInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanarySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(BlueGreenSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutSpec.
+func (in *RolloutSpec) DeepCopy() *RolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	in.PromotionTime.DeepCopyInto(&out.PromotionTime)
+	in.LastStepTime.DeepCopyInto(&out.LastStepTime)
+	if in.AnalysisResults != nil {
+		in, out := &in.AnalysisResults, &out.AnalysisResults
+		*out = make([]AnalysisResult, len(*in))
+		copy(*out, *in)
+	}
+	in.LastAnalysisTime.DeepCopyInto(&out.LastAnalysisTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
 	*out = *in
+	if in.AdditionalHostnames != nil {
+		in, out := &in.AdditionalHostnames, &out.AdditionalHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.TrafficTarget.DeepCopyInto(&out.TrafficTarget)
+	if in.TrafficTargets != nil {
+		in, out := &in.TrafficTargets, &out.TrafficTargets
+		*out = make([]CappTrafficTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.RouteTimeoutSeconds != nil {
 		in, out := &in.RouteTimeoutSeconds, &out.RouteTimeoutSeconds
 		*out = new(int64)
 		**out = **in
 	}
+	if in.DNSRecordTTLSeconds != nil {
+		in, out := &in.DNSRecordTTLSeconds, &out.DNSRecordTTLSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CertificateIssuerRef != nil {
+		in, out := &in.CertificateIssuerRef, &out.CertificateIssuerRef
+		*out = new(CertificateIssuerReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
@@ -410,6 +933,13 @@ func (in *RouteStatus) DeepCopyInto(out *RouteStatus) {
 	in.DomainMappingObjectStatus.DeepCopyInto(&out.DomainMappingObjectStatus)
 	in.DNSRecordObjectStatus.DeepCopyInto(&out.DNSRecordObjectStatus)
 	in.CertificateObjectStatus.DeepCopyInto(&out.CertificateObjectStatus)
+	in.HTTPRouteObjectStatus.DeepCopyInto(&out.HTTPRouteObjectStatus)
+	in.OpenShiftRouteObjectStatus.DeepCopyInto(&out.OpenShiftRouteObjectStatus)
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]HostnameStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteStatus.
@@ -0,0 +1,129 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Capp to the Hub version (v1alpha1).
+func (src *Capp) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*cappv1alpha1.Capp)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Capp but got a %T", dstRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Status.DeepCopyInto(&dst.Status)
+
+	dst.Spec.ScaleMetric = src.Spec.ScaleMetric
+	dst.Spec.Site = src.Spec.Site
+	dst.Spec.State = string(src.Spec.State)
+	src.Spec.ConfigurationSpec.DeepCopyInto(&dst.Spec.ConfigurationSpec)
+	dst.Spec.VolumesSpec.NFSVolumes = src.Spec.VolumesSpec.DeepCopy().NFSVolumes
+	dst.Spec.RolloutSpec = src.Spec.RolloutSpec.DeepCopy()
+
+	routeSpec := src.Spec.RouteSpec.DeepCopy()
+	dst.Spec.RouteSpec = cappv1alpha1.RouteSpec{
+		Hostname:             routeSpec.Hostname,
+		TlsEnabled:           routeSpec.TLSEnabled,
+		TagHostnamesEnabled:  routeSpec.TagHostnamesEnabled,
+		AdditionalHostnames:  routeSpec.AdditionalHostnames,
+		Visibility:           routeSpec.Visibility,
+		TrafficTargets:       routeSpec.TrafficTargets,
+		RouteTimeoutSeconds:  routeSpec.RouteTimeoutSeconds,
+		DNSRecordTTLSeconds:  routeSpec.DNSRecordTTLSeconds,
+		CertificateIssuerRef: routeSpec.CertificateIssuerRef,
+	}
+
+	// LogSpecs holds a single destination at most, which is the LogSpec of v1alpha1.
+	dst.Spec.LogSpec = cappv1alpha1.LogSpec{}
+	if len(src.Spec.LogSpecs) > 0 {
+		dst.Spec.LogSpec = convertLogSpecToHub(src.Spec.LogSpecs[0])
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Capp) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*cappv1alpha1.Capp)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Capp but got a %T", srcRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Status.DeepCopyInto(&dst.Status)
+
+	dst.Spec.ScaleMetric = src.Spec.ScaleMetric
+	dst.Spec.Site = src.Spec.Site
+	dst.Spec.State = CappState(src.Spec.State)
+	src.Spec.ConfigurationSpec.DeepCopyInto(&dst.Spec.ConfigurationSpec)
+	dst.Spec.VolumesSpec.NFSVolumes = src.Spec.VolumesSpec.DeepCopy().NFSVolumes
+	dst.Spec.RolloutSpec = src.Spec.RolloutSpec.DeepCopy()
+
+	routeSpec := src.Spec.RouteSpec.DeepCopy()
+	dst.Spec.RouteSpec = RouteSpec{
+		Hostname:             routeSpec.Hostname,
+		TLSEnabled:           routeSpec.TlsEnabled,
+		TagHostnamesEnabled:  routeSpec.TagHostnamesEnabled,
+		AdditionalHostnames:  routeSpec.AdditionalHostnames,
+		Visibility:           routeSpec.Visibility,
+		TrafficTargets:       routeSpec.TrafficTargets,
+		RouteTimeoutSeconds:  routeSpec.RouteTimeoutSeconds,
+		DNSRecordTTLSeconds:  routeSpec.DNSRecordTTLSeconds,
+		CertificateIssuerRef: routeSpec.CertificateIssuerRef,
+	}
+	// The deprecated single TrafficTarget is only set on Capps stored before TrafficTargets was added.
+	if len(routeSpec.TrafficTargets) == 0 && !equality.Semantic.DeepEqual(routeSpec.TrafficTarget, knativev1.TrafficTarget{}) {
+		dst.Spec.RouteSpec.TrafficTargets = []cappv1alpha1.CappTrafficTarget{{TrafficTarget: routeSpec.TrafficTarget}}
+	}
+
+	dst.Spec.LogSpecs = nil
+	if src.Spec.LogSpec != (cappv1alpha1.LogSpec{}) {
+		dst.Spec.LogSpecs = []LogSpec{convertLogSpecFromHub(src.Spec.LogSpec)}
+	}
+
+	return nil
+}
+
+// convertLogSpecToHub converts a v1beta1 LogSpec to a v1alpha1 LogSpec.
+func convertLogSpecToHub(logSpec LogSpec) cappv1alpha1.LogSpec {
+	return cappv1alpha1.LogSpec{
+		Type:           logSpec.Type,
+		Host:           logSpec.Host,
+		Index:          logSpec.Index,
+		User:           logSpec.User,
+		PasswordSecret: logSpec.PasswordSecret,
+	}
+}
+
+// convertLogSpecFromHub converts a v1alpha1 LogSpec to a v1beta1 LogSpec.
+func convertLogSpecFromHub(logSpec cappv1alpha1.LogSpec) LogSpec {
+	return LogSpec{
+		Type:           logSpec.Type,
+		Host:           logSpec.Host,
+		Index:          logSpec.Index,
+		User:           logSpec.User,
+		PasswordSecret: logSpec.PasswordSecret,
+	}
+}
@@ -0,0 +1,98 @@
+package v1beta1
+
+import (
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func newHubCapp() *cappv1alpha1.Capp {
+	timeout := int64(30)
+	percent := int64(100)
+
+	return &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns", Annotations: map[string]string{"team": "a"}},
+		Spec: cappv1alpha1.CappSpec{
+			ScaleMetric: "rps",
+			Site:        "cluster-a",
+			State:       "enabled",
+			RouteSpec: cappv1alpha1.RouteSpec{
+				Hostname:             "app",
+				TlsEnabled:           true,
+				TagHostnamesEnabled:  true,
+				AdditionalHostnames:  []string{"legacy", "vanity.example.com"},
+				Visibility:           cappv1alpha1.RouteVisibilityExternal,
+				TrafficTargets:       []cappv1alpha1.CappTrafficTarget{{TrafficTarget: knativev1.TrafficTarget{RevisionName: "test-capp-00001", Percent: &percent}}},
+				RouteTimeoutSeconds:  &timeout,
+				DNSRecordTTLSeconds:  &timeout,
+				CertificateIssuerRef: &cappv1alpha1.CertificateIssuerReference{Name: "selfsigned", Kind: "Issuer", Group: "cert-manager.io"},
+			},
+			RolloutSpec: &cappv1alpha1.RolloutSpec{
+				Strategy: cappv1alpha1.RolloutStrategyCanary,
+				Canary:   &cappv1alpha1.CanarySpec{Steps: []int64{10, 50}, StepIntervalSeconds: 60},
+			},
+			LogSpec:     cappv1alpha1.LogSpec{Type: "elastic", Host: "1.2.3.4", Index: "main", PasswordSecret: "credentials"},
+			VolumesSpec: cappv1alpha1.VolumesSpec{NFSVolumes: []cappv1alpha1.NFSVolume{{Name: "data", Server: "nfs", Path: "/data"}}},
+		},
+		Status: cappv1alpha1.CappStatus{ObservedGeneration: 2, URL: "https://app.capp-zone.com"},
+	}
+}
+
+func TestConvertHubRoundTrip(t *testing.T) {
+	hub := newHubCapp()
+
+	spoke := &Capp{}
+	assert.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, CappStateEnabled, spoke.Spec.State)
+	assert.True(t, spoke.Spec.RouteSpec.TLSEnabled)
+	assert.Len(t, spoke.Spec.RouteSpec.TrafficTargets, 1)
+	assert.Len(t, spoke.Spec.LogSpecs, 1)
+	assert.Equal(t, hub.Spec.RolloutSpec, spoke.Spec.RolloutSpec)
+
+	convertedHub := &cappv1alpha1.Capp{}
+	assert.NoError(t, spoke.ConvertTo(convertedHub))
+	assert.Equal(t, hub, convertedHub)
+}
+
+func TestConvertSpokeRoundTrip(t *testing.T) {
+	hub := newHubCapp()
+	spoke := &Capp{}
+	assert.NoError(t, spoke.ConvertFrom(hub))
+
+	canaryPercent := int64(10)
+	spoke.Spec.RouteSpec.TrafficTargets = append(spoke.Spec.RouteSpec.TrafficTargets,
+		cappv1alpha1.CappTrafficTarget{CappRevisionNumber: 2, TrafficTarget: knativev1.TrafficTarget{Percent: &canaryPercent}})
+	spoke.Spec.LogSpecs = []LogSpec{{Type: "elastic", Host: "5.6.7.8", Index: "audit"}}
+
+	convertedHub := &cappv1alpha1.Capp{}
+	assert.NoError(t, spoke.ConvertTo(convertedHub))
+	assert.Len(t, convertedHub.Spec.RouteSpec.TrafficTargets, 2)
+	assert.Equal(t, "5.6.7.8", convertedHub.Spec.LogSpec.Host)
+	assert.Equal(t, hub.Annotations, convertedHub.Annotations)
+
+	convertedSpoke := &Capp{}
+	assert.NoError(t, convertedSpoke.ConvertFrom(convertedHub))
+	assert.Equal(t, spoke, convertedSpoke)
+
+	spoke.Spec.LogSpecs = nil
+	assert.NoError(t, spoke.ConvertTo(convertedHub))
+	assert.Empty(t, convertedHub.Spec.LogSpec)
+}
+
+func TestConvertHubWithDeprecatedTrafficTarget(t *testing.T) {
+	hub := newHubCapp()
+	hub.Spec.RouteSpec.TrafficTarget = hub.Spec.RouteSpec.TrafficTargets[0].TrafficTarget
+	hub.Spec.RouteSpec.TrafficTargets = nil
+
+	spoke := &Capp{}
+	assert.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, []cappv1alpha1.CappTrafficTarget{{TrafficTarget: hub.Spec.RouteSpec.TrafficTarget}}, spoke.Spec.RouteSpec.TrafficTargets)
+
+	convertedHub := &cappv1alpha1.Capp{}
+	assert.NoError(t, spoke.ConvertTo(convertedHub))
+	assert.Equal(t, spoke.Spec.RouteSpec.TrafficTargets, convertedHub.Spec.RouteSpec.TrafficTargets)
+	assert.Empty(t, convertedHub.Spec.RouteSpec.TrafficTarget)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// CappState defines whether the workload of a Capp is running.
+// +kubebuilder:validation:Enum=enabled;disabled
+type CappState string
+
+const (
+	// CappStateEnabled means the workload of the Capp is running.
+	CappStateEnabled CappState = "enabled"
+
+	// CappStateDisabled means the workload of the Capp is not running.
+	CappStateDisabled CappState = "disabled"
+)
+
+// CappSpec defines the desired state of Capp.
+type CappSpec struct {
+	// ScaleMetric defines which metric type is watched by the Autoscaler.
+	// Possible values examples: "concurrency", "rps", "cpu", "memory".
+	// +kubebuilder:default:="concurrency"
+	// +kubebuilder:validation:Enum=cpu;memory;rps;concurrency
+	ScaleMetric string `json:"scaleMetric,omitempty"`
+
+	// Site defines where to deploy the Capp.
+	// It can be a specific cluster or a placement name.
+	// +optional
+	Site string `json:"site,omitempty"`
+
+	// State defines the state of capp.
+	// +optional
+	// +kubebuilder:default:="enabled"
+	State CappState `json:"state,omitempty"`
+
+	// ConfigurationSpec holds the desired state of the Configuration (from the client).
+	ConfigurationSpec knativev1.ConfigurationSpec `json:"configurationSpec"`
+
+	// RouteSpec defines the route specification for the Capp.
+	// +optional
+	RouteSpec RouteSpec `json:"routeSpec,omitempty"`
+
+	// RolloutSpec defines how new revisions of the Capp are rolled out.
+	// If not set, all traffic goes to every new revision once it is ready.
+	// +optional
+	RolloutSpec *cappv1alpha1.RolloutSpec `json:"rolloutSpec,omitempty"`
+
+	// LogSpecs defines the destinations for shipping Capp logs. Only a single destination
+	// is supported for now, so that no destination is silently ignored.
+	// +kubebuilder:validation:MaxItems=1
+	// +optional
+	LogSpecs []LogSpec `json:"logSpecs,omitempty"`
+
+	// VolumesSpec defines the volumes specification for the Capp.
+	// +optional
+	VolumesSpec VolumesSpec `json:"volumesSpec,omitempty"`
+}
+
+// VolumesSpec defines the volumes specification for the Capp.
+type VolumesSpec struct {
+	// NFSVolumes is a list of NFS volumes to be mounted.
+	// +optional
+	NFSVolumes []cappv1alpha1.NFSVolume `json:"nfsVolumes,omitempty"`
+}
+
+// RouteSpec defines the route specification for the Capp.
+type RouteSpec struct {
+	// Hostname is a custom DNS name for the Capp route.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// TLSEnabled determines whether to enable TLS for the Capp route.
+	// +optional
+	TLSEnabled bool `json:"tlsEnabled,omitempty"`
+
+	// TagHostnamesEnabled gives every tagged traffic target of the Capp route its own hostname,
+	// "<tag>-<hostname>", with its own DNS record, DomainMapping and Certificate. It requires Hostname to be set.
+	// +optional
+	TagHostnamesEnabled bool `json:"tagHostnamesEnabled,omitempty"`
+
+	// AdditionalHostnames are custom DNS names for the Capp route in addition to Hostname, such as
+	// vanity or legacy names. Each gets its own DNS record and DomainMapping, and they are all added
+	// to the Certificate of Hostname. They require Hostname to be set.
+	// +optional
+	AdditionalHostnames []string `json:"additionalHostnames,omitempty"`
+
+	// Visibility is the visibility of the Capp route. A "cluster-local" Capp is only reachable from within
+	// the cluster on its internal URL, and can not have custom hostnames. Defaults to "external".
+	// +kubebuilder:validation:Enum=external;cluster-local
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// TrafficTargets holds the entries of the routing table for the Capp route. The percentages
+	// of the entries must add up to 100. If empty, all traffic goes to the latest ready revision.
+	// +optional
+	TrafficTargets []cappv1alpha1.CappTrafficTarget `json:"trafficTargets,omitempty"`
+
+	// RouteTimeoutSeconds is the maximum duration in seconds
+	// that the request instance is allowed to respond to a request.
+	// +optional
+	RouteTimeoutSeconds *int64 `json:"routeTimeoutSeconds,omitempty"`
+
+	// DNSRecordTTLSeconds is the TTL in seconds of the DNS records of the hostnames of the Capp.
+	// Defaults to the ttl of the DNS ConfigMap, or to the default of the DNS backend if it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DNSRecordTTLSeconds *int64 `json:"dnsRecordTTLSeconds,omitempty"`
+
+	// CertificateIssuerRef is the issuer of the Certificate of the Capp route. It requires TLSEnabled
+	// to be set, and must be an Issuer in the namespace of the Capp. Defaults to the issuer of the zone
+	// of Hostname from the DNS ConfigMap.
+	// +optional
+	CertificateIssuerRef *cappv1alpha1.CertificateIssuerReference `json:"certificateIssuerRef,omitempty"`
+}
+
+// LogSpec defines a destination for shipping Capp logs.
+type LogSpec struct {
+	// Type defines where to send the Capp logs.
+	// +kubebuilder:validation:Enum=elastic
+	Type string `json:"type"`
+
+	// Host defines Elasticsearch or Splunk host.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Index defines the index name to write events to.
+	// +optional
+	Index string `json:"index,omitempty"`
+
+	// User defines a User for authentication.
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// PasswordSecret defines the name of the secret
+	// containing the password for authentication.
+	// +optional
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".status.applicationLinks.site",description="cluster of the resource"
+// +kubebuilder:printcolumn:name="Custom URL",type="string",JSONPath=".spec.routeSpec.hostname",description="shorten url"
+// +kubebuilder:printcolumn:name="AutoScale Type",type="string",JSONPath=".spec.scaleMetric",description="autoscale metric"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="effective public url"
+// +kubebuilder:printcolumn:name="Internal URL",type="string",JSONPath=".status.internalURL",description="cluster-internal url",priority=1
+// +kubebuilder:printcolumn:name="Latest Created",type="string",JSONPath=".status.latestCreatedRevisionName",description="latest created revision",priority=1
+// +kubebuilder:printcolumn:name="Latest Ready",type="string",JSONPath=".status.latestReadyRevisionName",description="latest ready revision"
+// +kubebuilder:printcolumn:name="Rollout",type="string",JSONPath=".status.rolloutStatus.phase",description="phase of the latest rollout",priority=1
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="readiness of the capp"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",description="reason of the readiness of the capp"
+//+kubebuilder:subresource:status
+
+// Capp is the Schema for the capps API.
+type Capp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// CappSpec defines the desired state of Capp.
+	// +optional
+	Spec CappSpec `json:"spec,omitempty"`
+
+	// CappStatus defines the observed state of Capp. Its shape is the same in all
+	// versions of the Capp API.
+	// +optional
+	Status cappv1alpha1.CappStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CappList contains a list of Capp.
+type CappList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Capp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Capp{}, &CappList{})
+}
@@ -0,0 +1,192 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"github.com/dana-team/container-app-operator/api/v1alpha1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Capp) DeepCopyInto(out *Capp) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capp.
+func (in *Capp) DeepCopy() *Capp {
+	if in == nil {
+		return nil
+	}
+	out := new(Capp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Capp) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappList) DeepCopyInto(out *CappList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Capp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappList.
+func (in *CappList) DeepCopy() *CappList {
+	if in == nil {
+		return nil
+	}
+	out := new(CappList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CappList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappSpec) DeepCopyInto(out *CappSpec) {
+	*out = *in
+	in.ConfigurationSpec.DeepCopyInto(&out.ConfigurationSpec)
+	in.RouteSpec.DeepCopyInto(&out.RouteSpec)
+	if in.RolloutSpec != nil {
+		in, out := &in.RolloutSpec, &out.RolloutSpec
+		*out = new(v1alpha1.RolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogSpecs != nil {
+		in, out := &in.LogSpecs, &out.LogSpecs
+		*out = make([]LogSpec, len(*in))
+		copy(*out, *in)
+	}
+	in.VolumesSpec.DeepCopyInto(&out.VolumesSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappSpec.
+func (in *CappSpec) DeepCopy() *CappSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CappSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogSpec) DeepCopyInto(out *LogSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSpec.
+func (in *LogSpec) DeepCopy() *LogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+	*out = *in
+	if in.AdditionalHostnames != nil {
+		in, out := &in.AdditionalHostnames, &out.AdditionalHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrafficTargets != nil {
+		in, out := &in.TrafficTargets, &out.TrafficTargets
+		*out = make([]v1alpha1.CappTrafficTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RouteTimeoutSeconds != nil {
+		in, out := &in.RouteTimeoutSeconds, &out.RouteTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DNSRecordTTLSeconds != nil {
+		in, out := &in.DNSRecordTTLSeconds, &out.DNSRecordTTLSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CertificateIssuerRef != nil {
+		in, out := &in.CertificateIssuerRef, &out.CertificateIssuerRef
+		*out = new(v1alpha1.CertificateIssuerReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
+func (in *RouteSpec) DeepCopy() *RouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumesSpec) DeepCopyInto(out *VolumesSpec) {
+	*out = *in
+	if in.NFSVolumes != nil {
+		in, out := &in.NFSVolumes, &out.NFSVolumes
+		*out = make([]v1alpha1.NFSVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumesSpec.
+func (in *VolumesSpec) DeepCopy() *VolumesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -18,16 +18,29 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	dnsrecordsetv1alpha1 "github.com/dana-team/provider-dns/apis/recordset/v1alpha1"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	cappv1beta1 "github.com/dana-team/container-app-operator/api/v1beta1"
 	cappcontroller "github.com/dana-team/container-app-operator/internal/kinds/capp/controllers"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	cappwebhooks "github.com/dana-team/container-app-operator/internal/kinds/capp/webhooks"
+	"github.com/dana-team/container-app-operator/internal/kinds/capprevision/actionmanagers"
 	crcontroller "github.com/dana-team/container-app-operator/internal/kinds/capprevision/controllers"
+	crwebhooks "github.com/dana-team/container-app-operator/internal/kinds/capprevision/webhooks"
+	routercontroller "github.com/dana-team/container-app-operator/internal/kinds/capprouter/controllers"
+	routerwebhooks "github.com/dana-team/container-app-operator/internal/kinds/capprouter/webhooks"
 	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
 	"github.com/go-logr/zapr"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
@@ -35,16 +48,20 @@ import (
 	"go.elastic.co/ecszap"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	runtimezap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -58,10 +75,14 @@ func init() {
 	utilruntime.Must(knativev1.AddToScheme(scheme))
 	utilruntime.Must(loggingv1beta1.AddToScheme(scheme))
 	utilruntime.Must(knativev1beta1.AddToScheme(scheme))
+	utilruntime.Must(autoscalingv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(cappv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(cappv1beta1.AddToScheme(scheme))
 	utilruntime.Must(nfspvcv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(cmapi.AddToScheme(scheme))
 	utilruntime.Must(dnsrecordv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dnsrecordsetv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.Install(scheme))
 
 	//+kubebuilder:scaffold:scheme
 }
@@ -77,17 +98,91 @@ func initEcsLogger() {
 	logf.SetLogger(zapr.NewLogger(logger))
 }
 
+// parseNamespacedName parses a "namespace/name" flag value.
+func parseNamespacedName(value string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("%q is not of the form namespace/name", value)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// parseRoutingConfig validates the routing flags and returns the routing configuration of the Capp controller.
+func parseRoutingConfig(backend, gateway, gatewayBackend, routeIngress string, onOpenshift bool) (rmanagers.RoutingConfig, error) {
+	routing := rmanagers.RoutingConfig{Backend: backend}
+	if !slices.Contains(rmanagers.RoutingBackends, backend) {
+		return routing, fmt.Errorf("routing backend %q is not one of %v", backend, rmanagers.RoutingBackends)
+	}
+
+	var err error
+	switch backend {
+	case rmanagers.RoutingBackendOpenShiftRoute:
+		if !onOpenshift {
+			return routing, fmt.Errorf("routing backend %q is only supported on OpenShift", backend)
+		}
+		if routing.RouteIngress, err = parseNamespacedName(routeIngress); err != nil {
+			return routing, fmt.Errorf("invalid route ingress: %w", err)
+		}
+		return routing, nil
+	case rmanagers.RoutingBackendGatewayAPI:
+	default:
+		return routing, nil
+	}
+
+	if routing.Gateway, err = parseNamespacedName(gateway); err != nil {
+		return routing, fmt.Errorf("invalid gateway: %w", err)
+	}
+	if routing.GatewayBackend, err = parseNamespacedName(gatewayBackend); err != nil {
+		return routing, fmt.Errorf("invalid gateway backend: %w", err)
+	}
+
+	return routing, nil
+}
+
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var ecsLogging bool
+	var revisionsToKeep int
+	var revisionMaxAge time.Duration
+	var routingBackend string
+	var gateway string
+	var gatewayBackend string
+	var routeIngress string
+	var clusterDomain string
+	var maintenanceService string
+	var maintenanceServicePort int
+	var rolloutPrometheusAddress string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&ecsLogging, "ecs-logging", true, "Display controller logs in ecs format.")
+	flag.IntVar(&revisionsToKeep, "revisions-to-keep", actionmanagers.DefaultRevisionsToKeep,
+		"The number of unpinned CappRevisions kept for every Capp, unless overridden on the Capp.")
+	flag.DurationVar(&revisionMaxAge, "revision-max-age", 0,
+		"The age after which unpinned CappRevisions are pruned, unless overridden on the Capp. Zero disables age-based pruning.")
+	flag.StringVar(&routingBackend, "routing-backend", rmanagers.RoutingBackendDomainMapping,
+		fmt.Sprintf("The backend used to route the custom hostnames of Capps, one of %v.", rmanagers.RoutingBackends))
+	flag.StringVar(&gateway, "gateway", "",
+		"The namespace/name of the Gateway which HTTPRoutes are attached to when the routing backend is gatewayAPI.")
+	flag.StringVar(&gatewayBackend, "gateway-backend", "kourier-system/kourier-internal",
+		"The namespace/name of the Knative ingress Service which HTTPRoutes send requests to when the routing backend is gatewayAPI.")
+	flag.StringVar(&clusterDomain, "cluster-domain", rmanagers.DefaultClusterDomain,
+		"The DNS domain of the cluster, which the internal hostnames of Services end with.")
+	flag.StringVar(&routeIngress, "route-ingress", "knative-serving-ingress/kourier",
+		"The namespace/name of the Knative ingress Service which OpenShift Routes send requests to when the routing backend is openshiftRoute.")
+	flag.StringVar(&maintenanceService, "maintenance-service", "",
+		"The namespace/name of the Service of the maintenance page which the hostnames of disabled Capps are routed to. "+
+			"The hostnames of disabled Capps are not routed if it is empty.")
+	flag.IntVar(&maintenanceServicePort, "maintenance-service-port", rmanagers.DefaultMaintenanceServicePort,
+		"The port of the Service of the maintenance page.")
+	flag.StringVar(&rolloutPrometheusAddress, "rollout-prometheus-address", "",
+		"The address of the Prometheus-compatible query API which the metrics of canary rollouts are queried from. "+
+			"Rollout analyses fail if it is empty.")
 
 	flag.Parse()
 
@@ -103,6 +198,8 @@ func main() {
 		LeaderElectionID:       "c1382367.dana.io",
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress: probeAddr,
+		// The DNSEndpoints of external-dns are unstructured, and are read from the cache like the other DNS records.
+		Client: client.Options{Cache: &client.CacheOptions{Unstructured: true}},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -119,24 +216,97 @@ func main() {
 		initOpenshiftSchemes()
 	}
 
+	routing, err := parseRoutingConfig(routingBackend, gateway, gatewayBackend, routeIngress, onOpenshift)
+	if err != nil {
+		setupLog.Error(err, "invalid routing configuration")
+		os.Exit(1)
+	}
+	routing.ClusterDomain = clusterDomain
+
+	retentionPolicy := actionmanagers.RetentionPolicy{RevisionsToKeep: revisionsToKeep, MaxAge: revisionMaxAge}
+	if err := retentionPolicy.Validate(); err != nil {
+		setupLog.Error(err, "invalid CappRevision retention")
+		os.Exit(1)
+	}
+
+	if maintenanceService != "" {
+		if routing.MaintenanceService, err = parseNamespacedName(maintenanceService); err != nil {
+			setupLog.Error(err, "invalid maintenance service")
+			os.Exit(1)
+		}
+	}
+
+	if maintenanceServicePort < 1 || maintenanceServicePort > 65535 {
+		setupLog.Error(fmt.Errorf("port %d is not between 1 and 65535", maintenanceServicePort), "invalid maintenance service port")
+		os.Exit(1)
+	}
+	routing.MaintenanceServicePort = int32(maintenanceServicePort)
+
+	if err := rollout.ValidatePrometheusAddress(rolloutPrometheusAddress); err != nil {
+		setupLog.Error(err, "invalid rollout Prometheus address")
+		os.Exit(1)
+	}
+
 	if err = (&cappcontroller.CappReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		OnOpenshift:   onOpenshift,
-		EventRecorder: mgr.GetEventRecorderFor("container-app-controller"),
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		OnOpenshift:     onOpenshift,
+		EventRecorder:   mgr.GetEventRecorderFor("container-app-controller"),
+		Routing:         routing,
+		RolloutAnalyzer: rollout.NewAnalyzer(rolloutPrometheusAddress),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Capp")
 		os.Exit(1)
 	}
 
 	if err = (&crcontroller.CappRevisionReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		EventRecorder:   mgr.GetEventRecorderFor("capprevision-controller"),
+		RetentionPolicy: retentionPolicy,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CappRevision")
+		os.Exit(1)
+	}
+
+	if err = (&routercontroller.CappRouterReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
-		EventRecorder: mgr.GetEventRecorderFor("capprevision-controller"),
+		EventRecorder: mgr.GetEventRecorderFor("capprouter-controller"),
+		Routing:       routing,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "CappRevision")
+		setupLog.Error(err, "unable to create controller", "controller", "CappRouter")
 		os.Exit(1)
 	}
+
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		// Registering the Capp webhooks also serves the conversion webhook between the Capp API versions.
+		if err = (&cappwebhooks.CappDefaulter{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Capp")
+			os.Exit(1)
+		}
+
+		if err = (&cappwebhooks.CappValidator{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Capp")
+			os.Exit(1)
+		}
+
+		if err = (&crwebhooks.CappRevisionValidator{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CappRevision")
+			os.Exit(1)
+		}
+
+		if err = (&routerwebhooks.CappRouterValidator{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CappRouter")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
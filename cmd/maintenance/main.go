@@ -0,0 +1,64 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command maintenance serves the maintenance page which the hostnames of disabled Capps are routed to.
+// It runs in its own Deployment, separately from the controller-manager.
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/maintenance"
+	ctrl "sigs.k8s.io/controller-runtime"
+	runtimezap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var setupLog = ctrl.Log.WithName("setup")
+
+func main() {
+	var page string
+	server := maintenance.Server{}
+	flag.StringVar(&server.BindAddress, "bind-address", ":8082", "The address the maintenance page is served on.")
+	flag.IntVar(&server.StatusCode, "status-code", maintenance.DefaultStatusCode,
+		"The HTTP status code of the maintenance page.")
+	flag.DurationVar(&server.RetryAfter, "retry-after", maintenance.DefaultRetryAfter,
+		"The time clients of the maintenance page are asked to wait before retrying. Zero omits the Retry-After header.")
+	flag.StringVar(&page, "page", "",
+		"The path of the HTML file served as the maintenance page. A built-in page is served if it is empty.")
+	flag.Parse()
+
+	ctrl.SetLogger(runtimezap.New())
+
+	if err := server.Validate(); err != nil {
+		setupLog.Error(err, "invalid maintenance page configuration")
+		os.Exit(1)
+	}
+
+	if page != "" {
+		var err error
+		if server.Page, err = os.ReadFile(page); err != nil {
+			setupLog.Error(err, "unable to read maintenance page")
+			os.Exit(1)
+		}
+	}
+
+	setupLog.Info("serving maintenance page", "address", server.BindAddress)
+	if err := server.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem serving maintenance page")
+		os.Exit(1)
+	}
+}
@@ -1,9 +1,15 @@
 package autoscale
 
 import (
+	"context"
+	"fmt"
+
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -17,6 +23,7 @@ const (
 	cpuScaleKey               = "cpu"
 	memoryScaleKey            = "memory"
 	concurrencyScaleKey       = "concurrency"
+	DefaultAutoScaleCM        = "autoscale-defaults"
 )
 
 var TargetDefaultValues = map[string]string{
@@ -51,6 +58,16 @@ func SetAutoScaler(capp cappv1alpha1.Capp, defaults map[string]string) map[strin
 	return autoScaleAnnotations
 }
 
+// GetAutoScaleDefaults returns the data of the autoscale defaults ConfigMap from the operator namespace.
+func GetAutoScaleDefaults(ctx context.Context, k8sClient client.Client) (map[string]string, error) {
+	defaultCM := corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: utils.CappNS, Name: DefaultAutoScaleCM}, &defaultCM); err != nil {
+		return nil, fmt.Errorf("could not fetch configMap %q from namespace %q: %w", DefaultAutoScaleCM, utils.CappNS, err)
+	}
+
+	return defaultCM.Data, nil
+}
+
 // Determines the autoscaling class based on the metric provided. Returns "kpa.autoscaling.knative.dev" if the metric is in KPAMetrics, "hpa.autoscaling.knative.dev" otherwise.
 func getAutoScaleClassByMetric(metric string) string {
 	if slices.Contains(KPAMetrics, metric) {
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"time"
 
@@ -9,9 +10,8 @@ import (
 
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
-	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
-
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	routev1 "github.com/openshift/api/route/v1"
 
 	"k8s.io/apimachinery/pkg/types"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -32,14 +32,18 @@ import (
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 const (
 	cappControllerName = "CappController"
 	RequeueTime        = 5 * time.Second
+	// hostnamesIndexKey is the field index of the hostnames in the status of Capps.
+	hostnamesIndexKey = "status.routeStatus.hostnames"
 )
 
 // CappReconciler reconciles a Capp object
@@ -49,29 +53,51 @@ type CappReconciler struct {
 	Scheme        *runtime.Scheme
 	OnOpenshift   bool
 	EventRecorder record.EventRecorder
+	Routing       rmanagers.RoutingConfig
+	// RolloutAnalyzer runs the analyses of canary rollouts in the background.
+	RolloutAnalyzer *rollout.Analyzer
 }
 
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps/finalizers,verbs=update
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=capprevisions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=hostnameclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=hostnameclaims/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=domainmappings,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=revisions,verbs=get;list;watch;update;create
+// +kubebuilder:rbac:groups=autoscaling.internal.knative.dev,resources=podautoscalers,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=syslogngflows,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=syslogngoutputs,verbs=get;list;watch;update;create;delete
-// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;update;create
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;create;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;update;create;patch
 // +kubebuilder:rbac:groups="events.k8s.io",resources=events,verbs=get;list;watch;update;create;patch;
 // +kubebuilder:rbac:groups="nfspvc.dana.io",resources=nfspvcs,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups="record.dns.crossplane.io",resources=cnamerecords,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="recordset.dns.crossplane.io",resources=arecordsets;aaaarecordsets;txtrecordsets,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="externaldns.k8s.io",resources=dnsendpoints,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes,verbs=get;list;watch;update;create;delete
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager. HTTPRoutes and OpenShift Routes are only
+// watched when they are used for routing, and the DNS records of the DNS backends are only watched when
+// their APIs are installed, as their APIs may not be installed otherwise.
 func (r *CappReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.RolloutAnalyzer == nil {
+		r.RolloutAnalyzer = rollout.NewAnalyzer("")
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cappv1alpha1.Capp{}, hostnamesIndexKey, indexCappHostnames); err != nil {
+		return err
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&cappv1alpha1.Capp{}).
 		Named(cappControllerName).
 		Watches(
@@ -89,11 +115,6 @@ func (r *CappReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
-		Watches(
-			&dnsrecordv1alpha1.CNAMERecord{},
-			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
-			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
-		).
 		Watches(
 			&loggingv1beta1.SyslogNGOutput{},
 			handler.EnqueueRequestsFromMapFunc(r.findCappFromEvent),
@@ -104,7 +125,42 @@ func (r *CappReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.findCappFromEvent),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
-		Complete(r)
+		Watches(
+			&cappv1alpha1.HostnameClaim{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappsFromHostnameClaim),
+			builder.WithPredicates(rmanagers.HostnameClaimReleasedPredicate),
+		)
+
+	dnsRecordObjects, err := rmanagers.InstalledDNSRecordObjects(mgr.GetRESTMapper())
+	if err != nil {
+		return err
+	}
+
+	for _, dnsRecordObject := range dnsRecordObjects {
+		controllerBuilder = controllerBuilder.Watches(
+			dnsRecordObject,
+			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+	}
+
+	if r.Routing.Backend == rmanagers.RoutingBackendGatewayAPI {
+		controllerBuilder = controllerBuilder.Watches(
+			&gatewayv1.HTTPRoute{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+	}
+
+	if r.Routing.Backend == rmanagers.RoutingBackendOpenShiftRoute {
+		controllerBuilder = controllerBuilder.Watches(
+			&routev1.Route{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+	}
+
+	return controllerBuilder.Complete(r)
 }
 
 // findCappFromKnative maps reconciliation requests to Capp reconciliation requests.
@@ -117,16 +173,62 @@ func (r *CappReconciler) findCappFromEvent(ctx context.Context, object client.Ob
 }
 
 // findCappFromDomainMapping maps reconciliation requests to Capp reconciliation requests based on hostname.
+// Objects which are not in the namespace of their Capp carry its namespace in a label.
 func (r *CappReconciler) findCappFromHostname(ctx context.Context, object client.Object) []reconcile.Request {
 	labels := object.GetLabels()
 
+	// Objects of CappRouters are not labelled with a Capp.
+	name, ok := labels[utils.CappResourceKey]
+	if !ok {
+		return nil
+	}
+
+	namespace := object.GetNamespace()
+	if cappNamespace, ok := labels[utils.CappNamespaceKey]; ok {
+		namespace = cappNamespace
+	}
+
 	request := reconcile.Request{NamespacedName: types.NamespacedName{
-		Namespace: object.GetNamespace(),
-		Name:      labels[utils.CappResourceKey]}}
+		Namespace: namespace,
+		Name:      name}}
 
 	return []reconcile.Request{request}
 }
 
+// indexCappHostnames returns the hostnames in the status of a Capp, by which Capps are indexed.
+func indexCappHostnames(object client.Object) []string {
+	capp := object.(*cappv1alpha1.Capp)
+
+	hostnames := make([]string, 0, len(capp.Status.RouteStatus.Hostnames))
+	for _, hostnameStatus := range capp.Status.RouteStatus.Hostnames {
+		hostnames = append(hostnames, hostnameStatus.Hostname)
+	}
+
+	return hostnames
+}
+
+// findCappsFromHostnameClaim maps a released HostnameClaim to reconciliation requests of the Capps
+// which could not claim its hostname, so that they may claim it instead.
+func (r *CappReconciler) findCappsFromHostnameClaim(ctx context.Context, object client.Object) []reconcile.Request {
+	capps := cappv1alpha1.CappList{}
+	if err := r.List(ctx, &capps, client.MatchingFields{hostnamesIndexKey: object.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list Capps")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, capp := range capps.Items {
+		for _, hostnameStatus := range capp.Status.RouteStatus.Hostnames {
+			if hostnameStatus.Hostname == object.GetName() && hostnameStatus.ClaimedBy != "" {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
 func (r *CappReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithValues("CappName", req.Name, "CappNamespace", req.Namespace)
 	logger.Info("Starting Reconcile")
@@ -140,13 +242,16 @@ func (r *CappReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	}
 
 	resourceManagers := map[string]rmanagers.ResourceManager{
-		rmanagers.KnativeServing: rmanagers.KnativeServiceManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
-		rmanagers.DNSRecord:      rmanagers.DNSRecordManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
-		rmanagers.Certificate:    rmanagers.CertificateManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
-		rmanagers.DomainMapping:  rmanagers.KnativeDomainMappingManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
-		rmanagers.SyslogNGFlow:   rmanagers.SyslogNGFlowManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
-		rmanagers.SyslogNGOutput: rmanagers.SyslogNGOutputManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
-		rmanagers.NfsPVC:         rmanagers.NFSPVCManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
+		rmanagers.KnativeServing:     rmanagers.KnativeServiceManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
+		rmanagers.DNSRecord:          rmanagers.DNSRecordManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
+		rmanagers.Certificate:        rmanagers.CertificateManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, Routing: r.Routing},
+		rmanagers.DomainMapping:      rmanagers.KnativeDomainMappingManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, Routing: r.Routing},
+		rmanagers.HTTPRoute:          rmanagers.HTTPRouteManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, Routing: r.Routing},
+		rmanagers.OpenShiftRoute:     rmanagers.OpenShiftRouteManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, Routing: r.Routing},
+		rmanagers.MaintenanceService: rmanagers.MaintenanceServiceManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, Routing: r.Routing},
+		rmanagers.SyslogNGFlow:       rmanagers.SyslogNGFlowManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
+		rmanagers.SyslogNGOutput:     rmanagers.SyslogNGOutputManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
+		rmanagers.NfsPVC:             rmanagers.NFSPVCManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
 	}
 
 	err, deleted := finalizer.HandleResourceDeletion(ctx, capp, r.Client, resourceManagers)
@@ -162,27 +267,47 @@ func (r *CappReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, fmt.Errorf("failed to ensure finalizer in Capp: %s", err.Error())
 	}
 
-	if err := r.SyncApplication(ctx, capp, resourceManagers, logger); err != nil {
+	result, err := r.SyncApplication(ctx, capp, resourceManagers, logger)
+	if err != nil {
 		if errors.IsConflict(err) {
 			logger.Info(fmt.Sprintf("Conflict detected, requeuing: %s", err.Error()))
 			return ctrl.Result{RequeueAfter: RequeueTime}, nil
 		}
+		if stderrors.Is(err, rmanagers.ErrHostnameOwnershipUnknown) {
+			logger.Info(fmt.Sprintf("Hostname ownership is unknown, requeuing: %s", err.Error()))
+			return ctrl.Result{RequeueAfter: RequeueTime}, nil
+		}
 		return ctrl.Result{}, fmt.Errorf("failed to sync Capp: %s", err.Error())
 	}
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 // SyncApplication manages the lifecycle of Capp.
 // It ensures all manifests are applied according to the specification and synchronizes the status accordingly.
-func (r *CappReconciler) SyncApplication(ctx context.Context, capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager, logger logr.Logger) error {
+// The rollout is progressed and its status saved before the manifests are applied, so a rollout transition is not
+// lost if applying a manifest fails. The returned result requeues the Capp when the next step of its rollout is due.
+// When the ownership of a hostname is unknown, the other manifests and the status are still synchronized before
+// ErrHostnameOwnershipUnknown is returned.
+func (r *CappReconciler) SyncApplication(ctx context.Context, capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager, logger logr.Logger) (ctrl.Result, error) {
+	rolloutManager := rollout.Manager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, Analyzer: r.RolloutAnalyzer}
+	rolloutStatus, requeueAfter, err := rolloutManager.Progress(capp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	capp.Status.RolloutStatus = rolloutStatus
+
+	var ownershipErr error
 	for _, manager := range resourceManagers {
 		if err := manager.Manage(capp); err != nil {
-			return err
+			if !stderrors.Is(err, rmanagers.ErrHostnameOwnershipUnknown) {
+				return ctrl.Result{}, err
+			}
+			ownershipErr = err
 		}
 	}
 
 	if err := status.SyncStatus(ctx, capp, logger, r.Client, r.OnOpenshift, resourceManagers); err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
-	return nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, ownershipErr
 }
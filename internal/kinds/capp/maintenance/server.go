@@ -0,0 +1,111 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultStatusCode is the default HTTP status code of the maintenance page.
+	DefaultStatusCode = http.StatusServiceUnavailable
+
+	// DefaultRetryAfter is the default time clients are asked to wait before retrying.
+	DefaultRetryAfter = 5 * time.Minute
+
+	minStatusCode = 200
+	maxStatusCode = 599
+
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 10 * time.Second
+
+	defaultPage = `<!DOCTYPE html>
+<html>
+<head><title>Under maintenance</title></head>
+<body>
+<h1>Under maintenance</h1>
+<p>This application is currently disabled. Please try again later.</p>
+</body>
+</html>
+`
+)
+
+// Server serves the maintenance page which the hostnames of disabled Capps are routed to. It answers every
+// request with the page and the configured status code, together with a Retry-After header if it is set.
+// It runs in its own Deployment, so that disabled Capps do not send their traffic to the controller-manager.
+type Server struct {
+	// BindAddress is the address the maintenance page is served on.
+	BindAddress string
+
+	// StatusCode is the HTTP status code of the maintenance page.
+	StatusCode int
+
+	// RetryAfter is the time clients are asked to wait before retrying. The Retry-After header is not sent if it is zero.
+	RetryAfter time.Duration
+
+	// Page is the HTML of the maintenance page. A built-in page is served if it is empty.
+	Page []byte
+}
+
+// Validate returns an error if the status code is not a valid final HTTP status code, as the server would
+// otherwise panic when writing it, or if the time clients are asked to wait before retrying is negative.
+func (s Server) Validate() error {
+	if s.StatusCode != 0 && (s.StatusCode < minStatusCode || s.StatusCode > maxStatusCode) {
+		return fmt.Errorf("status code %d is not between %d and %d", s.StatusCode, minStatusCode, maxStatusCode)
+	}
+
+	if s.RetryAfter < 0 {
+		return fmt.Errorf("retry after %s is negative", s.RetryAfter)
+	}
+
+	return nil
+}
+
+// ServeHTTP responds to a request with the maintenance page.
+func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	page := s.Page
+	if len(page) == 0 {
+		page = []byte(defaultPage)
+	}
+
+	statusCode := s.StatusCode
+	if statusCode == 0 {
+		statusCode = DefaultStatusCode
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if s.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.RetryAfter.Seconds())))
+	}
+	w.WriteHeader(statusCode)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(page)
+	}
+}
+
+// Start serves the maintenance page until the context is done.
+func (s Server) Start(ctx context.Context) error {
+	server := &http.Server{Addr: s.BindAddress, Handler: s, ReadHeaderTimeout: readHeaderTimeout}
+
+	errs := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
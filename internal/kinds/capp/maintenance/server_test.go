@@ -0,0 +1,39 @@
+package maintenance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeHTTP(t *testing.T) {
+	server := Server{RetryAfter: 2 * time.Minute}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "120", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, defaultPage, recorder.Body.String())
+
+	server = Server{StatusCode: http.StatusOK, Page: []byte("<h1>Back soon</h1>")}
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Retry-After"))
+	assert.Equal(t, "<h1>Back soon</h1>", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, "/", nil))
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Server{}.Validate())
+	assert.NoError(t, Server{StatusCode: http.StatusOK, RetryAfter: time.Minute}.Validate())
+	assert.Error(t, Server{StatusCode: http.StatusContinue}.Validate())
+	assert.Error(t, Server{StatusCode: 1000}.Validate())
+	assert.Error(t, Server{StatusCode: -1}.Validate())
+	assert.Error(t, Server{RetryAfter: -time.Second}.Validate())
+}
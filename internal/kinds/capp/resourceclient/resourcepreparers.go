@@ -3,11 +3,13 @@ package resourceclient
 import (
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
-	dnsvrecord1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // GetBareKSVC returns a KSVC object with only ObjectMeta set.
@@ -70,11 +72,32 @@ func GetBareSyslogNGOutput(name, namespace string) loggingv1beta1.SyslogNGOutput
 	}
 }
 
-// GetBareDNSRecord returns a DNSRecord object with only ObjectMeta set.
-func GetBareDNSRecord(name string) dnsvrecord1alpha1.CNAMERecord {
-	return dnsvrecord1alpha1.CNAMERecord{
+// GetBareHTTPRoute returns an HTTPRoute object with only ObjectMeta set.
+func GetBareHTTPRoute(name, namespace string) gatewayv1.HTTPRoute {
+	return gatewayv1.HTTPRoute{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+// GetBareOpenShiftRoute returns an OpenShift Route object with only ObjectMeta set.
+func GetBareOpenShiftRoute(name, namespace string) routev1.Route {
+	return routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+// GetBareService returns a Service object with only ObjectMeta set.
+func GetBareService(name, namespace string) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
 		},
 	}
 }
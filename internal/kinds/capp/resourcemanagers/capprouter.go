@@ -0,0 +1,164 @@
+package resourcemanagers
+
+import (
+	"context"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// RouterCapp returns the Capp which stands for a CappRouter when managing the DNS record and Certificate of
+// its hostname, so that the DNSRecordManager and CertificateManager can be used with utils.RouterResourceKey
+// as their ParentKey. Its URL is the previous hostname of the CappRouter, so that the DNS record and
+// Certificate of the previous hostname are removed once the hostname changes, and its DNS backend is the DNS backend
+// of the CappRouter, so that its DNS record is removed once the DNS backend changes.
+func RouterCapp(router cappv1alpha1.CappRouter) cappv1alpha1.Capp {
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: router.Name, Namespace: router.Namespace},
+		Spec: cappv1alpha1.CappSpec{
+			RouteSpec: cappv1alpha1.RouteSpec{Hostname: router.Spec.Hostname, TlsEnabled: router.Spec.TlsEnabled},
+		},
+	}
+
+	if router.Status.Hostname != "" {
+		capp.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP(router.Status.Hostname)
+	}
+	capp.Status.RouteStatus.DNSRecordObjectStatus.Backend = router.Status.DNSBackend
+
+	return capp
+}
+
+// CappRouterManager routes the path prefixes of the hostname of a CappRouter to its Capps using a single
+// HTTPRoute, which has a rule for every path prefix. Like the hostnames of Capps routed by the HTTPRouteManager,
+// TLS is terminated by the HTTPS listeners of the Gateway.
+type CappRouterManager struct {
+	Ctx           context.Context
+	K8sclient     client.Client
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+	Routing       RoutingConfig
+}
+
+// httpRouteManager returns the HTTPRouteManager whose building blocks are used for the CappRouter.
+func (m CappRouterManager) httpRouteManager() HTTPRouteManager {
+	return HTTPRouteManager{Ctx: m.Ctx, K8sclient: m.K8sclient, Log: m.Log, EventRecorder: m.EventRecorder, Routing: m.Routing}
+}
+
+// routerLabels returns the labels of the resources of a CappRouter.
+func routerLabels(router cappv1alpha1.CappRouter) map[string]string {
+	return map[string]string{
+		utils.RouterResourceKey: router.Name,
+		utils.ManagedByLabelKey: utils.CappKey,
+	}
+}
+
+// prepareResource prepares the HTTPRoute of a CappRouter, which sends the requests of every path prefix
+// to the Knative Service of its Capp, using the route timeout of the Capp if it exists.
+func (m CappRouterManager) prepareResource(router cappv1alpha1.CappRouter, hostname string) (gatewayv1.HTTPRoute, error) {
+	httpRouteManager := m.httpRouteManager()
+	pathType := gatewayv1.PathMatchPathPrefix
+
+	var rules []gatewayv1.HTTPRouteRule
+	for _, route := range router.Spec.Routes {
+		pathPrefix := route.PathPrefix
+		rule := httpRouteManager.prepareRule(route.CappName, router.Namespace)
+		rule.Matches = []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: &pathType, Value: &pathPrefix}}}
+
+		capp := cappv1alpha1.Capp{}
+		if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: router.Namespace, Name: route.CappName}, &capp); err != nil {
+			if !errors.IsNotFound(err) {
+				return gatewayv1.HTTPRoute{}, fmt.Errorf("failed to get Capp %q: %w", route.CappName, err)
+			}
+		}
+		rule.Timeouts = prepareTimeouts(capp.Spec.RouteSpec.RouteTimeoutSeconds)
+
+		rules = append(rules, rule)
+	}
+
+	return httpRouteManager.prepareHTTPRoute(hostname, router.Namespace, routerLabels(router), rules), nil
+}
+
+// IsRequired returns a boolean indicating whether CappRouters can be routed, which requires the Gateway API routing backend.
+func (m CappRouterManager) IsRequired() bool {
+	return isRoutingBackend(m.Routing.Backend, RoutingBackendGatewayAPI)
+}
+
+// Manage creates or updates the HTTPRoute of a CappRouter, and removes those of its previous hostnames.
+// The HTTPRoute is removed instead when the namespace of the CappRouter may not use the zone of its hostname,
+// or when the hostname is owned by another Capp, CappRouter or cluster.
+func (m CappRouterManager) Manage(router cappv1alpha1.CappRouter) error {
+	dnsConfig, err := utils.GetDNSConfig(m.Ctx, m.K8sclient)
+	if err != nil {
+		return err
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	if err != nil {
+		return err
+	}
+
+	resourceManager := rclient.ResourceManagerClient{Ctx: m.Ctx, K8sclient: m.K8sclient, Log: m.Log}
+	hostname := zones.ResourceName(router.Spec.Hostname)
+	if !zones.Match(hostname).IsNamespaceAllowed(router.Namespace) {
+		return m.deletePrevious(router, resourceManager, nil)
+	}
+
+	conflict, err := GetHostnameConflict(m.Ctx, m.K8sclient, hostname, RouterCapp(router), utils.RouterResourceKey)
+	if err != nil {
+		return err
+	}
+
+	if conflict != "" {
+		return m.deletePrevious(router, resourceManager, nil)
+	}
+
+	httpRouteManager := m.httpRouteManager()
+
+	httpRoute, err := m.prepareResource(router, hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := httpRouteManager.applyHTTPRoute(&router, httpRoute, resourceManager); err != nil {
+		return err
+	}
+
+	return m.deletePrevious(router, resourceManager, map[string]bool{hostname: true})
+}
+
+// CleanUp deletes the HTTPRoutes of a CappRouter.
+// There is nothing to clean up if the Gateway API is not installed.
+func (m CappRouterManager) CleanUp(router cappv1alpha1.CappRouter) error {
+	resourceManager := rclient.ResourceManagerClient{Ctx: m.Ctx, K8sclient: m.K8sclient, Log: m.Log}
+
+	err := m.deletePrevious(router, resourceManager, nil)
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	return err
+}
+
+// deletePrevious deletes the HTTPRoutes of a CappRouter which are not of one of the given hostnames.
+func (m CappRouterManager) deletePrevious(router cappv1alpha1.CappRouter, resourceManager rclient.ResourceManagerClient, hostnames map[string]bool) error {
+	httpRoutes := gatewayv1.HTTPRouteList{}
+	listOptions := utils.GetListOptions(labels.Set{utils.RouterResourceKey: router.Name})
+	listOptions.Namespace = router.Namespace
+	if err := m.K8sclient.List(m.Ctx, &httpRoutes, &listOptions); err != nil {
+		return fmt.Errorf("unable to list HTTPRoutes of CappRouter %q: %w", router.Name, err)
+	}
+
+	return m.httpRouteManager().deletePreviousHTTPRoutes(httpRoutes, resourceManager, hostnames)
+}
@@ -0,0 +1,94 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newCappRouter() cappv1alpha1.CappRouter {
+	return cappv1alpha1.CappRouter{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-router", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappRouterSpec{
+			Hostname:   "shop",
+			TlsEnabled: true,
+			Routes: []cappv1alpha1.CappRouterRoute{
+				{PathPrefix: "/orders", CappName: "orders"},
+				{PathPrefix: "/", CappName: "storefront"},
+			},
+		},
+	}
+}
+
+func TestManageCappRouter(t *testing.T) {
+	timeoutSeconds := int64(120)
+	orders := &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test-ns"},
+		Spec:       cappv1alpha1.CappSpec{RouteSpec: cappv1alpha1.RouteSpec{RouteTimeoutSeconds: &timeoutSeconds}},
+	}
+	httpRouteManager, k8sClient := newHTTPRouteManager(orders)
+	manager := CappRouterManager{Ctx: httpRouteManager.Ctx, K8sclient: k8sClient, Log: httpRouteManager.Log,
+		EventRecorder: httpRouteManager.EventRecorder, Routing: httpRouteManager.Routing}
+	ctx := context.Background()
+
+	router := newCappRouter()
+	assert.True(t, manager.IsRequired())
+	assert.NoError(t, manager.Manage(router))
+
+	httpRoute := gatewayv1.HTTPRoute{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "shop.capp-zone.com"}, &httpRoute))
+	assert.Equal(t, "test-router", httpRoute.Labels[utils.RouterResourceKey])
+	assert.Equal(t, []gatewayv1.Hostname{"shop.capp-zone.com"}, httpRoute.Spec.Hostnames)
+	assert.Len(t, httpRoute.Spec.Rules, 2)
+	rule := httpRoute.Spec.Rules[0]
+	assert.Equal(t, "/orders", *rule.Matches[0].Path.Value)
+	assert.Equal(t, gatewayv1.PathMatchPathPrefix, *rule.Matches[0].Path.Type)
+	assert.Equal(t, gatewayv1.PreciseHostname("orders.test-ns.svc.cluster.local"), *rule.Filters[0].URLRewrite.Hostname)
+	assert.Equal(t, gatewayv1.PreciseHostname("storefront.test-ns.svc.cluster.local"), *httpRoute.Spec.Rules[1].Filters[0].URLRewrite.Hostname)
+
+	// Every rule uses the route timeout of its Capp, if it exists.
+	assert.Equal(t, gatewayv1.Duration("2m0s"), *rule.Timeouts.Request)
+	assert.Nil(t, httpRoute.Spec.Rules[1].Timeouts)
+
+	router.Spec.Hostname = "store"
+	assert.NoError(t, manager.Manage(router))
+	assert.Error(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "shop.capp-zone.com"}, &httpRoute))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "store.capp-zone.com"}, &httpRoute))
+
+	assert.NoError(t, manager.CleanUp(router))
+	httpRoutes := gatewayv1.HTTPRouteList{}
+	assert.NoError(t, k8sClient.List(ctx, &httpRoutes))
+	assert.Empty(t, httpRoutes.Items)
+
+	gateway := gatewayv1.Gateway{}
+	assert.NoError(t, k8sClient.Get(ctx, manager.Routing.Gateway, &gateway))
+	assert.Len(t, gateway.Spec.Listeners, 1)
+
+	manager.Routing.Backend = RoutingBackendDomainMapping
+	assert.False(t, manager.IsRequired())
+}
+
+func TestRouterCapp(t *testing.T) {
+	router := newCappRouter()
+	capp := RouterCapp(router)
+	assert.Equal(t, "shop", capp.Spec.RouteSpec.Hostname)
+	assert.True(t, capp.Spec.RouteSpec.TlsEnabled)
+	assert.Nil(t, capp.Status.RouteStatus.DomainMappingObjectStatus.URL)
+
+	router.Status.Hostname = "shop.capp-zone.com"
+	capp = RouterCapp(router)
+	assert.Equal(t, "shop.capp-zone.com", capp.Status.RouteStatus.DomainMappingObjectStatus.URL.Host)
+
+	_, k8sClient := newHTTPRouteManager()
+	dnsRecordManager := DNSRecordManager{Ctx: context.Background(), K8sclient: k8sClient, ParentKey: utils.RouterResourceKey}
+	dnsRecord, err := dnsRecordManager.prepareResource(capp, RouteHostname{Hostname: "shop.capp-zone.com"}, cnameRecordKind{}, dnsRecordOptions{zone: testZone})
+	assert.NoError(t, err)
+	assert.Equal(t, "test-router", dnsRecord.GetLabels()[utils.RouterResourceKey])
+	assert.NotContains(t, dnsRecord.GetLabels(), utils.CappResourceKey)
+}
@@ -2,10 +2,8 @@ package resourcemanagers
 
 import (
 	"context"
-
 	"fmt"
-
-	certv1alpha1 "github.com/dana-team/cert-external-issuer/api/v1alpha1"
+	"reflect"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
@@ -28,7 +26,6 @@ const (
 	eventCappCertificateCreationFailed = "CertificateCreationFailed"
 	eventCappCertificateCreated        = "CertificateCreated"
 	PrivateKeySize                     = 4096
-	clusterIssuerKind                  = "ClusterIssuer"
 )
 
 type CertificateManager struct {
@@ -36,52 +33,52 @@ type CertificateManager struct {
 	K8sclient     client.Client
 	Log           logr.Logger
 	EventRecorder record.EventRecorder
+	Routing       RoutingConfig
+
+	// ParentKey is the label which references the owner of the resources. It defaults to utils.CappResourceKey,
+	// and is set to utils.RouterResourceKey when managing the resources of a CappRouter.
+	ParentKey string
 }
 
-// prepareResource prepares a Certificate resource based on the provided Capp.
-func (c CertificateManager) prepareResource(capp cappv1alpha1.Capp) (cmapi.Certificate, error) {
+// prepareResource prepares a Certificate resource of the provided Capp which covers the given hostnames.
+func (c CertificateManager) prepareResource(capp cappv1alpha1.Capp, certificateName string, dnsNames []string) (cmapi.Certificate, error) {
 	dnsConfig, err := utils.GetDNSConfig(c.Ctx, c.K8sclient)
 	if err != nil {
 		return cmapi.Certificate{}, err
 	}
 
-	zone, err := utils.GetZoneFromConfig(dnsConfig)
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
 	if err != nil {
 		return cmapi.Certificate{}, err
 	}
 
-	issuer, err := utils.GetIssuerNameFromConfig(dnsConfig)
+	issuerRef, err := certificateIssuerRef(capp, zones.Match(certificateName).Config)
 	if err != nil {
 		return cmapi.Certificate{}, err
 	}
 
-	resourceName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
-	secretName := utils.GenerateSecretName(resourceName)
+	secretName := utils.GenerateSecretName(certificateName)
 
 	certificate := cmapi.Certificate{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      resourceName,
+			Name:      certificateName,
 			Namespace: capp.Namespace,
 			Labels: map[string]string{
-				utils.CappResourceKey:   capp.Name,
-				utils.ManagedByLabelKey: utils.CappKey,
+				parentLabelKey(c.ParentKey): capp.Name,
+				utils.ManagedByLabelKey:     utils.CappKey,
 			},
 		},
 		Spec: cmapi.CertificateSpec{
-			CommonName: utils.TruncateCommonName(resourceName),
-			DNSNames:   []string{resourceName},
+			CommonName: utils.TruncateCommonName(certificateName),
+			DNSNames:   dnsNames,
 			PrivateKey: &cmapi.CertificatePrivateKey{
 				Algorithm: cmapi.RSAKeyAlgorithm,
 				Encoding:  cmapi.PKCS1,
 				Size:      PrivateKeySize,
 			},
-			IsCA: false,
-			IssuerRef: cmmeta.ObjectReference{
-				Name:  issuer,
-				Kind:  clusterIssuerKind,
-				Group: certv1alpha1.GroupVersion.Group,
-			},
+			IsCA:       false,
+			IssuerRef:  issuerRef,
 			SecretName: secretName,
 		},
 	}
@@ -89,63 +86,109 @@ func (c CertificateManager) prepareResource(capp cappv1alpha1.Capp) (cmapi.Certi
 	return certificate, nil
 }
 
-// CleanUp attempts to delete the associated Certificate for a given Capp resource.
+// certificateIssuerRef returns the issuer of the Certificates of a Capp, which is the issuer of the zone
+// unless the Capp sets its own Issuer, in its namespace. The group of the issuer of the Capp defaults to
+// cert-manager.io. Issuers of other kinds, such as a ClusterIssuer, are ignored, as a Capp may not use them.
+func certificateIssuerRef(capp cappv1alpha1.Capp, zoneConfig map[string]string) (cmmeta.ObjectReference, error) {
+	issuerRef, err := utils.GetIssuerRefFromConfig(zoneConfig)
+	if err != nil {
+		return cmmeta.ObjectReference{}, err
+	}
+
+	cappIssuerRef := capp.Spec.RouteSpec.CertificateIssuerRef
+	if cappIssuerRef != nil && (cappIssuerRef.Kind == "" || cappIssuerRef.Kind == utils.IssuerKind) {
+		issuerRef.Name = cappIssuerRef.Name
+		issuerRef.Kind = utils.IssuerKind
+		issuerRef.Group = utils.IssuerGroup
+		if cappIssuerRef.Group != "" {
+			issuerRef.Group = cappIssuerRef.Group
+		}
+	}
+
+	return cmmeta.ObjectReference{Name: issuerRef.Name, Kind: issuerRef.Kind, Group: issuerRef.Group}, nil
+}
+
+// CleanUp attempts to delete the associated Certificates for a given Capp resource.
 func (c CertificateManager) CleanUp(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: c.Ctx, K8sclient: c.K8sclient, Log: c.Log}
 
 	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
 		certificate := rclient.GetBareCertificate(capp.Status.RouteStatus.DomainMappingObjectStatus.URL.Host, capp.Namespace)
-		if err := resourceManager.DeleteResource(&certificate); err != nil {
-			if errors.IsNotFound(err) {
-				return nil
-			}
+		if err := resourceManager.DeleteResource(&certificate); err != nil && !errors.IsNotFound(err) {
 			return err
 		}
 	}
-	return nil
+
+	certificates, err := c.getPreviousCertificates(capp)
+	if err != nil {
+		return err
+	}
+
+	return c.deletePreviousCertificates(certificates, resourceManager, nil)
 }
 
-// IsRequired is responsible to determine if resource Certificate is required.
+// IsRequired is responsible to determine if resource Certificate is required. Certificates are not issued
+// for the gatewayAPI routing backend, as TLS is terminated by the listeners of the Gateway.
 func (c CertificateManager) IsRequired(capp cappv1alpha1.Capp) bool {
-	return capp.Spec.RouteSpec.TlsEnabled && utils.IsCustomHostnameSet(capp.Spec.RouteSpec.Hostname)
+	return capp.Spec.RouteSpec.TlsEnabled && utils.IsCustomHostnameSet(capp.Spec.RouteSpec.Hostname) &&
+		!utils.IsClusterLocal(capp.Spec.RouteSpec) && !isRoutingBackend(c.Routing.Backend, RoutingBackendGatewayAPI)
 }
 
 // Manage creates or updates a Certificate resource based on the provided Capp if it's required.
 // If it's not, then it cleans up the resource if it exists.
 func (c CertificateManager) Manage(capp cappv1alpha1.Capp) error {
 	if c.IsRequired(capp) {
-		return c.create(capp)
+		return c.createOrUpdate(capp)
 	}
 
 	return c.CleanUp(capp)
 }
 
-// create creates a Certificate resource.
-func (c CertificateManager) create(capp cappv1alpha1.Capp) error {
-	certificateFromCapp, err := c.prepareResource(capp)
+// createOrUpdate creates or updates the Certificate resources which cover the hostnames which a Capp owns.
+func (c CertificateManager) createOrUpdate(capp cappv1alpha1.Capp) error {
+	routeHostnames, err := getRouteHostnames(c.Ctx, c.K8sclient, capp, c.ParentKey)
 	if err != nil {
-		return fmt.Errorf("failed to prepare Certificate: %w", err)
+		return fmt.Errorf("failed to get hostnames: %w", err)
 	}
 
-	certificate := cmapi.Certificate{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: c.Ctx, K8sclient: c.K8sclient, Log: c.Log}
+	certificateNames, dnsNames := certificateDNSNames(routeHostnames)
+	for _, certificateName := range certificateNames {
+		if err := c.createOrUpdateCertificate(capp, certificateName, dnsNames[certificateName], resourceManager); err != nil {
+			return err
+		}
+	}
+
+	if hasPreviousHostnames(capp) {
+		if err := c.handlePreviousCertificates(capp, resourceManager, routeHostnames); err != nil {
+			return fmt.Errorf("failed to handle previous Certificates: %w", err)
+		}
+	}
 
+	return nil
+}
+
+// createOrUpdateCertificate creates the Certificate resource which covers the given hostnames of a Capp if it
+// does not exist yet, and otherwise updates its hostnames and issuer.
+func (c CertificateManager) createOrUpdateCertificate(capp cappv1alpha1.Capp, certificateName string, dnsNames []string, resourceManager rclient.ResourceManagerClient) error {
+	certificateFromCapp, err := c.prepareResource(capp, certificateName, dnsNames)
+	if err != nil {
+		return fmt.Errorf("failed to prepare Certificate: %w", err)
+	}
+
+	certificate := cmapi.Certificate{}
 	if err := c.K8sclient.Get(c.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: certificateFromCapp.Name}, &certificate); err != nil {
 		if errors.IsNotFound(err) {
-			if err := c.createCertificate(capp, certificateFromCapp, resourceManager); err != nil {
-				return err
-			}
-
 			return c.createCertificate(capp, certificateFromCapp, resourceManager)
-		} else {
-			return fmt.Errorf("failed to get Certificate %q: %w", certificateFromCapp.Name, err)
 		}
+		return fmt.Errorf("failed to get Certificate %q: %w", certificateFromCapp.Name, err)
 	}
 
-	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		if err := c.handlePreviousCertificates(capp, resourceManager, certificateFromCapp.Name); err != nil {
-			return fmt.Errorf("failed to handle previous Certificates: %w", err)
-		}
+	if !reflect.DeepEqual(certificate.Spec.DNSNames, certificateFromCapp.Spec.DNSNames) ||
+		certificate.Spec.IssuerRef != certificateFromCapp.Spec.IssuerRef {
+		certificate.Spec.DNSNames = certificateFromCapp.Spec.DNSNames
+		certificate.Spec.IssuerRef = certificateFromCapp.Spec.IssuerRef
+		return resourceManager.UpdateResource(&certificate)
 	}
 
 	return nil
@@ -169,17 +212,16 @@ func (c CertificateManager) createCertificate(capp cappv1alpha1.Capp, certificat
 // handlePreviousCertificates takes care of removing unneeded Certificate objects. If the DNSRecord
 // which corresponds to the latest Certificate object is not yet available then return early
 // and do not delete the previous Certificates.
-func (c CertificateManager) handlePreviousCertificates(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, name string) error {
-	var available bool
-	var err error
-
-	available, err = utils.IsDNSRecordAvailable(c.Ctx, c.K8sclient, name, capp.Namespace)
-	if err != nil {
-		return err
-	}
+func (c CertificateManager) handlePreviousCertificates(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, routeHostnames []RouteHostname) error {
+	if len(routeHostnames) > 0 {
+		available, err := isDNSRecordAvailable(c.Ctx, c.K8sclient, routeHostnames[0].Hostname, capp.Namespace)
+		if err != nil {
+			return err
+		}
 
-	if !available {
-		return nil
+		if !available {
+			return nil
+		}
 	}
 
 	certificates, err := c.getPreviousCertificates(capp)
@@ -187,7 +229,13 @@ func (c CertificateManager) handlePreviousCertificates(capp cappv1alpha1.Capp, r
 		return err
 	}
 
-	return c.deletePreviousCertificates(certificates, resourceManager, capp.Spec.RouteSpec.Hostname)
+	certificateNames, _ := certificateDNSNames(routeHostnames)
+	keep := make(map[string]bool, len(certificateNames))
+	for _, certificateName := range certificateNames {
+		keep[certificateName] = true
+	}
+
+	return c.deletePreviousCertificates(certificates, resourceManager, keep)
 }
 
 // getPreviousCertificates returns a list of all Certificate objects that are related to the given Capp.
@@ -195,9 +243,10 @@ func (c CertificateManager) getPreviousCertificates(capp cappv1alpha1.Capp) (cma
 	certificates := cmapi.CertificateList{}
 
 	set := labels.Set{
-		utils.CappResourceKey: capp.Name,
+		parentLabelKey(c.ParentKey): capp.Name,
 	}
 	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
 
 	if err := c.K8sclient.List(c.Ctx, &certificates, &listOptions); err != nil {
 		return certificates, fmt.Errorf("unable to list Certificates of Capp %q: %w", capp.Name, err)
@@ -206,10 +255,10 @@ func (c CertificateManager) getPreviousCertificates(capp cappv1alpha1.Capp) (cma
 	return certificates, nil
 }
 
-// deletePreviousCertificates deletes all previous Certificates associated with a Capp.
-func (c CertificateManager) deletePreviousCertificates(certificates cmapi.CertificateList, resourceManager rclient.ResourceManagerClient, hostname string) error {
+// deletePreviousCertificates deletes all Certificates associated with a Capp which are not one of the given Certificates.
+func (c CertificateManager) deletePreviousCertificates(certificates cmapi.CertificateList, resourceManager rclient.ResourceManagerClient, hostnames map[string]bool) error {
 	for _, certificate := range certificates.Items {
-		if certificate.Name != hostname {
+		if !hostnames[certificate.Name] {
 			cert := rclient.GetBareCertificate(certificate.Name, certificate.Namespace)
 			if err := resourceManager.DeleteResource(&cert); err != nil {
 				return err
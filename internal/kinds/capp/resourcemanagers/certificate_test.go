@@ -0,0 +1,84 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestManageCertificateIssuer(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = cmapi.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data: map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer",
+			"zones": "- name: dev-zone.com.\n  issuer: letsencrypt\n  issuerGroup: cert-manager.io\n"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig).Build()
+	manager := CertificateManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+	ctx := context.Background()
+
+	capp := newTaggedCapp()
+	capp.Spec.RouteSpec.TlsEnabled = true
+	assert.NoError(t, manager.Manage(capp))
+
+	certificate := cmapi.Certificate{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &certificate))
+	assert.Equal(t, cmmeta.ObjectReference{Name: "cert-issuer", Kind: "ClusterIssuer", Group: "cert.dana.io"}, certificate.Spec.IssuerRef)
+
+	// The issuer of the Capp overrides the issuer of the zone, and the Certificate is updated when it changes.
+	// Its kind defaults to Issuer, and its group to cert-manager.io rather than to the group of the zone.
+	capp.Spec.RouteSpec.CertificateIssuerRef = &cappv1alpha1.CertificateIssuerReference{Name: "selfsigned"}
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &certificate))
+	assert.Equal(t, cmmeta.ObjectReference{Name: "selfsigned", Kind: "Issuer", Group: "cert-manager.io"}, certificate.Spec.IssuerRef)
+
+	capp.Spec.RouteSpec.CertificateIssuerRef = &cappv1alpha1.CertificateIssuerReference{Name: "cert-issuer", Kind: "Issuer", Group: "cert.dana.io"}
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &certificate))
+	assert.Equal(t, cmmeta.ObjectReference{Name: "cert-issuer", Kind: "Issuer", Group: "cert.dana.io"}, certificate.Spec.IssuerRef)
+
+	devCapp := newTaggedCapp()
+	devCapp.Name = "dev-capp"
+	devCapp.Spec.RouteSpec.Hostname = "app.dev-zone.com"
+	devCapp.Spec.RouteSpec.TlsEnabled = true
+	assert.NoError(t, manager.Manage(devCapp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.dev-zone.com"}, &certificate))
+	assert.Equal(t, cmmeta.ObjectReference{Name: "letsencrypt", Kind: "ClusterIssuer", Group: "cert-manager.io"}, certificate.Spec.IssuerRef)
+
+	devCapp.Spec.RouteSpec.CertificateIssuerRef = &cappv1alpha1.CertificateIssuerReference{Name: "letsencrypt-staging"}
+	assert.NoError(t, manager.Manage(devCapp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.dev-zone.com"}, &certificate))
+	assert.Equal(t, cmmeta.ObjectReference{Name: "letsencrypt-staging", Kind: "Issuer", Group: "cert-manager.io"}, certificate.Spec.IssuerRef)
+
+	// A Capp may not use a ClusterIssuer, so the issuer of the zone is used instead.
+	devCapp.Spec.RouteSpec.CertificateIssuerRef = &cappv1alpha1.CertificateIssuerReference{Name: "letsencrypt-staging", Kind: "ClusterIssuer"}
+	assert.NoError(t, manager.Manage(devCapp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.dev-zone.com"}, &certificate))
+	assert.Equal(t, cmmeta.ObjectReference{Name: "letsencrypt", Kind: "ClusterIssuer", Group: "cert-manager.io"}, certificate.Spec.IssuerRef)
+}
+
+func TestCertificateIsRequiredGatewayAPI(t *testing.T) {
+	capp := newTaggedCapp()
+	capp.Spec.RouteSpec.TlsEnabled = true
+
+	assert.True(t, CertificateManager{}.IsRequired(capp))
+	assert.True(t, CertificateManager{Routing: RoutingConfig{Backend: RoutingBackendOpenShiftRoute}}.IsRequired(capp))
+
+	// TLS is terminated by the listeners of the Gateway, so no Certificate is issued for the hostname.
+	assert.False(t, CertificateManager{Routing: RoutingConfig{Backend: RoutingBackendGatewayAPI}}.IsRequired(capp))
+}
@@ -0,0 +1,385 @@
+package resourcemanagers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"strings"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	RecordTypeCNAME = "CNAME"
+	RecordTypeA     = "A"
+	RecordTypeAAAA  = "AAAA"
+	RecordTypeTXT   = "TXT"
+
+	// OwnershipRecordPrefix is prefixed to the name of a hostname to get the name of its ownership TXT record,
+	// as a CNAME record can not share its name with other records.
+	OwnershipRecordPrefix = "_capp-owner."
+
+	ownershipRecordHeritage = "heritage=container-app-operator"
+	ownershipRecordOwner    = "container-app-operator/owner="
+	ownershipRecordResource = "container-app-operator/resource="
+	ownerResourceIDBytes    = 8
+	reasonDNSRecordPending  = "Pending"
+)
+
+// dnsRecordKind is a kind of DNS record object of a DNS backend, which holds the DNS records of one hostname.
+type dnsRecordKind interface {
+	// groupVersionKind returns the GroupVersionKind of the DNS record objects.
+	groupVersionKind() schema.GroupVersionKind
+
+	// recordKey returns the key of the DNS record object of a hostname whose owner is in the given namespace.
+	recordKey(hostname, namespace string) types.NamespacedName
+
+	// newRecord returns a DNS record object with only its name and namespace set.
+	newRecord(key types.NamespacedName) client.Object
+
+	// newRecordList returns an empty list of DNS record objects.
+	newRecordList() client.ObjectList
+
+	// prepareRecord prepares the DNS record object of a hostname.
+	prepareRecord(key types.NamespacedName, hostname string, recordLabels map[string]string, options dnsRecordOptions) (client.Object, error)
+
+	// updateRecord copies the spec of the desired DNS record object into the existing one,
+	// and returns a boolean indicating whether it has changed.
+	updateRecord(existing, desired client.Object) bool
+
+	// setRecordStatus sets the status of the DNS record object in the status of the DNS records of a hostname.
+	setRecordStatus(record client.Object, dnsRecordStatus *cappv1alpha1.DNSRecordObjectStatus)
+}
+
+// dnsBackend manages the DNS records of hostnames using the API of one of the supported DNS backends.
+type dnsBackend interface {
+	// name returns the name of the backend, as set in the DNS ConfigMap.
+	name() string
+
+	// recordKinds returns all the kinds of DNS record objects of the backend.
+	recordKinds() []dnsRecordKind
+
+	// requiredRecordKinds returns the kinds of the DNS record objects which every hostname needs with the given options.
+	requiredRecordKinds(options dnsRecordOptions) []dnsRecordKind
+}
+
+// dnsBackends are the supported DNS backends, the default one first.
+var dnsBackends = []dnsBackend{providerDNSBackend{}, externalDNSBackend{}}
+
+// dnsRecordOptions are the options of the DNS records of the hostnames of a Capp.
+type dnsRecordOptions struct {
+	// zones are the DNS zones of the DNS ConfigMap, which the options of every hostname are taken from.
+	zones utils.DNSZones
+
+	// zone is the DNS zone of the hostname.
+	zone string
+
+	// cname is the canonical name which CNAME records point at. It is only used when there are no addresses.
+	cname string
+
+	// addresses are the IP addresses of the ingress which A and AAAA records point at.
+	addresses []net.IP
+
+	// ttl is the TTL in seconds of the DNS records, or nil for the default TTL of the DNS backend.
+	ttl *int64
+
+	// txtOwnerID is the ID of the owner which is written to the ownership TXT records. They are only created when it is set.
+	txtOwnerID string
+
+	// xpProvider is the name of the Crossplane provider config of provider-dns records.
+	xpProvider string
+
+	// owner is the object which owns the DNS records.
+	owner types.NamespacedName
+}
+
+// getDNSBackend returns the DNS backend which is set in the DNS ConfigMap.
+func getDNSBackend(dnsConfig map[string]string) (dnsBackend, error) {
+	backendName, err := utils.GetDNSBackendFromConfig(dnsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, ok := getDNSBackendByName(backendName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported DNS backend %q", backendName)
+	}
+
+	return backend, nil
+}
+
+// getDNSBackendByName returns the DNS backend with the given name, and a boolean indicating whether it is supported.
+func getDNSBackendByName(backendName string) (dnsBackend, bool) {
+	for _, backend := range dnsBackends {
+		if backend.name() == backendName {
+			return backend, true
+		}
+	}
+
+	return nil, false
+}
+
+// getDNSRecordOptions returns the options of DNS records from the DNS ConfigMap, which are shared by all the zones.
+func getDNSRecordOptions(dnsConfig map[string]string) (dnsRecordOptions, error) {
+	options := dnsRecordOptions{txtOwnerID: utils.GetTXTOwnerIDFromConfig(dnsConfig)}
+	var err error
+
+	if options.zones, err = utils.GetDNSZonesFromConfig(dnsConfig); err != nil {
+		return options, err
+	}
+
+	if options.addresses, err = utils.GetAddressesFromConfig(dnsConfig); err != nil {
+		return options, err
+	}
+
+	if options.ttl, err = utils.GetTTLFromConfig(dnsConfig); err != nil {
+		return options, err
+	}
+
+	return options, nil
+}
+
+// forHostname returns the options of the DNS records of a hostname using the given DNS backend,
+// with the cname and Crossplane provider config of the zone which the hostname matches.
+func (o dnsRecordOptions) forHostname(hostname string, backend dnsBackend) (dnsRecordOptions, error) {
+	zone := o.zones.Match(hostname)
+	o.zone = zone.Name
+	var err error
+
+	if len(o.addresses) == 0 {
+		if o.cname, err = utils.GetDNSRecordFromConfig(zone.Config); err != nil {
+			return o, err
+		}
+	}
+
+	if backend.name() == utils.DNSBackendProviderDNS {
+		if o.xpProvider, err = utils.GetXPProviderFromConfig(zone.Config); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
+
+// recordTypes returns the types of the DNS records of every hostname. Hostnames get A and AAAA records
+// for the addresses of the ingress if there are any, or else a CNAME record, and an ownership TXT record if
+// an owner ID is set.
+func (o dnsRecordOptions) recordTypes() []string {
+	var recordTypes []string
+	if len(o.addresses) == 0 {
+		recordTypes = append(recordTypes, RecordTypeCNAME)
+	}
+	if len(o.addressesOfType(RecordTypeA)) > 0 {
+		recordTypes = append(recordTypes, RecordTypeA)
+	}
+	if len(o.addressesOfType(RecordTypeAAAA)) > 0 {
+		recordTypes = append(recordTypes, RecordTypeAAAA)
+	}
+	if o.txtOwnerID != "" {
+		recordTypes = append(recordTypes, RecordTypeTXT)
+	}
+
+	return recordTypes
+}
+
+// addressesOfType returns the IPv4 addresses of the ingress for A records, or its IPv6 addresses for AAAA records.
+func (o dnsRecordOptions) addressesOfType(recordType string) []string {
+	var addresses []string
+	for _, address := range o.addresses {
+		if (address.To4() != nil) == (recordType == RecordTypeA) {
+			addresses = append(addresses, address.String())
+		}
+	}
+
+	return addresses
+}
+
+// ownershipRecordValue returns the value of the ownership TXT record of the hostnames of the owner. The owner is
+// written as an opaque ID, as the ownership TXT records are public and must not disclose namespaces and names.
+func (o dnsRecordOptions) ownershipRecordValue() string {
+	return fmt.Sprintf("%s,%s%s,%s%s", ownershipRecordHeritage, ownershipRecordOwner, o.txtOwnerID, ownershipRecordResource, o.ownerResourceID())
+}
+
+// ownerResourceID returns the opaque ID of the owner of the DNS records, which is derived from its namespace and name.
+func (o dnsRecordOptions) ownerResourceID() string {
+	sum := sha256.Sum256([]byte(o.owner.String()))
+	return hex.EncodeToString(sum[:ownerResourceIDBytes])
+}
+
+// lookupTXT looks up the TXT records of a DNS name. It is a variable so that the DNS can be replaced in tests.
+var lookupTXT = net.DefaultResolver.LookupTXT
+
+// getOwnershipRecordOwner returns the owner ID in the existing ownership TXT record of a hostname when it was written
+// by the operator with another owner ID, such as the operator of another cluster, and an empty string otherwise.
+// TXT records which were not written by the operator are ignored.
+func getOwnershipRecordOwner(ctx context.Context, hostname, txtOwnerID string) (string, error) {
+	values, err := lookupTXT(ctx, OwnershipRecordPrefix+hostname)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if stderrors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to look up the ownership TXT record of %q: %w", hostname, err)
+	}
+
+	for _, value := range values {
+		fields := strings.Split(value, ",")
+		if fields[0] != ownershipRecordHeritage {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			if owner, ok := strings.CutPrefix(field, ownershipRecordOwner); ok && owner != txtOwnerID {
+				return owner, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// ClusterOwner returns the description of the cluster with the given owner ID as the owner of a hostname.
+func ClusterOwner(txtOwnerID string) string {
+	return fmt.Sprintf("cluster %s", txtOwnerID)
+}
+
+// allDNSRecordKinds returns the kinds of DNS record objects of all the DNS backends.
+func allDNSRecordKinds() []dnsRecordKind {
+	var kinds []dnsRecordKind
+	for _, backend := range dnsBackends {
+		kinds = append(kinds, backend.recordKinds()...)
+	}
+
+	return kinds
+}
+
+// listDNSRecords returns the DNS record objects of a kind which match the given list options.
+func listDNSRecords(ctx context.Context, k8sClient client.Client, kind dnsRecordKind, listOptions *client.ListOptions) ([]client.Object, error) {
+	recordList := kind.newRecordList()
+	if err := k8sClient.List(ctx, recordList, listOptions); err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(recordList)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		records = append(records, item.(client.Object))
+	}
+
+	return records, nil
+}
+
+// GetDNSRecordStatus returns the status of the DNS records of a hostname whose owner is in the given namespace,
+// using the DNS backend which is set in the DNS ConfigMap. The Backend and RecordTypes of the status are set
+// even when getting one of the DNS record objects fails.
+func GetDNSRecordStatus(ctx context.Context, k8sClient client.Client, hostname, namespace string) (cappv1alpha1.DNSRecordObjectStatus, error) {
+	dnsConfig, err := utils.GetDNSConfig(ctx, k8sClient)
+	if err != nil {
+		return cappv1alpha1.DNSRecordObjectStatus{}, err
+	}
+
+	backend, err := getDNSBackend(dnsConfig)
+	if err != nil {
+		return cappv1alpha1.DNSRecordObjectStatus{}, err
+	}
+
+	options, err := getDNSRecordOptions(dnsConfig)
+	if err != nil {
+		return cappv1alpha1.DNSRecordObjectStatus{}, err
+	}
+
+	dnsRecordStatus := cappv1alpha1.DNSRecordObjectStatus{Backend: backend.name(), RecordTypes: options.recordTypes()}
+	for _, kind := range backend.requiredRecordKinds(options) {
+		record := kind.newRecord(kind.recordKey(hostname, namespace))
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(record), record); err != nil {
+			return dnsRecordStatus, err
+		}
+		kind.setRecordStatus(record, &dnsRecordStatus)
+	}
+
+	return dnsRecordStatus, nil
+}
+
+// DNSRecordReadyCondition returns the Ready condition of the DNS records of a hostname with the given status.
+// With provider-dns it is the Ready condition of the first DNS record which is not available, and with
+// external-dns it reflects whether external-dns has processed the latest generation of the DNSEndpoint.
+func DNSRecordReadyCondition(dnsRecordStatus cappv1alpha1.DNSRecordObjectStatus) xpcommonv1.Condition {
+	if dnsRecordStatus.Backend == utils.DNSBackendExternalDNS {
+		endpointStatus := dnsRecordStatus.DNSEndpointObjectStatus
+		if endpointStatus.Generation > 0 && endpointStatus.ObservedGeneration >= endpointStatus.Generation {
+			return xpcommonv1.Available()
+		}
+
+		return xpcommonv1.Condition{Type: xpcommonv1.TypeReady, Status: corev1.ConditionUnknown,
+			Reason: reasonDNSRecordPending, Message: "waiting for external-dns to process the DNSEndpoint"}
+	}
+
+	recordTypes := dnsRecordStatus.RecordTypes
+	if len(recordTypes) == 0 {
+		recordTypes = []string{RecordTypeCNAME}
+	}
+
+	for _, recordType := range recordTypes {
+		readyCondition := providerDNSReadyCondition(dnsRecordStatus, recordType)
+		if readyCondition.Status != corev1.ConditionTrue {
+			if readyCondition.Message != "" {
+				readyCondition.Message = fmt.Sprintf("%s record: %s", recordType, readyCondition.Message)
+			}
+			return readyCondition
+		}
+	}
+
+	return xpcommonv1.Available()
+}
+
+// IsDNSRecordStatusAvailable returns a boolean indicating whether the DNS records with the given status are available.
+func IsDNSRecordStatusAvailable(dnsRecordStatus cappv1alpha1.DNSRecordObjectStatus) bool {
+	return DNSRecordReadyCondition(dnsRecordStatus).Status == corev1.ConditionTrue
+}
+
+// isDNSRecordAvailable returns a boolean indicating whether the DNS records of a hostname are currently available.
+// DNS records which were not created yet are not available.
+func isDNSRecordAvailable(ctx context.Context, k8sClient client.Client, hostname, namespace string) (bool, error) {
+	dnsRecordStatus, err := GetDNSRecordStatus(ctx, k8sClient, hostname, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed getting DNSRecord: %w", err)
+	}
+
+	return IsDNSRecordStatusAvailable(dnsRecordStatus), nil
+}
+
+// InstalledDNSRecordObjects returns an empty DNS record object of every kind whose API is installed in the
+// cluster, so that they can be watched regardless of the DNS backend which is currently in use.
+func InstalledDNSRecordObjects(mapper meta.RESTMapper) ([]client.Object, error) {
+	var objects []client.Object
+	for _, kind := range allDNSRecordKinds() {
+		gvk := kind.groupVersionKind()
+		if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to find the API of %q: %w", gvk.Kind, err)
+		}
+		objects = append(objects, kind.newRecord(types.NamespacedName{}))
+	}
+
+	return objects, nil
+}
@@ -3,19 +3,17 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"strings"
 
-	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
-
-	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 
@@ -33,79 +31,86 @@ type DNSRecordManager struct {
 	K8sclient     client.Client
 	Log           logr.Logger
 	EventRecorder record.EventRecorder
+
+	// ParentKey is the label which references the owner of the resources. It defaults to utils.CappResourceKey,
+	// and is set to utils.RouterResourceKey when managing the resources of a CappRouter.
+	ParentKey string
 }
 
-// prepareResource prepares a DNSRecord resource based on the provided Capp.
-func (r DNSRecordManager) prepareResource(capp cappv1alpha1.Capp) (dnsrecordv1alpha1.CNAMERecord, error) {
-	dnsConfig, err := utils.GetDNSConfig(r.Ctx, r.K8sclient)
-	if err != nil {
-		return dnsrecordv1alpha1.CNAMERecord{}, err
+// prepareResource prepares a DNSRecord resource of the given kind for a hostname of the provided Capp.
+func (r DNSRecordManager) prepareResource(capp cappv1alpha1.Capp, routeHostname RouteHostname, kind dnsRecordKind, options dnsRecordOptions) (client.Object, error) {
+	resourceName := utils.GenerateResourceName(routeHostname.Hostname, options.zone)
+	recordLabels := map[string]string{
+		parentLabelKey(r.ParentKey): capp.Name,
+		utils.CappNamespaceKey:      capp.Namespace,
+		utils.ManagedByLabelKey:     utils.CappKey,
 	}
 
-	zone, err := utils.GetZoneFromConfig(dnsConfig)
+	return kind.prepareRecord(kind.recordKey(resourceName, capp.Namespace), resourceName, recordLabels, options)
+}
+
+// getOptions returns the options of the DNSRecords of a Capp. The TTL of the DNS ConfigMap
+// is overridden by the TTL of the Capp.
+func (r DNSRecordManager) getOptions(capp cappv1alpha1.Capp, dnsConfig map[string]string) (dnsRecordOptions, error) {
+	options, err := getDNSRecordOptions(dnsConfig)
 	if err != nil {
-		return dnsrecordv1alpha1.CNAMERecord{}, err
+		return options, err
 	}
 
-	cname, err := utils.GetDNSRecordFromConfig(dnsConfig)
-	if err != nil {
-		return dnsrecordv1alpha1.CNAMERecord{}, err
+	options.owner = types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}
+	if capp.Spec.RouteSpec.DNSRecordTTLSeconds != nil {
+		options.ttl = capp.Spec.RouteSpec.DNSRecordTTLSeconds
 	}
 
-	xpProvider, err := utils.GetXPProviderFromConfig(dnsConfig)
-	if err != nil {
-		return dnsrecordv1alpha1.CNAMERecord{}, err
-	}
-
-	resourceName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
-	recordName := utils.GenerateRecordName(capp.Spec.RouteSpec.Hostname, zone)
-
-	dnsRecord := dnsrecordv1alpha1.CNAMERecord{
-		TypeMeta: metav1.TypeMeta{},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: resourceName,
-			Labels: map[string]string{
-				utils.CappResourceKey:   capp.Name,
-				utils.CappNamespaceKey:  capp.Namespace,
-				utils.ManagedByLabelKey: utils.CappKey,
-			},
-		},
-		Spec: dnsrecordv1alpha1.CNAMERecordSpec{
-			ForProvider: dnsrecordv1alpha1.CNAMERecordParameters{
-				Name:  &recordName,
-				Zone:  &zone,
-				Cname: &cname,
-			},
-			ResourceSpec: xpcommonv1.ResourceSpec{
-				ProviderConfigReference: &xpcommonv1.Reference{
-					Name: xpProvider,
-				},
-			},
-		},
-	}
-
-	return dnsRecord, nil
+	return options, nil
 }
 
-// CleanUp attempts to delete the associated DNSRecord for a given Capp resource.
+// CleanUp attempts to delete the associated DNSRecords for a given Capp resource, of every kind of the DNS backend
+// which is set in the DNS ConfigMap and of the DNS backend in the status of the Capp, so that no DNSRecords are left
+// behind when the DNS backend or record types are changed. When the DNS backend can not be determined, every kind
+// whose API is installed is cleaned up. The DNSRecords of a hostname which is claimed by another Capp or CappRouter
+// are left for their owner, and the HostnameClaims of the Capp are released.
 func (r DNSRecordManager) CleanUp(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: r.Ctx, K8sclient: r.K8sclient, Log: r.Log}
 
+	kinds := allDNSRecordKinds()
+	if dnsConfig, err := utils.GetDNSConfig(r.Ctx, r.K8sclient); err == nil {
+		if backend, err := getDNSBackend(dnsConfig); err == nil {
+			kinds = dnsRecordKinds(capp, backend)
+		}
+	}
+
+	var previousHostname string
 	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		dnsRecord := rclient.GetBareDNSRecord(capp.Status.RouteStatus.DomainMappingObjectStatus.URL.Host)
-		if err := resourceManager.DeleteResource(&dnsRecord); err != nil {
-			if errors.IsNotFound(err) {
-				return nil
+		previousHostname = capp.Status.RouteStatus.DomainMappingObjectStatus.URL.Host
+		conflict, err := GetHostnameClaimConflict(r.Ctx, r.K8sclient, previousHostname, capp, r.ParentKey)
+		if err != nil {
+			return err
+		}
+		if conflict != nil {
+			previousHostname = ""
+		}
+	}
+
+	for _, kind := range kinds {
+		if previousHostname != "" {
+			dnsRecord := kind.newRecord(kind.recordKey(previousHostname, capp.Namespace))
+			if err := resourceManager.DeleteResource(dnsRecord); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return err
 			}
+		}
+
+		if err := r.deletePreviousDNSRecords(capp, kind, resourceManager, nil); err != nil {
 			return err
 		}
 	}
-	return nil
+
+	return r.releaseHostnameClaims(capp, nil, resourceManager)
 }
 
 // IsRequired is responsible to determine if resource DNSRecord is required.
 func (r DNSRecordManager) IsRequired(capp cappv1alpha1.Capp) bool {
-	return capp.Spec.RouteSpec.Hostname != ""
+	return capp.Spec.RouteSpec.Hostname != "" && !utils.IsClusterLocal(capp.Spec.RouteSpec)
 }
 
 // Manage creates or updates a DNSRecord resource based on the provided Capp if it's required.
@@ -118,103 +123,197 @@ func (r DNSRecordManager) Manage(capp cappv1alpha1.Capp) error {
 	return r.CleanUp(capp)
 }
 
-// createOrUpdate creates or updates a DomainMapping resource.
+// createOrUpdate creates or updates the DNSRecord resources of every hostname of a Capp which it owns,
+// using the DNS backend which is set in the DNS ConfigMap. It returns ErrHostnameOwnershipUnknown once the
+// other hostnames are managed when the ownership of any hostname could not be verified.
 func (r DNSRecordManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	dnsRecordFromCapp, err := r.prepareResource(capp)
+	dnsConfig, err := utils.GetDNSConfig(r.Ctx, r.K8sclient)
 	if err != nil {
-		return fmt.Errorf("failed to prepare DNSRecord: %w", err)
+		return err
+	}
+
+	backend, err := getDNSBackend(dnsConfig)
+	if err != nil {
+		return err
+	}
+
+	options, err := r.getOptions(capp, dnsConfig)
+	if err != nil {
+		return err
 	}
 
-	dnsRecord := dnsrecordv1alpha1.CNAMERecord{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: r.Ctx, K8sclient: r.K8sclient, Log: r.Log}
+	claimed, err := r.claimHostnames(capp, zoneRouteHostnames(capp, options.zones), backend, options.txtOwnerID, resourceManager)
+	if err != nil {
+		return err
+	}
 
-	if err := r.K8sclient.Get(r.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: dnsRecordFromCapp.Name}, &dnsRecord); err != nil {
-		if errors.IsNotFound(err) {
-			if err := r.createDNSRecord(capp, dnsRecordFromCapp, resourceManager); err != nil {
+	for _, routeHostname := range claimed.owned {
+		hostnameOptions, err := options.forHostname(routeHostname.Hostname, backend)
+		if err != nil {
+			return err
+		}
+
+		for _, kind := range backend.requiredRecordKinds(hostnameOptions) {
+			if err := r.createOrUpdateDNSRecord(capp, routeHostname, kind, hostnameOptions, resourceManager); err != nil {
 				return err
 			}
-		} else {
-			return fmt.Errorf("failed to get DNSRecord %q: %w", dnsRecordFromCapp.Name, err)
 		}
 	}
 
-	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		if err := r.handlePreviousDNSRecords(capp, resourceManager, dnsRecordFromCapp.Name); err != nil {
-			return fmt.Errorf("failed to delete previous Certificates: %w", err)
+	if err := r.deletePreviousBackendDNSRecords(capp, backend, resourceManager); err != nil {
+		return fmt.Errorf("failed to delete DNSRecords of the previous DNS backend: %w", err)
+	}
+
+	if hasPreviousHostnames(capp) {
+		if err := r.handlePreviousDNSRecords(capp, backend, options, resourceManager, claimed); err != nil {
+			return fmt.Errorf("failed to delete previous DNSRecords: %w", err)
+		}
+	}
+
+	if len(claimed.unknown) > 0 {
+		var unknown []string
+		for _, routeHostname := range claimed.unknown {
+			unknown = append(unknown, routeHostname.Hostname)
+		}
+		return fmt.Errorf("%w: %s", ErrHostnameOwnershipUnknown, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// createOrUpdateDNSRecord creates or updates the DNSRecord resource of the given kind of a hostname of a Capp.
+func (r DNSRecordManager) createOrUpdateDNSRecord(capp cappv1alpha1.Capp, routeHostname RouteHostname, kind dnsRecordKind, options dnsRecordOptions, resourceManager rclient.ResourceManagerClient) error {
+	dnsRecordFromCapp, err := r.prepareResource(capp, routeHostname, kind, options)
+	if err != nil {
+		return fmt.Errorf("failed to prepare DNSRecord: %w", err)
+	}
+
+	dnsRecord := kind.newRecord(client.ObjectKeyFromObject(dnsRecordFromCapp))
+	if err := r.K8sclient.Get(r.Ctx, client.ObjectKeyFromObject(dnsRecordFromCapp), dnsRecord); err != nil {
+		if errors.IsNotFound(err) {
+			return r.createDNSRecord(capp, dnsRecordFromCapp, resourceManager)
 		}
+		return fmt.Errorf("failed to get DNSRecord %q: %w", dnsRecordFromCapp.GetName(), err)
+	}
+
+	if kind.updateRecord(dnsRecord, dnsRecordFromCapp) {
+		return resourceManager.UpdateResource(dnsRecord)
 	}
 
-	return r.updateDNSRecord(dnsRecord, dnsRecordFromCapp, resourceManager)
+	return nil
 }
 
 // createDNSRecord creates a new DNSRecord and emits an event.
-func (r DNSRecordManager) createDNSRecord(capp cappv1alpha1.Capp, dnsRecordFromCapp dnsrecordv1alpha1.CNAMERecord, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(&dnsRecordFromCapp); err != nil {
+func (r DNSRecordManager) createDNSRecord(capp cappv1alpha1.Capp, dnsRecordFromCapp client.Object, resourceManager rclient.ResourceManagerClient) error {
+	if err := resourceManager.CreateResource(dnsRecordFromCapp); err != nil {
 		r.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappDNSRecordCreationFailed,
-			fmt.Sprintf("Failed to create DNSRecord %s", dnsRecordFromCapp.Name))
+			fmt.Sprintf("Failed to create DNSRecord %s", dnsRecordFromCapp.GetName()))
 
 		return err
 	}
 
 	r.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventCappDNSRecordCreated,
-		fmt.Sprintf("Created DNSRecord %s", dnsRecordFromCapp.Name))
+		fmt.Sprintf("Created DNSRecord %s", dnsRecordFromCapp.GetName()))
 
 	return nil
 }
 
-// updateDNSRecord checks if an update to the DNSRecord is necessary and performs the update to match desired state.
-func (r DNSRecordManager) updateDNSRecord(dnsRecord, dnsRecordFromCapp dnsrecordv1alpha1.CNAMERecord, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(dnsRecord.Spec, dnsRecordFromCapp.Spec) {
-		dnsRecord.Spec = dnsRecordFromCapp.Spec
-		return resourceManager.UpdateResource(&dnsRecord)
+// dnsRecordKinds returns the kinds of the DNSRecords which a Capp may have while the given DNS backend is in use:
+// the kinds of the DNS backend and, when the DNS backend in the status of the Capp is another one, its kinds too.
+// The kinds of the other DNS backends are not listed, as the Capp has no DNSRecords of them.
+func dnsRecordKinds(capp cappv1alpha1.Capp, backend dnsBackend) []dnsRecordKind {
+	kinds := backend.recordKinds()
+	if previousBackend, ok := getDNSBackendByName(capp.Status.RouteStatus.DNSRecordObjectStatus.Backend); ok && previousBackend.name() != backend.name() {
+		kinds = append(kinds, previousBackend.recordKinds()...)
+	}
+
+	return kinds
+}
+
+// deletePreviousBackendDNSRecords deletes the DNSRecords of a Capp whose status names another DNS backend than the
+// given one. They are deleted right away rather than once the new DNSRecords are available, as only one DNS backend
+// can manage the DNS records of a hostname.
+func (r DNSRecordManager) deletePreviousBackendDNSRecords(capp cappv1alpha1.Capp, backend dnsBackend, resourceManager rclient.ResourceManagerClient) error {
+	previousBackend, ok := getDNSBackendByName(capp.Status.RouteStatus.DNSRecordObjectStatus.Backend)
+	if !ok || previousBackend.name() == backend.name() {
+		return nil
+	}
+
+	for _, kind := range previousBackend.recordKinds() {
+		if err := r.deletePreviousDNSRecords(capp, kind, resourceManager, nil); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// handlePreviousDNSRecords takes care of removing unneeded DNSRecord objects. If the new DNSRecord
-// is not yet available then return early and do not delete the previous Records.
-func (r DNSRecordManager) handlePreviousDNSRecords(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, name string) error {
-	available, err := utils.IsDNSRecordAvailable(r.Ctx, r.K8sclient, name, capp.Namespace)
-	if err != nil {
-		return err
+// handlePreviousDNSRecords takes care of removing unneeded DNSRecord objects of the given DNS backend, including
+// those of kinds which are no longer required with the given options, and releases the HostnameClaims of
+// hostnames which are no longer used. Only the kinds of the DNS backend are listed. If the DNSRecords of the
+// first owned hostname of the Capp are not yet available then return early and do not delete the previous Records.
+func (r DNSRecordManager) handlePreviousDNSRecords(capp cappv1alpha1.Capp, backend dnsBackend, options dnsRecordOptions, resourceManager rclient.ResourceManagerClient, claimed claimedHostnames) error {
+	if len(claimed.owned) > 0 {
+		available, err := isDNSRecordAvailable(r.Ctx, r.K8sclient, claimed.owned[0].Hostname, capp.Namespace)
+		if err != nil {
+			return err
+		}
+
+		if !available {
+			return nil
+		}
 	}
 
-	if !available {
-		return nil
+	required := map[schema.GroupVersionKind]bool{}
+	for _, kind := range backend.requiredRecordKinds(options) {
+		required[kind.groupVersionKind()] = true
 	}
 
-	dnsRecords, err := r.getPreviousDNSRecords(capp)
-	if err != nil {
-		return err
+	for _, kind := range backend.recordKinds() {
+		var hostnames map[string]bool
+		if required[kind.groupVersionKind()] {
+			hostnames = claimed.records()
+		}
+
+		if err := r.deletePreviousDNSRecords(capp, kind, resourceManager, hostnames); err != nil {
+			return err
+		}
 	}
 
-	return r.deletePreviousDNSRecords(dnsRecords, resourceManager, capp.Spec.RouteSpec.Hostname)
+	return r.releaseHostnameClaims(capp, claimed.claims(), resourceManager)
 }
 
-// getPreviousDNSRecords returns a list of all DNSRecord objects that are related to the given Capp.
-func (r DNSRecordManager) getPreviousDNSRecords(capp cappv1alpha1.Capp) (dnsrecordv1alpha1.CNAMERecordList, error) {
-	dnsRecords := dnsrecordv1alpha1.CNAMERecordList{}
-
+// getPreviousDNSRecords returns a list of all DNSRecord objects of a kind that are related to the given Capp.
+// Kinds whose API is not installed have no DNSRecords.
+func (r DNSRecordManager) getPreviousDNSRecords(capp cappv1alpha1.Capp, kind dnsRecordKind) ([]client.Object, error) {
 	set := labels.Set{
-		utils.CappResourceKey:  capp.Name,
-		utils.CappNamespaceKey: capp.Namespace,
+		parentLabelKey(r.ParentKey): capp.Name,
+		utils.CappNamespaceKey:      capp.Namespace,
 	}
 	listOptions := utils.GetListOptions(set)
 
-	if err := r.K8sclient.List(r.Ctx, &dnsRecords, &listOptions); err != nil {
-		return dnsRecords, fmt.Errorf("unable to list DNSRecords of Capp %q: %w", capp.Name, err)
+	dnsRecords, err := listDNSRecords(r.Ctx, r.K8sclient, kind, &listOptions)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to list DNSRecords of Capp %q: %w", capp.Name, err)
 	}
 
 	return dnsRecords, nil
 }
 
-// deletePreviousDNSRecords deletes all previous DNSRecords associated with a Capp.
-func (r DNSRecordManager) deletePreviousDNSRecords(dnsRecords dnsrecordv1alpha1.CNAMERecordList, resourceManager rclient.ResourceManagerClient, hostname string) error {
-	for _, dnsRecord := range dnsRecords.Items {
-		if dnsRecord.Name != hostname {
-			recordset := rclient.GetBareDNSRecord(dnsRecord.Name)
-			if err := resourceManager.DeleteResource(&recordset); err != nil {
+// deletePreviousDNSRecords deletes all DNSRecords of a kind associated with a Capp which are not of one of the given hostnames.
+func (r DNSRecordManager) deletePreviousDNSRecords(capp cappv1alpha1.Capp, kind dnsRecordKind, resourceManager rclient.ResourceManagerClient, hostnames map[string]bool) error {
+	dnsRecords, err := r.getPreviousDNSRecords(capp, kind)
+	if err != nil {
+		return err
+	}
+
+	for _, dnsRecord := range dnsRecords {
+		if !hostnames[dnsRecord.GetName()] {
+			if err := resourceManager.DeleteResource(kind.newRecord(client.ObjectKeyFromObject(dnsRecord))); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,309 @@
+package resourcemanagers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	dnsrecordsetv1alpha1 "github.com/dana-team/provider-dns/apis/recordset/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newDNSRecordManager(backend string, objects ...client.Object) (DNSRecordManager, client.Client) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	_ = dnsrecordsetv1alpha1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data: map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default",
+			"issuer": "cert-issuer", "backend": backend},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(append(objects, dnsConfig)...).
+		WithStatusSubresource(&cappv1alpha1.HostnameClaim{}).Build()
+
+	return DNSRecordManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}, k8sClient
+}
+
+func TestManageDNSEndpoints(t *testing.T) {
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendExternalDNS)
+	capp := newTaggedCapp()
+	assert.NoError(t, manager.Manage(capp))
+
+	dnsEndpoint := dnsEndpointKind{}.newRecord(types.NamespacedName{Namespace: "test-ns", Name: "app.capp-zone.com"}).(*unstructured.Unstructured)
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(dnsEndpoint), dnsEndpoint))
+	assert.Equal(t, "test-capp", dnsEndpoint.GetLabels()[utils.CappResourceKey])
+
+	endpoints, _, _ := unstructured.NestedSlice(dnsEndpoint.Object, "spec", "endpoints")
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"dnsName":    "app.capp-zone.com",
+		"recordType": "CNAME",
+		"targets":    []interface{}{"ingress.capp-zone.com"},
+	}}, endpoints)
+
+	dnsRecordStatus, err := GetDNSRecordStatus(context.Background(), k8sClient, "app.capp-zone.com", "test-ns")
+	assert.NoError(t, err)
+	assert.Equal(t, utils.DNSBackendExternalDNS, dnsRecordStatus.Backend)
+	assert.False(t, IsDNSRecordStatusAvailable(dnsRecordStatus))
+
+	assert.NoError(t, manager.CleanUp(capp))
+	assert.Error(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(dnsEndpoint), dnsEndpoint))
+}
+
+func TestManageDNSRecordsWhenChangingDNSBackend(t *testing.T) {
+	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{Name: "app.capp-zone.com",
+		Labels: map[string]string{utils.CappResourceKey: "test-capp", utils.CappNamespaceKey: "test-ns"}}}
+	cnameRecord.Status.SetConditions(xpcommonv1.Available())
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendExternalDNS, cnameRecord)
+
+	capp := newTaggedCapp()
+	capp.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP("app.capp-zone.com")
+	capp.Status.RouteStatus.DNSRecordObjectStatus.Backend = utils.DNSBackendProviderDNS
+	assert.NoError(t, manager.Manage(capp))
+
+	// The CNAMERecord is deleted right away, as external-dns can only manage the DNS records of the hostname without it.
+	assert.Error(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cnameRecord), cnameRecord))
+
+	dnsEndpoint := dnsEndpointKind{}.newRecord(types.NamespacedName{Namespace: "test-ns", Name: "app.capp-zone.com"}).(*unstructured.Unstructured)
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(dnsEndpoint), dnsEndpoint))
+}
+
+func TestManageDNSRecordsListsOnlyKindsOfDNSBackend(t *testing.T) {
+	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{Name: "old.capp-zone.com",
+		Labels: map[string]string{utils.CappResourceKey: "test-capp", utils.CappNamespaceKey: "test-ns"}}}
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendExternalDNS, cnameRecord)
+
+	capp := newTaggedCapp()
+	capp.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP("app.capp-zone.com")
+	capp.Status.RouteStatus.DNSRecordObjectStatus.Backend = utils.DNSBackendExternalDNS
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, manager.CleanUp(capp))
+
+	// The Capp has no DNSRecords of the DNS backends which are neither in use nor in its status, so they are not listed.
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cnameRecord), cnameRecord))
+	assert.Len(t, dnsRecordKinds(capp, externalDNSBackend{}), len(externalDNSBackend{}.recordKinds()))
+	assert.Len(t, dnsRecordKinds(capp, providerDNSBackend{}), len(allDNSRecordKinds()))
+}
+
+func TestGetDNSBackend(t *testing.T) {
+	backend, err := getDNSBackend(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, utils.DNSBackendProviderDNS, backend.name())
+
+	backend, err = getDNSBackend(map[string]string{"backend": utils.DNSBackendExternalDNS})
+	assert.NoError(t, err)
+	assert.Equal(t, utils.DNSBackendExternalDNS, backend.name())
+
+	_, err = getDNSBackend(map[string]string{"backend": "route53"})
+	assert.Error(t, err)
+}
+
+// withOwnershipRecords replaces the DNS with one which only holds the given ownership TXT records, and returns
+// the number of lookups. The names without records fail to be looked up when unavailable is set.
+func withOwnershipRecords(t *testing.T, records map[string][]string, unavailable bool) *int {
+	lookups := 0
+	previousLookupTXT := lookupTXT
+	lookupTXT = func(_ context.Context, name string) ([]string, error) {
+		lookups++
+		if values, ok := records[name]; ok {
+			return values, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: !unavailable, IsTemporary: unavailable}
+	}
+	t.Cleanup(func() { lookupTXT = previousLookupTXT })
+
+	return &lookups
+}
+
+func TestManageAddressAndOwnershipRecords(t *testing.T) {
+	withOwnershipRecords(t, nil, false)
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendProviderDNS)
+	dnsConfig := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: utils.CappNS, Name: "dns-config"}, dnsConfig))
+	dnsConfig.Data["addresses"] = "10.0.0.1, 10.0.0.2,fd00::1"
+	dnsConfig.Data["ttl"] = "300"
+	dnsConfig.Data["txtOwnerID"] = "cluster-a"
+	assert.NoError(t, k8sClient.Update(context.Background(), dnsConfig))
+
+	capp := newTaggedCapp()
+	ttl := int64(60)
+	capp.Spec.RouteSpec.DNSRecordTTLSeconds = &ttl
+	assert.NoError(t, manager.Manage(capp))
+
+	key := client.ObjectKey{Name: "app.capp-zone.com"}
+	aRecordSet := dnsrecordsetv1alpha1.ARecordSet{}
+	assert.NoError(t, k8sClient.Get(context.Background(), key, &aRecordSet))
+	assert.Equal(t, []*string{ptr.To("10.0.0.1"), ptr.To("10.0.0.2")}, aRecordSet.Spec.ForProvider.Addresses)
+	assert.Equal(t, ttl, *aRecordSet.Spec.ForProvider.TTL)
+
+	aaaaRecordSet := dnsrecordsetv1alpha1.AAAARecordSet{}
+	assert.NoError(t, k8sClient.Get(context.Background(), key, &aaaaRecordSet))
+	assert.Equal(t, []*string{ptr.To("fd00::1")}, aaaaRecordSet.Spec.ForProvider.Addresses)
+
+	txtRecordSet := dnsrecordsetv1alpha1.TXTRecordSet{}
+	assert.NoError(t, k8sClient.Get(context.Background(), key, &txtRecordSet))
+	assert.Equal(t, "_capp-owner.app", *txtRecordSet.Spec.ForProvider.Name)
+	assert.Equal(t, "heritage=container-app-operator,container-app-operator/owner=cluster-a,container-app-operator/resource=f49e424a4b5632d0",
+		*txtRecordSet.Spec.ForProvider.Txt[0])
+
+	assert.Error(t, k8sClient.Get(context.Background(), key, &dnsrecordv1alpha1.CNAMERecord{}))
+
+	dnsRecordStatus, err := GetDNSRecordStatus(context.Background(), k8sClient, "app.capp-zone.com", "test-ns")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{RecordTypeA, RecordTypeAAAA, RecordTypeTXT}, dnsRecordStatus.RecordTypes)
+}
+
+func TestManageDNSRecordsOwnedByOtherCluster(t *testing.T) {
+	lookups := withOwnershipRecords(t, map[string][]string{
+		"_capp-owner.app.capp-zone.com": {"heritage=container-app-operator,container-app-operator/owner=cluster-b,container-app-operator/resource=0123456789abcdef"},
+		"_capp-owner.old.capp-zone.com": {"v=spf1 -all"},
+	}, false)
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendProviderDNS)
+	dnsConfig := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: utils.CappNS, Name: "dns-config"}, dnsConfig))
+	dnsConfig.Data["txtOwnerID"] = "cluster-a"
+	assert.NoError(t, k8sClient.Update(context.Background(), dnsConfig))
+
+	capp := newTaggedCapp()
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"old.capp-zone.com"}
+	assert.NoError(t, manager.Manage(capp))
+
+	// The DNS records of the hostname owned by the other cluster are not overwritten, and its owner is recorded
+	// on its HostnameClaim, so that the other managers leave its objects to the other cluster without a lookup.
+	assert.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.capp-zone.com"}, &dnsrecordv1alpha1.CNAMERecord{}))
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "old.capp-zone.com"}, &dnsrecordv1alpha1.CNAMERecord{}))
+	assert.True(t, hasEvent(manager.EventRecorder.(*record.FakeRecorder), "Hostname app.capp-zone.com is owned by cluster cluster-b"))
+
+	claim := cappv1alpha1.HostnameClaim{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.Equal(t, cappv1alpha1.HostnameClaimStatus{OwnershipVerified: true, ClusterOwner: "cluster-b"}, claim.Status)
+
+	owner, err := GetHostnameConflict(context.Background(), k8sClient, "app.capp-zone.com", capp, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster cluster-b", owner)
+	assert.Equal(t, 2, *lookups)
+
+	// The ownership of a hostname which is owned by the cluster is only looked up once, while that of a hostname
+	// owned by another cluster is looked up again until it is released.
+	assert.NoError(t, manager.Manage(capp))
+	assert.Equal(t, 3, *lookups)
+
+	owner, err = GetHostnameConflict(context.Background(), k8sClient, "old.capp-zone.com", capp, "")
+	assert.NoError(t, err)
+	assert.Empty(t, owner)
+}
+
+func TestManageDNSRecordsOfUnknownOwnership(t *testing.T) {
+	withOwnershipRecords(t, nil, true)
+	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{Name: "app.capp-zone.com",
+		Labels: map[string]string{utils.CappResourceKey: "test-capp", utils.CappNamespaceKey: "test-ns"}}}
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendProviderDNS, cnameRecord)
+	dnsConfig := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: utils.CappNS, Name: "dns-config"}, dnsConfig))
+	dnsConfig.Data["txtOwnerID"] = "cluster-a"
+	assert.NoError(t, k8sClient.Update(context.Background(), dnsConfig))
+
+	capp := newTaggedCapp()
+	capp.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP("app.capp-zone.com")
+	err := manager.Manage(capp)
+	assert.ErrorIs(t, err, ErrHostnameOwnershipUnknown)
+
+	// The hostname stays claimed and its DNS records are kept as they are until its ownership is verified.
+	claim := cappv1alpha1.HostnameClaim{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.False(t, claim.Status.OwnershipVerified)
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cnameRecord), cnameRecord))
+
+	withOwnershipRecords(t, nil, false)
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.Equal(t, cappv1alpha1.HostnameClaimStatus{OwnershipVerified: true}, claim.Status)
+}
+
+func TestDNSRecordReadyCondition(t *testing.T) {
+	dnsRecordStatus := cappv1alpha1.DNSRecordObjectStatus{Backend: utils.DNSBackendProviderDNS, RecordTypes: []string{RecordTypeA, RecordTypeTXT}}
+	dnsRecordStatus.ARecordSetObjectStatus.SetConditions(xpcommonv1.Available())
+	dnsRecordStatus.TXTRecordSetObjectStatus.SetConditions(xpcommonv1.Unavailable().WithMessage("zone not found"))
+
+	readyCondition := DNSRecordReadyCondition(dnsRecordStatus)
+	assert.Equal(t, corev1.ConditionFalse, readyCondition.Status)
+	assert.Equal(t, "TXT record: zone not found", readyCondition.Message)
+
+	dnsRecordStatus.TXTRecordSetObjectStatus.SetConditions(xpcommonv1.Available())
+	assert.True(t, IsDNSRecordStatusAvailable(dnsRecordStatus))
+
+	// The status of Capps from before record types were reported only holds a CNAMERecord.
+	assert.False(t, IsDNSRecordStatusAvailable(cappv1alpha1.DNSRecordObjectStatus{}))
+}
+
+func TestDNSEndpointOfAddressAndOwnershipRecords(t *testing.T) {
+	options := dnsRecordOptions{zone: testZone, addresses: []net.IP{net.ParseIP("10.0.0.1")}, ttl: ptr.To(int64(300)),
+		txtOwnerID: "cluster-a", owner: types.NamespacedName{Namespace: "test-ns", Name: "test-capp"}}
+	record, err := dnsEndpointKind{}.prepareRecord(types.NamespacedName{Namespace: "test-ns", Name: "app.capp-zone.com"}, "app.capp-zone.com", nil, options)
+	assert.NoError(t, err)
+
+	endpoints, _, _ := unstructured.NestedSlice(record.(*unstructured.Unstructured).Object, "spec", "endpoints")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"dnsName": "app.capp-zone.com", "recordType": "A", "targets": []interface{}{"10.0.0.1"}, "recordTTL": int64(300)},
+		map[string]interface{}{"dnsName": "_capp-owner.app.capp-zone.com", "recordType": "TXT", "recordTTL": int64(300),
+			"targets": []interface{}{"heritage=container-app-operator,container-app-operator/owner=cluster-a,container-app-operator/resource=f49e424a4b5632d0"}},
+	}, endpoints)
+}
+
+func TestManageDNSRecordsInZones(t *testing.T) {
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendProviderDNS)
+	dnsConfig := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: utils.CappNS, Name: "dns-config"}, dnsConfig))
+	dnsConfig.Data["zones"] = `
+- name: internal.capp-zone.com.
+  cname: ingress.internal.capp-zone.com.
+  provider: dns-internal
+  namespaces: [test-ns]
+`
+	assert.NoError(t, k8sClient.Update(context.Background(), dnsConfig))
+
+	capp := newTaggedCapp()
+	capp.Spec.RouteSpec.Hostname = "app.internal.capp-zone.com"
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"legacy"}
+	assert.NoError(t, manager.Manage(capp))
+
+	cnameRecord := dnsrecordv1alpha1.CNAMERecord{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.internal.capp-zone.com"}, &cnameRecord))
+	assert.Equal(t, "app", *cnameRecord.Spec.ForProvider.Name)
+	assert.Equal(t, "internal.capp-zone.com.", *cnameRecord.Spec.ForProvider.Zone)
+	assert.Equal(t, "ingress.internal.capp-zone.com.", *cnameRecord.Spec.ForProvider.Cname)
+	assert.Equal(t, "dns-internal", cnameRecord.Spec.ProviderConfigReference.Name)
+
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "legacy.capp-zone.com"}, &cnameRecord))
+	assert.Equal(t, "ingress.capp-zone.com.", *cnameRecord.Spec.ForProvider.Cname)
+	assert.Equal(t, "dns-default", cnameRecord.Spec.ProviderConfigReference.Name)
+	cnameRecord.Status.SetConditions(xpcommonv1.Available())
+	assert.NoError(t, k8sClient.Update(context.Background(), &cnameRecord))
+
+	// The zone may not be used by Capps of other namespaces, even if they were admitted before the zone was restricted.
+	// Only the hostnames in the zone are skipped, and their DNSRecords are removed like those of previous hostnames.
+	dnsConfig.Data["zones"] = strings.Replace(dnsConfig.Data["zones"], "[test-ns]", "[other-ns]", 1)
+	assert.NoError(t, k8sClient.Update(context.Background(), dnsConfig))
+	capp.Status.RouteStatus.Hostnames = []cappv1alpha1.HostnameStatus{{Hostname: "app.internal.capp-zone.com"}, {Hostname: "legacy.capp-zone.com"}}
+	assert.NoError(t, manager.Manage(capp))
+	assert.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.internal.capp-zone.com"}, &cnameRecord))
+	assert.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "app.internal.capp-zone.com"}, &cappv1alpha1.HostnameClaim{}))
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "legacy.capp-zone.com"}, &cnameRecord))
+}
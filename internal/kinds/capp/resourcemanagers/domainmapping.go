@@ -29,6 +29,10 @@ const (
 	eventCappDomainMappingCreationFailed = "DomainMappingCreationFailed"
 	eventCappDomainMappingCreated        = "DomainMappingCreated"
 	referenceKind                        = "Service"
+	tagServiceAPIVersion                 = "v1"
+
+	// disableRouteAnnotationKey prevents OpenShift Serverless from creating a Route for a DomainMapping.
+	disableRouteAnnotationKey = "serving.knative.openshift.io/disableRoute"
 )
 
 type KnativeDomainMappingManager struct {
@@ -36,22 +40,27 @@ type KnativeDomainMappingManager struct {
 	K8sclient     client.Client
 	Log           logr.Logger
 	EventRecorder record.EventRecorder
+	Routing       RoutingConfig
 }
 
-// PrepareKnativeDomainMapping creates a new DomainMapping for a Knative service.
-func (k KnativeDomainMappingManager) prepareResource(capp cappv1alpha1.Capp) (knativev1beta1.DomainMapping, error) {
+// PrepareKnativeDomainMapping creates a new DomainMapping for a hostname of a Knative service. The DomainMapping
+// of a tagged hostname points at the Kubernetes Service which Knative creates for the tag, "<tag>-<name>".
+// While the Capp is under maintenance, every DomainMapping points at its maintenance Service instead.
+// It uses the TLS secret of the Certificate which covers the hostname, also when the hostname is exposed
+// using an OpenShift Route, which passes TLS through to the Knative ingress.
+func (k KnativeDomainMappingManager) prepareResource(capp cappv1alpha1.Capp, routeHostname RouteHostname) (knativev1beta1.DomainMapping, error) {
 	dnsConfig, err := utils.GetDNSConfig(k.Ctx, k.K8sclient)
 	if err != nil {
 		return knativev1beta1.DomainMapping{}, err
 	}
 
-	zone, err := utils.GetZoneFromConfig(dnsConfig)
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
 	if err != nil {
 		return knativev1beta1.DomainMapping{}, err
 	}
 
-	resourceName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
-	secretName := utils.GenerateSecretName(resourceName)
+	resourceName := zones.ResourceName(routeHostname.Hostname)
+	secretName := utils.GenerateSecretName(zones.ResourceName(routeHostname.CertificateName))
 
 	knativeDomainMapping := &knativev1beta1.DomainMapping{
 		TypeMeta: metav1.TypeMeta{},
@@ -72,6 +81,26 @@ func (k KnativeDomainMappingManager) prepareResource(capp cappv1alpha1.Capp) (kn
 		},
 	}
 
+	if routeHostname.Tag != "" {
+		knativeDomainMapping.Spec.Ref = duckv1.KReference{
+			APIVersion: tagServiceAPIVersion,
+			Name:       utils.GenerateTagHostname(routeHostname.Tag, capp.Name),
+			Kind:       referenceKind,
+		}
+	}
+
+	if isUnderMaintenance(capp, k.Routing) {
+		knativeDomainMapping.Spec.Ref = duckv1.KReference{
+			APIVersion: tagServiceAPIVersion,
+			Name:       MaintenanceServiceName(capp.Name),
+			Kind:       referenceKind,
+		}
+	}
+
+	if isRoutingBackend(k.Routing.Backend, RoutingBackendOpenShiftRoute) {
+		knativeDomainMapping.Annotations = map[string]string{disableRouteAnnotationKey: "true"}
+	}
+
 	if tlsEnabled := capp.Spec.RouteSpec.TlsEnabled; tlsEnabled {
 		if err := k.setHTTPSKnativeDomainMapping(secretName, capp.Namespace, knativeDomainMapping); err != nil {
 			if !errors.IsNotFound(err) {
@@ -103,16 +132,13 @@ func (k KnativeDomainMappingManager) setHTTPSKnativeDomainMapping(secretName, se
 	return nil
 }
 
-// CleanUp attempts to delete the associated DomainMapping and tls secret for a given Capp resource.
+// CleanUp attempts to delete the associated DomainMappings and tls secrets for a given Capp resource.
 func (k KnativeDomainMappingManager) CleanUp(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
 
 	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
 		domainMapping := rclient.GetBareDomainMapping(capp.Status.RouteStatus.DomainMappingObjectStatus.URL.Host, capp.Namespace)
-		if err := resourceManager.DeleteResource(&domainMapping); err != nil {
-			if errors.IsNotFound(err) {
-				return nil
-			}
+		if err := resourceManager.DeleteResource(&domainMapping); err != nil && !errors.IsNotFound(err) {
 			return err
 		}
 
@@ -120,12 +146,19 @@ func (k KnativeDomainMappingManager) CleanUp(capp cappv1alpha1.Capp) error {
 			return err
 		}
 	}
-	return nil
+
+	domainMappings, err := k.getPreviousDomainMappings(capp)
+	if err != nil {
+		return err
+	}
+
+	return k.deletePreviousDomainMappings(domainMappings, resourceManager, nil)
 }
 
 // IsRequired is responsible to determine if resource DomainMapping is required.
 func (k KnativeDomainMappingManager) IsRequired(capp cappv1alpha1.Capp) bool {
-	return capp.Spec.RouteSpec.Hostname != ""
+	return isHostnameRouted(capp) && (isRoutingBackend(k.Routing.Backend, RoutingBackendDomainMapping) ||
+		isRoutingBackend(k.Routing.Backend, RoutingBackendOpenShiftRoute))
 }
 
 // Manage creates or updates a DomainMapping resource based on the provided Capp if it's required.
@@ -138,32 +171,44 @@ func (k KnativeDomainMappingManager) Manage(capp cappv1alpha1.Capp) error {
 	return k.CleanUp(capp)
 }
 
-// createOrUpdate creates or updates a DomainMapping resource.
+// createOrUpdate creates or updates the DomainMapping resources of every hostname which a Capp owns.
 func (k KnativeDomainMappingManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	domainMappingFromCapp, err := k.prepareResource(capp)
+	routeHostnames, err := getRouteHostnames(k.Ctx, k.K8sclient, capp, "")
 	if err != nil {
-		return fmt.Errorf("failed to prepare DomainMapping: %w", err)
+		return fmt.Errorf("failed to get hostnames: %w", err)
 	}
 
-	domainMapping := knativev1beta1.DomainMapping{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
-
-	if err := k.K8sclient.Get(k.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingFromCapp.Name}, &domainMapping); err != nil {
-		if errors.IsNotFound(err) {
-			if err := k.createDomainMapping(capp, domainMappingFromCapp, resourceManager); err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("failed to get DomainMapping %q: %w", domainMappingFromCapp.Name, err)
+	for _, routeHostname := range routeHostnames {
+		if err := k.createOrUpdateDomainMapping(capp, routeHostname, resourceManager); err != nil {
+			return err
 		}
 	}
 
-	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		if err := k.handlePreviousDomainMappings(capp, resourceManager, domainMappingFromCapp.Name); err != nil {
+	if hasPreviousHostnames(capp) {
+		if err := k.handlePreviousDomainMappings(capp, resourceManager, routeHostnames); err != nil {
 			return fmt.Errorf("failed to delete previous DomainMappings: %w", err)
 		}
 	}
 
+	return nil
+}
+
+// createOrUpdateDomainMapping creates or updates the DomainMapping resource of a hostname of a Capp.
+func (k KnativeDomainMappingManager) createOrUpdateDomainMapping(capp cappv1alpha1.Capp, routeHostname RouteHostname, resourceManager rclient.ResourceManagerClient) error {
+	domainMappingFromCapp, err := k.prepareResource(capp, routeHostname)
+	if err != nil {
+		return fmt.Errorf("failed to prepare DomainMapping: %w", err)
+	}
+
+	domainMapping := knativev1beta1.DomainMapping{}
+	if err := k.K8sclient.Get(k.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingFromCapp.Name}, &domainMapping); err != nil {
+		if errors.IsNotFound(err) {
+			return k.createDomainMapping(capp, domainMappingFromCapp, resourceManager)
+		}
+		return fmt.Errorf("failed to get DomainMapping %q: %w", domainMappingFromCapp.Name, err)
+	}
+
 	return k.updateDomainMapping(domainMapping, domainMappingFromCapp, resourceManager)
 }
 
@@ -184,8 +229,17 @@ func (k KnativeDomainMappingManager) createDomainMapping(capp cappv1alpha1.Capp,
 
 // updateDomainMapping checks if an update to the DomainMapping is necessary and performs the update to match desired state.
 func (k KnativeDomainMappingManager) updateDomainMapping(knativeDomainMapping, domainMappingFromCapp knativev1beta1.DomainMapping, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(knativeDomainMapping.Spec, domainMappingFromCapp.Spec) {
+	disableRoute := domainMappingFromCapp.Annotations[disableRouteAnnotationKey]
+	if !reflect.DeepEqual(knativeDomainMapping.Spec, domainMappingFromCapp.Spec) || knativeDomainMapping.Annotations[disableRouteAnnotationKey] != disableRoute {
 		knativeDomainMapping.Spec = domainMappingFromCapp.Spec
+		if disableRoute != "" {
+			if knativeDomainMapping.Annotations == nil {
+				knativeDomainMapping.Annotations = map[string]string{}
+			}
+			knativeDomainMapping.Annotations[disableRouteAnnotationKey] = disableRoute
+		} else {
+			delete(knativeDomainMapping.Annotations, disableRouteAnnotationKey)
+		}
 		return resourceManager.UpdateResource(&knativeDomainMapping)
 	}
 
@@ -195,17 +249,16 @@ func (k KnativeDomainMappingManager) updateDomainMapping(knativeDomainMapping, d
 // handlePreviousDomainMappings takes care of removing unneeded DomainMapping objects. If the DNSRecord
 // which corresponds to the latest DomainMapping object is not yet available then return early
 // and do not delete the previous DomainMappings.
-func (k KnativeDomainMappingManager) handlePreviousDomainMappings(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, name string) error {
-	var available bool
-	var err error
-
-	available, err = utils.IsDNSRecordAvailable(k.Ctx, k.K8sclient, name, capp.Namespace)
-	if err != nil {
-		return err
-	}
+func (k KnativeDomainMappingManager) handlePreviousDomainMappings(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, routeHostnames []RouteHostname) error {
+	if len(routeHostnames) > 0 {
+		available, err := isDNSRecordAvailable(k.Ctx, k.K8sclient, routeHostnames[0].Hostname, capp.Namespace)
+		if err != nil {
+			return err
+		}
 
-	if !available {
-		return nil
+		if !available {
+			return nil
+		}
 	}
 
 	domainMappings, err := k.getPreviousDomainMappings(capp)
@@ -213,7 +266,7 @@ func (k KnativeDomainMappingManager) handlePreviousDomainMappings(capp cappv1alp
 		return err
 	}
 
-	return k.deletePreviousDomainMappings(domainMappings, resourceManager, capp.Spec.RouteSpec.Hostname)
+	return k.deletePreviousDomainMappings(domainMappings, resourceManager, hostnameSet(routeHostnames))
 }
 
 // getPreviousDomainMappings returns a list of all DomainMapping objects that are related to the given Capp.
@@ -225,6 +278,7 @@ func (k KnativeDomainMappingManager) getPreviousDomainMappings(capp cappv1alpha1
 	}
 
 	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
 	if err := k.K8sclient.List(k.Ctx, &knativeDomainMappings, &listOptions); err != nil {
 		return knativeDomainMappings, fmt.Errorf("unable to list DomainMappings of Capp %q: %w", capp.Name, err)
 	}
@@ -232,14 +286,17 @@ func (k KnativeDomainMappingManager) getPreviousDomainMappings(capp cappv1alpha1
 	return knativeDomainMappings, nil
 }
 
-// deletePreviousDomainMappings deletes all previous DomainMappings associated with a Capp.
-func (k KnativeDomainMappingManager) deletePreviousDomainMappings(knativeDomainMappings knativev1beta1.DomainMappingList, resourceManager rclient.ResourceManagerClient, hostname string) error {
+// deletePreviousDomainMappings deletes all DomainMappings associated with a Capp which are not of one of the
+// given hostnames, together with their tls secrets.
+func (k KnativeDomainMappingManager) deletePreviousDomainMappings(knativeDomainMappings knativev1beta1.DomainMappingList, resourceManager rclient.ResourceManagerClient, hostnames map[string]bool) error {
 	for _, domainMapping := range knativeDomainMappings.Items {
-		if domainMapping.Name != hostname {
-			dm := rclient.GetBareDomainMapping(domainMapping.Name, domainMapping.Namespace)
-			if err := resourceManager.DeleteResource(&dm); err != nil {
-				return err
-			}
+		if hostnames[domainMapping.Name] {
+			continue
+		}
+
+		dm := rclient.GetBareDomainMapping(domainMapping.Name, domainMapping.Namespace)
+		if err := resourceManager.DeleteResource(&dm); err != nil {
+			return err
 		}
 		if err := deleteTLSSecret(resourceManager.Ctx, resourceManager.K8sclient, utils.GenerateSecretName(domainMapping.Name), domainMapping.Namespace); err != nil {
 			return err
@@ -0,0 +1,126 @@
+package resourcemanagers
+
+import (
+	"strings"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DNSEndpointGroupVersionKind is the GroupVersionKind of the external-dns DNSEndpoint.
+var DNSEndpointGroupVersionKind = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// externalDNSBackend manages the DNS records of hostnames using external-dns DNSEndpoints, which are created
+// in the namespace of their owner, with one DNSEndpoint holding all the DNS records of a hostname.
+type externalDNSBackend struct{}
+
+func (externalDNSBackend) name() string {
+	return utils.DNSBackendExternalDNS
+}
+
+func (externalDNSBackend) recordKinds() []dnsRecordKind {
+	return []dnsRecordKind{dnsEndpointKind{}}
+}
+
+func (externalDNSBackend) requiredRecordKinds(_ dnsRecordOptions) []dnsRecordKind {
+	return []dnsRecordKind{dnsEndpointKind{}}
+}
+
+// dnsEndpointKind is the DNSEndpoint of a hostname. The API of external-dns is used as unstructured objects.
+type dnsEndpointKind struct{}
+
+func (dnsEndpointKind) groupVersionKind() schema.GroupVersionKind {
+	return DNSEndpointGroupVersionKind
+}
+
+func (dnsEndpointKind) recordKey(hostname, namespace string) types.NamespacedName {
+	return types.NamespacedName{Namespace: namespace, Name: hostname}
+}
+
+func (dnsEndpointKind) newRecord(key types.NamespacedName) client.Object {
+	record := &unstructured.Unstructured{}
+	record.SetGroupVersionKind(DNSEndpointGroupVersionKind)
+	record.SetName(key.Name)
+	record.SetNamespace(key.Namespace)
+
+	return record
+}
+
+func (dnsEndpointKind) newRecordList() client.ObjectList {
+	records := &unstructured.UnstructuredList{}
+	records.SetGroupVersionKind(DNSEndpointGroupVersionKind.GroupVersion().WithKind(DNSEndpointGroupVersionKind.Kind + "List"))
+
+	return records
+}
+
+func (k dnsEndpointKind) prepareRecord(key types.NamespacedName, hostname string, recordLabels map[string]string, options dnsRecordOptions) (client.Object, error) {
+	record := k.newRecord(key).(*unstructured.Unstructured)
+	record.SetLabels(recordLabels)
+
+	var endpoints []interface{}
+	for _, recordType := range options.recordTypes() {
+		dnsName := hostname
+		var targets []string
+
+		switch recordType {
+		case RecordTypeCNAME:
+			targets = []string{strings.TrimSuffix(options.cname, ".")}
+		case RecordTypeA, RecordTypeAAAA:
+			targets = options.addressesOfType(recordType)
+		case RecordTypeTXT:
+			dnsName = OwnershipRecordPrefix + hostname
+			targets = []string{options.ownershipRecordValue()}
+		}
+
+		endpoint := map[string]interface{}{
+			"dnsName":    dnsName,
+			"recordType": recordType,
+			"targets":    stringSliceToInterfaces(targets),
+		}
+		if options.ttl != nil {
+			endpoint["recordTTL"] = *options.ttl
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := unstructured.SetNestedSlice(record.Object, endpoints, "spec", "endpoints"); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (dnsEndpointKind) updateRecord(existing, desired client.Object) bool {
+	existingRecord := existing.(*unstructured.Unstructured)
+	desiredRecord := desired.(*unstructured.Unstructured)
+	if equality.Semantic.DeepEqual(existingRecord.Object["spec"], desiredRecord.Object["spec"]) {
+		return false
+	}
+
+	existingRecord.Object["spec"] = desiredRecord.Object["spec"]
+	return true
+}
+
+func (dnsEndpointKind) setRecordStatus(record client.Object, dnsRecordStatus *cappv1alpha1.DNSRecordObjectStatus) {
+	observedGeneration, _, _ := unstructured.NestedInt64(record.(*unstructured.Unstructured).Object, "status", "observedGeneration")
+
+	dnsRecordStatus.DNSEndpointObjectStatus = cappv1alpha1.DNSEndpointObjectStatus{
+		Generation:         record.GetGeneration(),
+		ObservedGeneration: observedGeneration,
+	}
+}
+
+// stringSliceToInterfaces converts a slice of strings to a slice which can be set in an unstructured object.
+func stringSliceToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+
+	return result
+}
@@ -0,0 +1,307 @@
+package resourcemanagers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	eventHostnameConflict   = "HostnameConflict"
+	hostnameClaimKindCapp   = "Capp"
+	hostnameClaimKindRouter = "CappRouter"
+)
+
+// ErrHostnameOwnershipUnknown is returned when the ownership TXT record of a hostname could not be looked up, so that
+// its owner is reconciled again later rather than failing.
+var ErrHostnameOwnershipUnknown = stderrors.New("ownership of hostname is unknown")
+
+// HostnameClaimReleasedPredicate filters the events of HostnameClaims down to their deletions, after which
+// a Capp or CappRouter which had a conflict on the hostname may claim it.
+var HostnameClaimReleasedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// hostnameClaimOwner returns the owner of the HostnameClaims of a Capp, which stands for a CappRouter
+// when the parentKey is utils.RouterResourceKey.
+func hostnameClaimOwner(capp cappv1alpha1.Capp, parentKey string) cappv1alpha1.HostnameClaimOwner {
+	kind := hostnameClaimKindCapp
+	if parentKey == utils.RouterResourceKey {
+		kind = hostnameClaimKindRouter
+	}
+
+	return cappv1alpha1.HostnameClaimOwner{Kind: kind, Namespace: capp.Namespace, Name: capp.Name}
+}
+
+// getHostnameClaim returns the HostnameClaim of a hostname, and nil if it was not claimed.
+func getHostnameClaim(ctx context.Context, k8sClient client.Client, hostname string) (*cappv1alpha1.HostnameClaim, error) {
+	claim := cappv1alpha1.HostnameClaim{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: hostname}, &claim); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get HostnameClaim %q: %w", hostname, err)
+	}
+
+	return &claim, nil
+}
+
+// GetHostnameClaimConflict returns the owner of the HostnameClaim of a hostname if it was claimed by another
+// owner than the given Capp, or a CappRouter when the parentKey is utils.RouterResourceKey, and nil otherwise.
+func GetHostnameClaimConflict(ctx context.Context, k8sClient client.Client, hostname string, capp cappv1alpha1.Capp, parentKey string) (*cappv1alpha1.HostnameClaimOwner, error) {
+	claim, err := getHostnameClaim(ctx, k8sClient, hostname)
+	if err != nil || claim == nil {
+		return nil, err
+	}
+
+	if claim.Spec.Owner == hostnameClaimOwner(capp, parentKey) {
+		return nil, nil
+	}
+
+	return &claim.Spec.Owner, nil
+}
+
+// GetHostnameConflict returns the owner of a hostname if it is owned by another owner than the given Capp, or a
+// CappRouter when the parentKey is utils.RouterResourceKey, and an empty string otherwise. The owner is either the
+// Capp or CappRouter which claimed the hostname first, or another cluster which owns the ownership TXT record of
+// the hostname, as recorded on its HostnameClaim by the DNSRecordManager, so that the DNS is not looked up.
+func GetHostnameConflict(ctx context.Context, k8sClient client.Client, hostname string, capp cappv1alpha1.Capp, parentKey string) (string, error) {
+	claim, err := getHostnameClaim(ctx, k8sClient, hostname)
+	if err != nil || claim == nil {
+		return "", err
+	}
+
+	if claim.Spec.Owner != hostnameClaimOwner(capp, parentKey) {
+		return claim.Spec.Owner.String(), nil
+	}
+
+	if claim.Status.ClusterOwner != "" {
+		return ClusterOwner(claim.Status.ClusterOwner), nil
+	}
+
+	return "", nil
+}
+
+// claimedHostnames are the hostnames of a Capp by the outcome of claiming them.
+type claimedHostnames struct {
+	// owned are the hostnames whose DNS records are managed for the Capp.
+	owned []RouteHostname
+
+	// unknown are the hostnames claimed by the Capp whose ownership TXT record could not be looked up,
+	// whose DNS records are left as they are until their ownership is verified.
+	unknown []RouteHostname
+
+	// otherClusters are the hostnames claimed by the Capp which are owned by another cluster. Their HostnameClaims
+	// are kept, so that the other owners of the hostname in the cluster also leave its objects to the other cluster.
+	otherClusters []RouteHostname
+}
+
+// records returns the hostnames whose DNS records are kept.
+func (c claimedHostnames) records() map[string]bool {
+	return hostnameSet(c.owned, c.unknown)
+}
+
+// claims returns the hostnames whose HostnameClaims are kept.
+func (c claimedHostnames) claims() map[string]bool {
+	return hostnameSet(c.owned, c.unknown, c.otherClusters)
+}
+
+// claimHostnames claims the given hostnames for a Capp and sorts them by their owner. The first claimant of a
+// hostname owns it, since creating the HostnameClaim of a hostname fails once it exists, unless its ownership
+// TXT record shows that another cluster owns it. An event is emitted for every hostname which is owned by another
+// Capp, CappRouter or cluster.
+func (r DNSRecordManager) claimHostnames(capp cappv1alpha1.Capp, routeHostnames []RouteHostname, backend dnsBackend, txtOwnerID string, resourceManager rclient.ResourceManagerClient) (claimedHostnames, error) {
+	claimed := claimedHostnames{}
+	for _, routeHostname := range routeHostnames {
+		claim, err := r.claimHostname(capp, routeHostname.Hostname, backend, resourceManager)
+		if err != nil {
+			return claimed, err
+		}
+
+		if claim.Spec.Owner != hostnameClaimOwner(capp, r.ParentKey) {
+			r.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventHostnameConflict,
+				fmt.Sprintf("Hostname %s is claimed by %s, its DNSRecord is not managed", routeHostname.Hostname, claim.Spec.Owner.String()))
+			continue
+		}
+
+		clusterOwner, err := r.verifyHostnameOwnership(claim, txtOwnerID)
+		if err != nil {
+			if !stderrors.Is(err, ErrHostnameOwnershipUnknown) {
+				return claimed, err
+			}
+			r.Log.Info(fmt.Sprintf("Failed to verify the ownership of hostname %s, retrying: %s", routeHostname.Hostname, err.Error()))
+			claimed.unknown = append(claimed.unknown, routeHostname)
+			continue
+		}
+
+		if clusterOwner != "" {
+			r.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventHostnameConflict,
+				fmt.Sprintf("Hostname %s is owned by %s, its DNSRecord is not managed", routeHostname.Hostname, ClusterOwner(clusterOwner)))
+			claimed.otherClusters = append(claimed.otherClusters, routeHostname)
+			continue
+		}
+		claimed.owned = append(claimed.owned, routeHostname)
+	}
+
+	return claimed, nil
+}
+
+// claimHostname creates the HostnameClaim of a hostname unless it exists, and returns it. A hostname whose DNS
+// record was created for another existing Capp or CappRouter before it was claimed is claimed on behalf of that owner.
+func (r DNSRecordManager) claimHostname(capp cappv1alpha1.Capp, hostname string, backend dnsBackend, resourceManager rclient.ResourceManagerClient) (*cappv1alpha1.HostnameClaim, error) {
+	claim, err := getHostnameClaim(r.Ctx, r.K8sclient, hostname)
+	if err != nil || claim != nil {
+		return claim, err
+	}
+
+	owner := hostnameClaimOwner(capp, r.ParentKey)
+	recordOwner, err := r.getDNSRecordOwner(hostname, capp.Namespace, backend)
+	if err != nil {
+		return nil, err
+	}
+	if recordOwner != nil {
+		owner = *recordOwner
+	}
+
+	claim = &cappv1alpha1.HostnameClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   hostname,
+			Labels: hostnameClaimLabels(owner),
+		},
+		Spec: cappv1alpha1.HostnameClaimSpec{Owner: owner},
+	}
+
+	if err := resourceManager.CreateResource(claim); err != nil {
+		if errors.IsAlreadyExists(err) {
+			// The hostname was claimed concurrently, so the owner is reconciled again once the HostnameClaim is cached.
+			return nil, errors.NewConflict(cappv1alpha1.GroupVersion.WithResource("hostnameclaims").GroupResource(), hostname, err)
+		}
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// verifyHostnameOwnership looks up the ownership TXT record of a hostname claimed by a Capp and records on its
+// HostnameClaim whether it is owned by another cluster, returning the owner ID of that cluster. The record is looked
+// up once after the hostname is claimed, and again on every reconciliation while another cluster owns it, so that the
+// hostname is taken over once the other cluster releases it. A failed lookup leaves the ownership unknown and returns
+// ErrHostnameOwnershipUnknown. Ownership is only checked when the txtOwnerID is set, and is cleared otherwise.
+func (r DNSRecordManager) verifyHostnameOwnership(claim *cappv1alpha1.HostnameClaim, txtOwnerID string) (string, error) {
+	claimStatus := cappv1alpha1.HostnameClaimStatus{}
+	if txtOwnerID != "" {
+		if claim.Status.OwnershipVerified && claim.Status.ClusterOwner == "" {
+			return "", nil
+		}
+
+		clusterOwner, err := getOwnershipRecordOwner(r.Ctx, claim.Name, txtOwnerID)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrHostnameOwnershipUnknown, err)
+		}
+		claimStatus = cappv1alpha1.HostnameClaimStatus{OwnershipVerified: true, ClusterOwner: clusterOwner}
+	}
+
+	if claim.Status != claimStatus {
+		claim.Status = claimStatus
+		if err := r.K8sclient.Status().Update(r.Ctx, claim); err != nil {
+			return "", fmt.Errorf("failed to update status of HostnameClaim %q: %w", claim.Name, err)
+		}
+	}
+
+	return claimStatus.ClusterOwner, nil
+}
+
+// hostnameClaimLabels returns the labels of a HostnameClaim, which reference its owner like the labels of
+// the DNS records of the owner.
+func hostnameClaimLabels(owner cappv1alpha1.HostnameClaimOwner) map[string]string {
+	parentKey := utils.CappResourceKey
+	if owner.Kind == hostnameClaimKindRouter {
+		parentKey = utils.RouterResourceKey
+	}
+
+	return map[string]string{
+		parentKey:               owner.Name,
+		utils.CappNamespaceKey:  owner.Namespace,
+		utils.ManagedByLabelKey: utils.CappKey,
+	}
+}
+
+// getDNSRecordOwner returns the Capp or CappRouter which is referenced by the labels of the existing DNS record
+// of a hostname if it still exists, and nil otherwise.
+func (r DNSRecordManager) getDNSRecordOwner(hostname, namespace string, backend dnsBackend) (*cappv1alpha1.HostnameClaimOwner, error) {
+	for _, kind := range backend.recordKinds() {
+		record := kind.newRecord(kind.recordKey(hostname, namespace))
+		if err := r.K8sclient.Get(r.Ctx, client.ObjectKeyFromObject(record), record); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s %q: %w", kind.groupVersionKind().Kind, hostname, err)
+		}
+
+		recordLabels := record.GetLabels()
+		owner := cappv1alpha1.HostnameClaimOwner{
+			Kind:      hostnameClaimKindCapp,
+			Namespace: recordLabels[utils.CappNamespaceKey],
+			Name:      recordLabels[utils.CappResourceKey],
+		}
+		var ownerObject client.Object = &cappv1alpha1.Capp{}
+		if routerName := recordLabels[utils.RouterResourceKey]; routerName != "" {
+			owner.Kind = hostnameClaimKindRouter
+			owner.Name = routerName
+			ownerObject = &cappv1alpha1.CappRouter{}
+		}
+		if owner.Namespace == "" || owner.Name == "" {
+			continue
+		}
+
+		if err := r.K8sclient.Get(r.Ctx, types.NamespacedName{Namespace: owner.Namespace, Name: owner.Name}, ownerObject); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s %q: %w", owner.Kind, owner.Name, err)
+		}
+
+		return &owner, nil
+	}
+
+	return nil, nil
+}
+
+// releaseHostnameClaims deletes the HostnameClaims owned by a Capp which are not of one of the given hostnames.
+func (r DNSRecordManager) releaseHostnameClaims(capp cappv1alpha1.Capp, hostnames map[string]bool, resourceManager rclient.ResourceManagerClient) error {
+	claims := cappv1alpha1.HostnameClaimList{}
+	listOptions := utils.GetListOptions(labels.Set{
+		parentLabelKey(r.ParentKey): capp.Name,
+		utils.CappNamespaceKey:      capp.Namespace,
+	})
+	if err := r.K8sclient.List(r.Ctx, &claims, &listOptions); err != nil {
+		return fmt.Errorf("unable to list HostnameClaims of Capp %q: %w", capp.Name, err)
+	}
+
+	owner := hostnameClaimOwner(capp, r.ParentKey)
+	for _, claim := range claims.Items {
+		if hostnames[claim.Name] || claim.Spec.Owner != owner {
+			continue
+		}
+		if err := resourceManager.DeleteResource(&claim); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
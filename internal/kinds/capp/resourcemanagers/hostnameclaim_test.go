@@ -0,0 +1,113 @@
+package resourcemanagers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hasEvent returns a boolean indicating whether a fake recorder recorded an event with the given reason.
+func hasEvent(recorder *record.FakeRecorder, reason string) bool {
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, reason) {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+func TestManageDNSRecordsOfClaimedHostname(t *testing.T) {
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendProviderDNS)
+	ctx := context.Background()
+
+	owner := newTaggedCapp()
+	assert.NoError(t, manager.Manage(owner))
+
+	claim := cappv1alpha1.HostnameClaim{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.Equal(t, cappv1alpha1.HostnameClaimOwner{Kind: "Capp", Namespace: "test-ns", Name: "test-capp"}, claim.Spec.Owner)
+
+	// A Capp of another namespace with the same hostname does not take over the DNS record.
+	other := newTaggedCapp()
+	other.Namespace = "other-ns"
+	other.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP("app.capp-zone.com")
+	assert.NoError(t, manager.Manage(other))
+	assert.True(t, hasEvent(manager.EventRecorder.(*record.FakeRecorder), eventHostnameConflict))
+
+	cnameRecord := dnsrecordv1alpha1.CNAMERecord{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &cnameRecord))
+	assert.Equal(t, "test-ns", cnameRecord.Labels[utils.CappNamespaceKey])
+
+	conflict, err := GetHostnameClaimConflict(ctx, k8sClient, "app.capp-zone.com", other, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Capp test-ns/test-capp", conflict.String())
+
+	conflict, err = GetHostnameClaimConflict(ctx, k8sClient, "app.capp-zone.com", owner, "")
+	assert.NoError(t, err)
+	assert.Nil(t, conflict)
+
+	// Cleaning up the other Capp leaves the DNS record and HostnameClaim of the owner.
+	assert.NoError(t, manager.CleanUp(other))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &cnameRecord))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+
+	// Once the owner releases the hostname, the other Capp may claim it.
+	assert.NoError(t, manager.CleanUp(owner))
+	assert.Error(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+
+	assert.NoError(t, manager.Manage(other))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.Equal(t, "other-ns", claim.Spec.Owner.Namespace)
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &cnameRecord))
+	assert.Equal(t, "other-ns", cnameRecord.Labels[utils.CappNamespaceKey])
+}
+
+func TestClaimHostnameOfExistingDNSRecord(t *testing.T) {
+	owner := newTaggedCapp()
+	owner.Namespace = "other-ns"
+	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{
+		Name:   "app.capp-zone.com",
+		Labels: map[string]string{utils.CappResourceKey: owner.Name, utils.CappNamespaceKey: owner.Namespace},
+	}}
+	manager, k8sClient := newDNSRecordManager(utils.DNSBackendProviderDNS, &owner, cnameRecord)
+	ctx := context.Background()
+
+	// The hostname of a DNS record which was created before it was claimed is claimed on behalf of its owner.
+	capp := newTaggedCapp()
+	assert.NoError(t, manager.Manage(capp))
+	assert.True(t, hasEvent(manager.EventRecorder.(*record.FakeRecorder), "Hostname app.capp-zone.com is claimed by Capp other-ns/test-capp"))
+
+	claim := cappv1alpha1.HostnameClaim{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.Equal(t, cappv1alpha1.HostnameClaimOwner{Kind: "Capp", Namespace: "other-ns", Name: "test-capp"}, claim.Spec.Owner)
+	assert.Equal(t, "other-ns", claim.Labels[utils.CappNamespaceKey])
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, cnameRecord))
+	assert.Equal(t, "other-ns", cnameRecord.Labels[utils.CappNamespaceKey])
+
+	// The claim is released together with the DNS record by its owner.
+	assert.NoError(t, manager.CleanUp(owner))
+	assert.Error(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+
+	// The DNS record of an owner which no longer exists is taken over.
+	cnameRecord = &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{
+		Name:   "app.capp-zone.com",
+		Labels: map[string]string{utils.CappResourceKey: "deleted-capp", utils.CappNamespaceKey: owner.Namespace},
+	}}
+	assert.NoError(t, k8sClient.Create(ctx, cnameRecord))
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "app.capp-zone.com"}, &claim))
+	assert.Equal(t, "test-ns", claim.Spec.Owner.Namespace)
+}
@@ -0,0 +1,154 @@
+package resourcemanagers
+
+import (
+	"context"
+	"sort"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RouteHostname is a hostname of a Capp, together with the traffic tag it is routed to, if any,
+// and the name of the Certificate which covers it.
+type RouteHostname struct {
+	Hostname        string
+	Tag             string
+	CertificateName string
+}
+
+// GetTrafficTags returns the sorted tags of the traffic targets of a Capp, including those set by its rollout.
+func GetTrafficTags(capp cappv1alpha1.Capp) []string {
+	var tags []string
+	if rollout.IsEnabled(capp) {
+		for _, target := range rollout.Traffic(capp) {
+			if target.Tag != "" {
+				tags = append(tags, target.Tag)
+			}
+		}
+	} else {
+		for _, target := range GetTrafficTargets(capp) {
+			if target.Tag != "" {
+				tags = append(tags, target.Tag)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+	return tags
+}
+
+// GetRouteHostnames returns the hostname of a Capp in the zone it matches, followed by its additional hostnames,
+// which are covered by the Certificate of the hostname, and the hostnames of its tagged traffic targets
+// if TagHostnamesEnabled is set.
+func GetRouteHostnames(capp cappv1alpha1.Capp, zones utils.DNSZones) []RouteHostname {
+	hostname := zones.ResourceName(capp.Spec.RouteSpec.Hostname)
+	routeHostnames := []RouteHostname{{Hostname: hostname, CertificateName: hostname}}
+
+	seen := map[string]bool{hostname: true}
+	for _, additionalHostname := range capp.Spec.RouteSpec.AdditionalHostnames {
+		additionalHostname = zones.ResourceName(additionalHostname)
+		if seen[additionalHostname] {
+			continue
+		}
+		seen[additionalHostname] = true
+		routeHostnames = append(routeHostnames, RouteHostname{Hostname: additionalHostname, CertificateName: hostname})
+	}
+
+	if !capp.Spec.RouteSpec.TagHostnamesEnabled {
+		return routeHostnames
+	}
+
+	for _, tag := range GetTrafficTags(capp) {
+		tagHostname := utils.GenerateTagHostname(tag, hostname)
+		routeHostnames = append(routeHostnames, RouteHostname{Hostname: tagHostname, Tag: tag, CertificateName: tagHostname})
+	}
+
+	return routeHostnames
+}
+
+// getRouteHostnames returns the hostnames of a Capp in the zones set in the DNS ConfigMap which its namespace
+// is allowed to use and which are not owned by another Capp, CappRouter or cluster, as the objects of a hostname
+// are only managed by its owner. The Capp stands for a CappRouter when the parentKey is utils.RouterResourceKey.
+func getRouteHostnames(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, parentKey string) ([]RouteHostname, error) {
+	dnsConfig, err := utils.GetDNSConfig(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var routeHostnames []RouteHostname
+	for _, routeHostname := range zoneRouteHostnames(capp, zones) {
+		conflict, err := GetHostnameConflict(ctx, k8sClient, routeHostname.Hostname, capp, parentKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if conflict == "" {
+			routeHostnames = append(routeHostnames, routeHostname)
+		}
+	}
+
+	return routeHostnames, nil
+}
+
+// zoneRouteHostnames returns the hostnames of a Capp in the given zones which its namespace is allowed to use.
+// The other hostnames are skipped rather than failing the whole Capp, so that their resources are removed like
+// those of previous hostnames, and they are reported in the status of the Capp.
+func zoneRouteHostnames(capp cappv1alpha1.Capp, zones utils.DNSZones) []RouteHostname {
+	var routeHostnames []RouteHostname
+	for _, routeHostname := range GetRouteHostnames(capp, zones) {
+		if zones.Match(routeHostname.Hostname).IsNamespaceAllowed(capp.Namespace) {
+			routeHostnames = append(routeHostnames, routeHostname)
+		}
+	}
+
+	return routeHostnames
+}
+
+// hasPreviousHostnames returns a boolean indicating whether the status of a Capp holds hostnames, in which case
+// the resources of hostnames which are no longer used may have to be removed.
+func hasPreviousHostnames(capp cappv1alpha1.Capp) bool {
+	return capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil || len(capp.Status.RouteStatus.Hostnames) > 0
+}
+
+// hostnameSet returns the set of the hostnames of the given RouteHostnames.
+func hostnameSet(routeHostnameLists ...[]RouteHostname) map[string]bool {
+	hostnames := map[string]bool{}
+	for _, routeHostnames := range routeHostnameLists {
+		for _, routeHostname := range routeHostnames {
+			hostnames[routeHostname.Hostname] = true
+		}
+	}
+
+	return hostnames
+}
+
+// certificateDNSNames returns the names of the Certificates which cover the given RouteHostnames, in order,
+// and the hostnames covered by each of them.
+func certificateDNSNames(routeHostnames []RouteHostname) ([]string, map[string][]string) {
+	var certificateNames []string
+	dnsNames := map[string][]string{}
+	for _, routeHostname := range routeHostnames {
+		if _, ok := dnsNames[routeHostname.CertificateName]; !ok {
+			certificateNames = append(certificateNames, routeHostname.CertificateName)
+		}
+		dnsNames[routeHostname.CertificateName] = append(dnsNames[routeHostname.CertificateName], routeHostname.Hostname)
+	}
+
+	return certificateNames, dnsNames
+}
+
+// parentLabelKey returns the label which references the owner of resources, defaulting to the Capp label.
+func parentLabelKey(parentKey string) string {
+	if parentKey == "" {
+		return utils.CappResourceKey
+	}
+
+	return parentKey
+}
@@ -0,0 +1,129 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testZone = "capp-zone.com."
+
+func newTaggedCapp(tags ...string) cappv1alpha1.Capp {
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			RouteSpec: cappv1alpha1.RouteSpec{Hostname: "app", TagHostnamesEnabled: true},
+		},
+	}
+	for _, tag := range tags {
+		capp.Spec.RouteSpec.TrafficTargets = append(capp.Spec.RouteSpec.TrafficTargets,
+			cappv1alpha1.CappTrafficTarget{TrafficTarget: knativev1.TrafficTarget{RevisionName: "test-capp-00001", Tag: tag}})
+	}
+
+	return capp
+}
+
+func TestGetRouteHostnames(t *testing.T) {
+	capp := newTaggedCapp("preview", "", "canary")
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"legacy", "app.capp-zone.com", "legacy.capp-zone.com"}
+	assert.Equal(t, []RouteHostname{
+		{Hostname: "app.capp-zone.com", CertificateName: "app.capp-zone.com"},
+		{Hostname: "legacy.capp-zone.com", CertificateName: "app.capp-zone.com"},
+		{Hostname: "canary-app.capp-zone.com", Tag: "canary", CertificateName: "canary-app.capp-zone.com"},
+		{Hostname: "preview-app.capp-zone.com", Tag: "preview", CertificateName: "preview-app.capp-zone.com"},
+	}, GetRouteHostnames(capp, utils.DNSZones{{Name: testZone}}))
+
+	capp.Spec.RouteSpec.TagHostnamesEnabled = false
+	capp.Spec.RouteSpec.AdditionalHostnames = nil
+	assert.Equal(t, []RouteHostname{{Hostname: "app.capp-zone.com", CertificateName: "app.capp-zone.com"}}, GetRouteHostnames(capp, utils.DNSZones{{Name: testZone}}))
+}
+
+func TestManageTaggedDomainMappings(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = knativev1beta1.AddToScheme(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+	dnsRecord := &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{Name: "app.capp-zone.com"}}
+	dnsRecord.Status.SetConditions(xpcommonv1.Available())
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig, dnsRecord).Build()
+	manager := KnativeDomainMappingManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+	capp := newTaggedCapp("preview")
+	assert.NoError(t, manager.Manage(capp))
+
+	domainMapping := knativev1beta1.DomainMapping{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &domainMapping))
+	assert.Equal(t, "test-capp", domainMapping.Spec.Ref.Name)
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "preview-app.capp-zone.com"}, &domainMapping))
+	assert.Equal(t, "preview-test-capp", domainMapping.Spec.Ref.Name)
+	assert.Equal(t, "v1", domainMapping.Spec.Ref.APIVersion)
+
+	capp = newTaggedCapp()
+	capp.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP("app.capp-zone.com")
+	assert.NoError(t, manager.Manage(capp))
+
+	domainMappings := knativev1beta1.DomainMappingList{}
+	assert.NoError(t, k8sClient.List(context.Background(), &domainMappings))
+	assert.Len(t, domainMappings.Items, 1)
+	assert.Equal(t, "app.capp-zone.com", domainMappings.Items[0].Name)
+
+	assert.NoError(t, manager.CleanUp(capp))
+	assert.NoError(t, k8sClient.List(context.Background(), &domainMappings))
+	assert.Empty(t, domainMappings.Items)
+}
+
+func TestManageCertificateOfAdditionalHostnames(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = cmapi.AddToScheme(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+	dnsRecord := &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{Name: "app.capp-zone.com"}}
+	dnsRecord.Status.SetConditions(xpcommonv1.Available())
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig, dnsRecord).Build()
+	manager := CertificateManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+	capp := newTaggedCapp()
+	capp.Spec.RouteSpec.TlsEnabled = true
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"legacy", "vanity"}
+	assert.NoError(t, manager.Manage(capp))
+
+	certificate := cmapi.Certificate{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &certificate))
+	assert.Equal(t, []string{"app.capp-zone.com", "legacy.capp-zone.com", "vanity.capp-zone.com"}, certificate.Spec.DNSNames)
+
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"vanity"}
+	capp.Status.RouteStatus.DomainMappingObjectStatus.URL = apis.HTTP("app.capp-zone.com")
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &certificate))
+	assert.Equal(t, []string{"app.capp-zone.com", "vanity.capp-zone.com"}, certificate.Spec.DNSNames)
+
+	certificates := cmapi.CertificateList{}
+	assert.NoError(t, k8sClient.List(context.Background(), &certificates))
+	assert.Len(t, certificates.Items, 1)
+}
@@ -0,0 +1,289 @@
+package resourcemanagers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	HTTPRoute                        = "httpRoute"
+	eventCappHTTPRouteCreationFailed = "HTTPRouteCreationFailed"
+	eventCappHTTPRouteCreated        = "HTTPRouteCreated"
+	gatewayBackendPort               = 80
+	gatewayKind                      = "Gateway"
+)
+
+// HTTPRouteManager routes the hostnames of a Capp through a Gateway using Gateway API HTTPRoutes.
+// Every HTTPRoute sends the requests of a hostname to the Knative ingress, rewriting the host to the
+// internal hostname of the Knative Service or of its tag. The Gateway itself is not modified: TLS is
+// terminated by its HTTPS listeners, such as a wildcard listener for every zone, which the HTTPRoutes
+// attach to by their hostnames.
+type HTTPRouteManager struct {
+	Ctx           context.Context
+	K8sclient     client.Client
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+	Routing       RoutingConfig
+}
+
+// prepareResource prepares an HTTPRoute resource for a hostname of the provided Capp. While the Capp is
+// under maintenance, the HTTPRoute sends requests directly to its maintenance Service instead.
+func (h HTTPRouteManager) prepareResource(capp cappv1alpha1.Capp, routeHostname RouteHostname) gatewayv1.HTTPRoute {
+	serviceName := capp.Name
+	if routeHostname.Tag != "" {
+		serviceName = utils.GenerateTagHostname(routeHostname.Tag, capp.Name)
+	}
+
+	rule := h.prepareRule(serviceName, capp.Namespace)
+	if isUnderMaintenance(capp, h.Routing) {
+		rule = h.prepareMaintenanceRule(capp)
+	}
+	rule.Timeouts = prepareTimeouts(capp.Spec.RouteSpec.RouteTimeoutSeconds)
+
+	return h.prepareHTTPRoute(routeHostname.Hostname, capp.Namespace, map[string]string{
+		utils.CappResourceKey:   capp.Name,
+		utils.ManagedByLabelKey: utils.CappKey,
+	}, []gatewayv1.HTTPRouteRule{rule})
+}
+
+// prepareHTTPRoute prepares an HTTPRoute resource of a hostname with the given rules, attached to the Gateway.
+func (h HTTPRouteManager) prepareHTTPRoute(hostname, namespace string, routeLabels map[string]string, rules []gatewayv1.HTTPRouteRule) gatewayv1.HTTPRoute {
+	parentGroup := gatewayv1.Group(gatewayv1.GroupName)
+	parentKind := gatewayv1.Kind(gatewayKind)
+	gatewayNamespace := gatewayv1.Namespace(h.Routing.Gateway.Namespace)
+
+	return gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hostname,
+			Namespace: namespace,
+			Labels:    routeLabels,
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{
+					Group:     &parentGroup,
+					Kind:      &parentKind,
+					Namespace: &gatewayNamespace,
+					Name:      gatewayv1.ObjectName(h.Routing.Gateway.Name),
+				}},
+			},
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(hostname)},
+			Rules:     rules,
+		},
+	}
+}
+
+// prepareRule prepares an HTTPRoute rule which sends requests to the Knative ingress, rewriting the host
+// to the internal hostname of the given Knative Service.
+func (h HTTPRouteManager) prepareRule(serviceName, namespace string) gatewayv1.HTTPRouteRule {
+	backendNamespace := gatewayv1.Namespace(h.Routing.GatewayBackend.Namespace)
+	backendPort := gatewayv1.PortNumber(gatewayBackendPort)
+	rewriteHostname := gatewayv1.PreciseHostname(h.Routing.ServiceHostname(serviceName, namespace))
+
+	return gatewayv1.HTTPRouteRule{
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Hostname: &rewriteHostname},
+		}},
+		BackendRefs: []gatewayv1.HTTPBackendRef{{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name:      gatewayv1.ObjectName(h.Routing.GatewayBackend.Name),
+					Namespace: &backendNamespace,
+					Port:      &backendPort,
+				},
+			},
+		}},
+	}
+}
+
+// prepareTimeouts prepares the timeouts of an HTTPRoute rule from the route timeout of a Capp, if it is set.
+func prepareTimeouts(timeoutSeconds *int64) *gatewayv1.HTTPRouteTimeouts {
+	if timeoutSeconds == nil {
+		return nil
+	}
+
+	timeout := gatewayv1.Duration((time.Duration(*timeoutSeconds) * time.Second).String())
+	return &gatewayv1.HTTPRouteTimeouts{Request: &timeout}
+}
+
+// prepareMaintenanceRule prepares an HTTPRoute rule which sends requests to the maintenance Service of a Capp.
+func (h HTTPRouteManager) prepareMaintenanceRule(capp cappv1alpha1.Capp) gatewayv1.HTTPRouteRule {
+	backendPort := gatewayv1.PortNumber(h.Routing.maintenanceServicePort())
+
+	return gatewayv1.HTTPRouteRule{
+		BackendRefs: []gatewayv1.HTTPBackendRef{{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(MaintenanceServiceName(capp.Name)),
+					Port: &backendPort,
+				},
+			},
+		}},
+	}
+}
+
+// CleanUp attempts to delete the associated HTTPRoutes for a given Capp resource.
+// There is nothing to clean up if the Gateway API is not installed.
+func (h HTTPRouteManager) CleanUp(capp cappv1alpha1.Capp) error {
+	resourceManager := rclient.ResourceManagerClient{Ctx: h.Ctx, K8sclient: h.K8sclient, Log: h.Log}
+
+	httpRoutes, err := h.getPreviousHTTPRoutes(capp)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return h.deletePreviousHTTPRoutes(httpRoutes, resourceManager, nil)
+}
+
+// IsRequired is responsible to determine if resource HTTPRoute is required.
+func (h HTTPRouteManager) IsRequired(capp cappv1alpha1.Capp) bool {
+	return isHostnameRouted(capp) && isRoutingBackend(h.Routing.Backend, RoutingBackendGatewayAPI)
+}
+
+// Manage creates or updates an HTTPRoute resource based on the provided Capp if it's required.
+// If it's not, then it cleans up the resource if it exists.
+func (h HTTPRouteManager) Manage(capp cappv1alpha1.Capp) error {
+	if h.IsRequired(capp) {
+		return h.createOrUpdate(capp)
+	}
+
+	return h.CleanUp(capp)
+}
+
+// createOrUpdate creates or updates the HTTPRoute resources of every hostname which a Capp owns, and deletes
+// those of its previous hostnames and of the hostnames owned by others.
+func (h HTTPRouteManager) createOrUpdate(capp cappv1alpha1.Capp) error {
+	routeHostnames, err := getRouteHostnames(h.Ctx, h.K8sclient, capp, "")
+	if err != nil {
+		return fmt.Errorf("failed to get hostnames: %w", err)
+	}
+
+	resourceManager := rclient.ResourceManagerClient{Ctx: h.Ctx, K8sclient: h.K8sclient, Log: h.Log}
+	for _, routeHostname := range routeHostnames {
+		if err := h.createOrUpdateHTTPRoute(capp, routeHostname, resourceManager); err != nil {
+			return err
+		}
+	}
+
+	if err := h.handlePreviousHTTPRoutes(capp, resourceManager, routeHostnames); err != nil {
+		return fmt.Errorf("failed to delete previous HTTPRoutes: %w", err)
+	}
+
+	return nil
+}
+
+// createOrUpdateHTTPRoute creates or updates the HTTPRoute resource of a hostname of a Capp.
+func (h HTTPRouteManager) createOrUpdateHTTPRoute(capp cappv1alpha1.Capp, routeHostname RouteHostname, resourceManager rclient.ResourceManagerClient) error {
+	return h.applyHTTPRoute(&capp, h.prepareResource(capp, routeHostname), resourceManager)
+}
+
+// applyHTTPRoute creates the given HTTPRoute if it does not exist yet, emitting an event for its owner,
+// and otherwise updates its spec.
+func (h HTTPRouteManager) applyHTTPRoute(owner runtime.Object, httpRouteFromCapp gatewayv1.HTTPRoute, resourceManager rclient.ResourceManagerClient) error {
+	httpRoute := gatewayv1.HTTPRoute{}
+	if err := h.K8sclient.Get(h.Ctx, types.NamespacedName{Namespace: httpRouteFromCapp.Namespace, Name: httpRouteFromCapp.Name}, &httpRoute); err != nil {
+		if errors.IsNotFound(err) {
+			return h.createHTTPRoute(owner, httpRouteFromCapp, resourceManager)
+		}
+		return fmt.Errorf("failed to get HTTPRoute %q: %w", httpRouteFromCapp.Name, err)
+	}
+
+	if !reflect.DeepEqual(httpRoute.Spec, httpRouteFromCapp.Spec) {
+		httpRoute.Spec = httpRouteFromCapp.Spec
+		return resourceManager.UpdateResource(&httpRoute)
+	}
+
+	return nil
+}
+
+// createHTTPRoute creates a new HTTPRoute and emits an event.
+func (h HTTPRouteManager) createHTTPRoute(owner runtime.Object, httpRouteFromCapp gatewayv1.HTTPRoute, resourceManager rclient.ResourceManagerClient) error {
+	if err := resourceManager.CreateResource(&httpRouteFromCapp); err != nil {
+		h.EventRecorder.Event(owner, corev1.EventTypeWarning, eventCappHTTPRouteCreationFailed,
+			fmt.Sprintf("Failed to create HTTPRoute %s", httpRouteFromCapp.Name))
+
+		return err
+	}
+
+	h.EventRecorder.Event(owner, corev1.EventTypeNormal, eventCappHTTPRouteCreated,
+		fmt.Sprintf("Created HTTPRoute %s", httpRouteFromCapp.Name))
+
+	return nil
+}
+
+// handlePreviousHTTPRoutes takes care of removing unneeded HTTPRoute objects. If the DNSRecord of the
+// hostname of the Capp is not yet available then return early and do not delete the previous HTTPRoutes.
+// When the Capp has no hostnames left, all of its HTTPRoutes are deleted.
+func (h HTTPRouteManager) handlePreviousHTTPRoutes(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, routeHostnames []RouteHostname) error {
+	if len(routeHostnames) > 0 {
+		available, err := isDNSRecordAvailable(h.Ctx, h.K8sclient, routeHostnames[0].Hostname, capp.Namespace)
+		if err != nil {
+			return err
+		}
+
+		if !available {
+			return nil
+		}
+	}
+
+	httpRoutes, err := h.getPreviousHTTPRoutes(capp)
+	if err != nil {
+		return err
+	}
+
+	return h.deletePreviousHTTPRoutes(httpRoutes, resourceManager, hostnameSet(routeHostnames))
+}
+
+// getPreviousHTTPRoutes returns a list of all HTTPRoute objects that are related to the given Capp.
+func (h HTTPRouteManager) getPreviousHTTPRoutes(capp cappv1alpha1.Capp) (gatewayv1.HTTPRouteList, error) {
+	httpRoutes := gatewayv1.HTTPRouteList{}
+
+	set := labels.Set{
+		utils.CappResourceKey: capp.Name,
+	}
+
+	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
+	if err := h.K8sclient.List(h.Ctx, &httpRoutes, &listOptions); err != nil {
+		return httpRoutes, fmt.Errorf("unable to list HTTPRoutes of Capp %q: %w", capp.Name, err)
+	}
+
+	return httpRoutes, nil
+}
+
+// deletePreviousHTTPRoutes deletes all HTTPRoutes associated with a Capp which are not of one of the given hostnames.
+func (h HTTPRouteManager) deletePreviousHTTPRoutes(httpRoutes gatewayv1.HTTPRouteList, resourceManager rclient.ResourceManagerClient, hostnames map[string]bool) error {
+	for _, httpRoute := range httpRoutes.Items {
+		if hostnames[httpRoute.Name] {
+			continue
+		}
+
+		removed := rclient.GetBareHTTPRoute(httpRoute.Name, httpRoute.Namespace)
+		if err := resourceManager.DeleteResource(&removed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
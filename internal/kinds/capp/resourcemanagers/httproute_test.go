@@ -0,0 +1,113 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newHTTPRouteManager(objects ...client.Object) (HTTPRouteManager, client.Client) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = gatewayv1.Install(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "capp-gateway", Namespace: "gateway-ns"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(append(objects, dnsConfig, gateway)...).Build()
+
+	return HTTPRouteManager{
+		Ctx:           context.Background(),
+		K8sclient:     k8sClient,
+		Log:           logr.Discard(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Routing: RoutingConfig{
+			Backend:        RoutingBackendGatewayAPI,
+			Gateway:        types.NamespacedName{Namespace: "gateway-ns", Name: "capp-gateway"},
+			GatewayBackend: types.NamespacedName{Namespace: "kourier-system", Name: "kourier-internal"},
+		},
+	}, k8sClient
+}
+
+func TestManageHTTPRoutes(t *testing.T) {
+	manager, k8sClient := newHTTPRouteManager()
+	ctx := context.Background()
+
+	capp := newTaggedCapp("preview")
+	capp.Spec.RouteSpec.TlsEnabled = true
+	assert.True(t, manager.IsRequired(capp))
+	assert.NoError(t, manager.Manage(capp))
+
+	httpRoute := gatewayv1.HTTPRoute{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "preview-app.capp-zone.com"}, &httpRoute))
+	assert.Equal(t, []gatewayv1.Hostname{"preview-app.capp-zone.com"}, httpRoute.Spec.Hostnames)
+	assert.Equal(t, gatewayv1.ObjectName("capp-gateway"), httpRoute.Spec.ParentRefs[0].Name)
+	rule := httpRoute.Spec.Rules[0]
+	assert.Equal(t, gatewayv1.PreciseHostname("preview-test-capp.test-ns.svc.cluster.local"), *rule.Filters[0].URLRewrite.Hostname)
+	assert.Equal(t, gatewayv1.ObjectName("kourier-internal"), rule.BackendRefs[0].Name)
+
+	// The shared Gateway is left untouched, its HTTPS listeners terminate TLS for the HTTPRoutes.
+	gateway := gatewayv1.Gateway{}
+	assert.NoError(t, k8sClient.Get(ctx, manager.Routing.Gateway, &gateway))
+	assert.Len(t, gateway.Spec.Listeners, 1)
+
+	manager.Routing.ClusterDomain = "cluster.example"
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "preview-app.capp-zone.com"}, &httpRoute))
+	assert.Equal(t, gatewayv1.PreciseHostname("preview-test-capp.test-ns.svc.cluster.example"), *httpRoute.Spec.Rules[0].Filters[0].URLRewrite.Hostname)
+
+	assert.NoError(t, manager.CleanUp(capp))
+	httpRoutes := gatewayv1.HTTPRouteList{}
+	assert.NoError(t, k8sClient.List(ctx, &httpRoutes))
+	assert.Empty(t, httpRoutes.Items)
+}
+
+func TestManageHTTPRoutesOfClaimedHostname(t *testing.T) {
+	claim := &cappv1alpha1.HostnameClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "preview-app.capp-zone.com"},
+		Spec:       cappv1alpha1.HostnameClaimSpec{Owner: cappv1alpha1.HostnameClaimOwner{Kind: "Capp", Namespace: "other-ns", Name: "preview"}},
+	}
+	manager, k8sClient := newHTTPRouteManager(claim)
+	ctx := context.Background()
+
+	// The HTTPRoute of a hostname which is claimed by another owner is not created.
+	capp := newTaggedCapp("preview")
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &gatewayv1.HTTPRoute{}))
+	assert.Error(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "preview-app.capp-zone.com"}, &gatewayv1.HTTPRoute{}))
+}
+
+func TestHTTPRouteIsRequired(t *testing.T) {
+	manager, _ := newHTTPRouteManager()
+	capp := newTaggedCapp()
+	assert.True(t, manager.IsRequired(capp))
+
+	capp.Spec.RouteSpec.Visibility = cappv1alpha1.RouteVisibilityClusterLocal
+	assert.False(t, manager.IsRequired(capp))
+
+	manager.Routing.Backend = RoutingBackendDomainMapping
+	capp.Spec.RouteSpec.Visibility = cappv1alpha1.RouteVisibilityExternal
+	assert.False(t, manager.IsRequired(capp))
+	assert.True(t, KnativeDomainMappingManager{Routing: manager.Routing}.IsRequired(capp))
+}
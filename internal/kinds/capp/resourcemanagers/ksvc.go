@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"knative.dev/serving/pkg/apis/serving"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -24,7 +25,6 @@ import (
 const (
 	cappDisabledState                     = "disabled"
 	cappEnabledState                      = "enabled"
-	defaultAutoScaleCM                    = "autoscale-defaults"
 	KnativeServing                        = "knativeServing"
 	eventCappKnativeServiceCreationFailed = "KnativeServiceCreationFailed"
 	eventCappKnativeServiceCreated        = "KnativeServiceCreated"
@@ -40,7 +40,7 @@ type KnativeServiceManager struct {
 }
 
 // prepareResource generates a Knative Service definition from a given Capp resource.
-func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx context.Context) knativev1.Service {
+func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx context.Context) (knativev1.Service, error) {
 	knativeServiceAnnotations := utils.FilterKeysWithoutPrefix(capp.Annotations, utils.CappAPIGroup)
 	knativeServiceLabels := map[string]string{}
 
@@ -65,6 +65,16 @@ func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx conte
 		},
 	}
 
+	if utils.IsClusterLocal(capp.Spec.RouteSpec) {
+		knativeService.Labels[utils.VisibilityLabelKey] = serving.VisibilityClusterLocal
+	}
+
+	traffic, err := k.resolveTraffic(capp)
+	if err != nil {
+		return knativeService, err
+	}
+	knativeService.Spec.RouteSpec.Traffic = traffic
+
 	// set defaults
 	knativeService.Spec.Template.Spec.EnableServiceLinks = new(bool)
 	knativeService.Spec.ConfigurationSpec.SetDefaults(ctx)
@@ -75,15 +85,15 @@ func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx conte
 	volumes := k.prepareVolumes(capp)
 	knativeService.Spec.Template.Spec.Volumes = append(knativeService.Spec.Template.Spec.Volumes, volumes...)
 
-	defaultCM := corev1.ConfigMap{}
-	if err := k.K8sclient.Get(k.Ctx, types.NamespacedName{Namespace: utils.CappNS, Name: defaultAutoScaleCM}, &defaultCM); err != nil {
-		k.Log.Error(err, fmt.Sprintf("could not fetch configMap from namespace %q", utils.CappNS))
+	autoScaleDefaults, err := autoscale.GetAutoScaleDefaults(k.Ctx, k.K8sclient)
+	if err != nil {
+		k.Log.Error(err, "could not fetch autoscale defaults")
 	}
 
-	knativeService.Spec.Template.ObjectMeta.Annotations = utils.MergeMaps(knativeServiceAnnotations, autoscale.SetAutoScaler(capp, defaultCM.Data))
+	knativeService.Spec.Template.ObjectMeta.Annotations = utils.MergeMaps(knativeServiceAnnotations, autoscale.SetAutoScaler(capp, autoScaleDefaults))
 	knativeService.Spec.Template.ObjectMeta.Labels = knativeServiceLabels
 
-	return knativeService
+	return knativeService, nil
 }
 
 // prepareVolumes generates a list of volumes to be used in a Knative Service definition from a given Capp resource.
@@ -147,7 +157,11 @@ func (k KnativeServiceManager) Manage(capp cappv1alpha1.Capp) error {
 
 // createOrUpdate creates or updates a KSVC resource.
 func (k KnativeServiceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	knativeServiceFromCapp := k.prepareResource(capp, k.Ctx)
+	knativeServiceFromCapp, err := k.prepareResource(capp, k.Ctx)
+	if err != nil {
+		k.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappTrafficResolutionFailed, err.Error())
+		return fmt.Errorf("failed to prepare KnativeService %q: %w", capp.Name, err)
+	}
 	knativeService := knativev1.Service{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
 
@@ -186,10 +200,25 @@ func (k KnativeServiceManager) createKSVC(capp *cappv1alpha1.Capp, knativeServic
 
 // updateKSVC checks if an update to the KnativeService is necessary and performs the update to match desired state.
 func (k KnativeServiceManager) updateKSVC(knativeService, knativeServiceFromCapp *knativev1.Service, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(knativeService.Spec, knativeServiceFromCapp.Spec) {
+	visibility := knativeServiceFromCapp.Labels[utils.VisibilityLabelKey]
+	if !reflect.DeepEqual(knativeService.Spec, knativeServiceFromCapp.Spec) || knativeService.Labels[utils.VisibilityLabelKey] != visibility {
 		knativeService.Spec = knativeServiceFromCapp.Spec
+		setVisibilityLabel(knativeService, visibility)
 		return resourceManager.UpdateResource(knativeService)
 	}
 
 	return nil
 }
+
+// setVisibilityLabel sets the visibility label of a KnativeService, or removes it if the visibility is empty.
+func setVisibilityLabel(knativeService *knativev1.Service, visibility string) {
+	if visibility == "" {
+		delete(knativeService.Labels, utils.VisibilityLabelKey)
+		return
+	}
+
+	if knativeService.Labels == nil {
+		knativeService.Labels = map[string]string{}
+	}
+	knativeService.Labels[utils.VisibilityLabelKey] = visibility
+}
@@ -0,0 +1,40 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/serving/pkg/apis/serving"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpdateKSVCVisibility(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = knativev1.AddToScheme(s)
+	knativeService := &knativev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(knativeService).Build()
+	manager := KnativeServiceManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(1)}
+	resourceManager := rclient.ResourceManagerClient{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard()}
+	key := types.NamespacedName{Namespace: "test-ns", Name: "test-capp"}
+
+	knativeServiceFromCapp := knativeService.DeepCopy()
+	knativeServiceFromCapp.Labels = map[string]string{utils.VisibilityLabelKey: serving.VisibilityClusterLocal}
+	assert.NoError(t, k8sClient.Get(context.Background(), key, knativeService))
+	assert.NoError(t, manager.updateKSVC(knativeService, knativeServiceFromCapp, resourceManager))
+	assert.NoError(t, k8sClient.Get(context.Background(), key, knativeService))
+	assert.Equal(t, serving.VisibilityClusterLocal, knativeService.Labels[utils.VisibilityLabelKey])
+
+	knativeServiceFromCapp.Labels = nil
+	assert.NoError(t, manager.updateKSVC(knativeService, knativeServiceFromCapp, resourceManager))
+	assert.NoError(t, k8sClient.Get(context.Background(), key, knativeService))
+	assert.NotContains(t, knativeService.Labels, utils.VisibilityLabelKey)
+}
@@ -0,0 +1,153 @@
+package resourcemanagers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	MaintenanceService                        = "maintenanceService"
+	eventCappMaintenanceServiceCreationFailed = "MaintenanceServiceCreationFailed"
+	eventCappMaintenanceServiceCreated        = "MaintenanceServiceCreated"
+	maintenanceServiceSuffix                  = "-maintenance"
+	maintenanceServicePortName                = "http"
+)
+
+// MaintenanceServiceManager routes the hostnames of a disabled Capp to the maintenance page. It creates a Service
+// of type ExternalName in the namespace of the Capp, which points at the Service of the maintenance page, so that
+// the DomainMappings and HTTPRoutes of the Capp can refer to it while its Knative Service does not exist.
+type MaintenanceServiceManager struct {
+	Ctx           context.Context
+	K8sclient     client.Client
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+	Routing       RoutingConfig
+}
+
+// MaintenanceServiceName returns the name of the Service which routes the hostnames of a Capp to the maintenance page.
+func MaintenanceServiceName(cappName string) string {
+	return cappName + maintenanceServiceSuffix
+}
+
+// isUnderMaintenance returns a boolean indicating whether the hostnames of a Capp are routed to the maintenance page,
+// which is the case when the Capp is disabled and a maintenance page is configured.
+func isUnderMaintenance(capp cappv1alpha1.Capp, routing RoutingConfig) bool {
+	return capp.Spec.State == cappDisabledState && routing.MaintenanceService.Name != "" && isHostnameRouted(capp)
+}
+
+// prepareResource prepares the ExternalName Service which points at the Service of the maintenance page.
+func (m MaintenanceServiceManager) prepareResource(capp cappv1alpha1.Capp) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MaintenanceServiceName(capp.Name),
+			Namespace: capp.Namespace,
+			Labels: map[string]string{
+				utils.CappResourceKey:   capp.Name,
+				utils.ManagedByLabelKey: utils.CappKey,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: m.Routing.ServiceHostname(m.Routing.MaintenanceService.Name, m.Routing.MaintenanceService.Namespace),
+			Ports: []corev1.ServicePort{{
+				Name:       maintenanceServicePortName,
+				Port:       m.Routing.maintenanceServicePort(),
+				TargetPort: intstr.FromInt32(m.Routing.maintenanceServicePort()),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// CleanUp attempts to delete the maintenance Service of a Capp. A Service of the same name which
+// is not labelled with the Capp is left alone.
+func (m MaintenanceServiceManager) CleanUp(capp cappv1alpha1.Capp) error {
+	resourceManager := rclient.ResourceManagerClient{Ctx: m.Ctx, K8sclient: m.K8sclient, Log: m.Log}
+
+	service := corev1.Service{}
+	if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: MaintenanceServiceName(capp.Name)}, &service); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if service.Labels[utils.CappResourceKey] != capp.Name {
+		return nil
+	}
+
+	bareService := rclient.GetBareService(service.Name, service.Namespace)
+	if err := resourceManager.DeleteResource(&bareService); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// IsRequired is responsible to determine if the maintenance Service is required.
+func (m MaintenanceServiceManager) IsRequired(capp cappv1alpha1.Capp) bool {
+	return isUnderMaintenance(capp, m.Routing)
+}
+
+// Manage creates or updates the maintenance Service of a Capp if it's required.
+// If it's not, then it cleans up the resource if it exists.
+func (m MaintenanceServiceManager) Manage(capp cappv1alpha1.Capp) error {
+	if m.IsRequired(capp) {
+		return m.createOrUpdate(capp)
+	}
+
+	return m.CleanUp(capp)
+}
+
+// createOrUpdate creates or updates the maintenance Service of a Capp.
+func (m MaintenanceServiceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
+	serviceFromCapp := m.prepareResource(capp)
+	resourceManager := rclient.ResourceManagerClient{Ctx: m.Ctx, K8sclient: m.K8sclient, Log: m.Log}
+
+	service := corev1.Service{}
+	if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: serviceFromCapp.Name}, &service); err != nil {
+		if errors.IsNotFound(err) {
+			return m.createService(capp, serviceFromCapp, resourceManager)
+		}
+		return fmt.Errorf("failed to get Service %q: %w", serviceFromCapp.Name, err)
+	}
+
+	if service.Labels[utils.CappResourceKey] != capp.Name {
+		return fmt.Errorf("service %q already exists and is not managed by Capp %q", service.Name, capp.Name)
+	}
+
+	if service.Spec.Type != serviceFromCapp.Spec.Type || service.Spec.ExternalName != serviceFromCapp.Spec.ExternalName ||
+		!reflect.DeepEqual(service.Spec.Ports, serviceFromCapp.Spec.Ports) {
+		service.Spec.Type = serviceFromCapp.Spec.Type
+		service.Spec.ExternalName = serviceFromCapp.Spec.ExternalName
+		service.Spec.Ports = serviceFromCapp.Spec.Ports
+		return resourceManager.UpdateResource(&service)
+	}
+
+	return nil
+}
+
+// createService creates a new maintenance Service and emits an event.
+func (m MaintenanceServiceManager) createService(capp cappv1alpha1.Capp, serviceFromCapp corev1.Service, resourceManager rclient.ResourceManagerClient) error {
+	if err := resourceManager.CreateResource(&serviceFromCapp); err != nil {
+		m.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappMaintenanceServiceCreationFailed,
+			fmt.Sprintf("Failed to create maintenance Service %s", serviceFromCapp.Name))
+
+		return err
+	}
+
+	m.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventCappMaintenanceServiceCreated,
+		fmt.Sprintf("Created maintenance Service %s", serviceFromCapp.Name))
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var testMaintenanceService = types.NamespacedName{Namespace: "capp-operator-system", Name: "maintenance"}
+
+func TestManageMaintenanceService(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	k8sClient := fake.NewClientBuilder().WithScheme(s).Build()
+	manager := MaintenanceServiceManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(),
+		EventRecorder: record.NewFakeRecorder(10), Routing: RoutingConfig{MaintenanceService: testMaintenanceService}}
+	key := client.ObjectKey{Namespace: "test-ns", Name: "test-capp-maintenance"}
+
+	capp := newTaggedCapp()
+	capp.Spec.State = cappEnabledState
+	assert.False(t, manager.IsRequired(capp))
+
+	capp.Spec.State = cappDisabledState
+	assert.True(t, manager.IsRequired(capp))
+	assert.NoError(t, manager.Manage(capp))
+
+	service := corev1.Service{}
+	assert.NoError(t, k8sClient.Get(context.Background(), key, &service))
+	assert.Equal(t, corev1.ServiceTypeExternalName, service.Spec.Type)
+	assert.Equal(t, "maintenance.capp-operator-system.svc.cluster.local", service.Spec.ExternalName)
+	assert.Equal(t, "test-capp", service.Labels[utils.CappResourceKey])
+	assert.Equal(t, int32(DefaultMaintenanceServicePort), service.Spec.Ports[0].Port)
+
+	// The port of the maintenance Service follows the configured port of the Service of the maintenance page.
+	manager.Routing.MaintenanceServicePort = 8080
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(context.Background(), key, &service))
+	assert.Equal(t, int32(8080), service.Spec.Ports[0].Port)
+
+	capp.Spec.State = cappEnabledState
+	assert.NoError(t, manager.Manage(capp))
+	assert.Error(t, k8sClient.Get(context.Background(), key, &service))
+
+	manager.Routing.MaintenanceService = types.NamespacedName{}
+	capp.Spec.State = cappDisabledState
+	assert.False(t, manager.IsRequired(capp))
+}
+
+func TestMaintenanceServiceOfUnmanagedService(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-capp-maintenance", Namespace: "test-ns"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(service).Build()
+	manager := MaintenanceServiceManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(),
+		EventRecorder: record.NewFakeRecorder(10), Routing: RoutingConfig{MaintenanceService: testMaintenanceService}}
+
+	capp := newTaggedCapp()
+	capp.Spec.State = cappDisabledState
+	assert.Error(t, manager.Manage(capp))
+
+	assert.NoError(t, manager.CleanUp(capp))
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(service), service))
+}
+
+func TestRoutesOfCappUnderMaintenance(t *testing.T) {
+	capp := newTaggedCapp("preview")
+	capp.Spec.State = cappDisabledState
+	routing := RoutingConfig{MaintenanceService: testMaintenanceService}
+
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = knativev1beta1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig).Build()
+	domainMappingManager := KnativeDomainMappingManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), Routing: routing}
+
+	for _, routeHostname := range []RouteHostname{{Hostname: "app.capp-zone.com"}, {Hostname: "preview-app.capp-zone.com", Tag: "preview"}} {
+		domainMapping, err := domainMappingManager.prepareResource(capp, routeHostname)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-capp-maintenance", domainMapping.Spec.Ref.Name)
+		assert.Equal(t, "v1", domainMapping.Spec.Ref.APIVersion)
+	}
+
+	httpRouteManager, _ := newHTTPRouteManager()
+	httpRouteManager.Routing.MaintenanceService = testMaintenanceService
+	httpRouteManager.Routing.MaintenanceServicePort = 8080
+	httpRoute := httpRouteManager.prepareResource(capp, RouteHostname{Hostname: "app.capp-zone.com"})
+	rule := httpRoute.Spec.Rules[0]
+	assert.Empty(t, rule.Filters)
+	assert.Equal(t, gatewayv1.ObjectName("test-capp-maintenance"), rule.BackendRefs[0].Name)
+	assert.Nil(t, rule.BackendRefs[0].Namespace)
+	assert.Equal(t, gatewayv1.PortNumber(8080), *rule.BackendRefs[0].Port)
+
+	capp.Spec.State = cappEnabledState
+	domainMapping, err := domainMappingManager.prepareResource(capp, RouteHostname{Hostname: "app.capp-zone.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test-capp", domainMapping.Spec.Ref.Name)
+}
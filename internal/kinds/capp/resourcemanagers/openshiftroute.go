@@ -0,0 +1,241 @@
+package resourcemanagers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpenShiftRoute                        = "openshiftRoute"
+	eventCappOpenShiftRouteCreationFailed = "OpenShiftRouteCreationFailed"
+	eventCappOpenShiftRouteCreated        = "OpenShiftRouteCreated"
+	routeTargetPort                       = "http2"
+	routeTLSTargetPort                    = "https"
+	routeTargetWeight                     = 100
+	routeTimeoutAnnotationKey             = "haproxy.router.openshift.io/timeout"
+)
+
+// OpenShiftRouteManager exposes the hostnames of a Capp using OpenShift Routes. The Routes are created in
+// the namespace of the Knative ingress and send requests to it, which routes them to the Capp according to
+// its DomainMappings. When TLS is enabled, the Routes use passthrough termination, so that TLS is terminated
+// by the Knative ingress using the TLS secrets of the DomainMappings, and keys never leave the Capp namespace.
+type OpenShiftRouteManager struct {
+	Ctx           context.Context
+	K8sclient     client.Client
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+	Routing       RoutingConfig
+}
+
+// prepareResource prepares an OpenShift Route resource for a hostname of the provided Capp. When TLS is enabled,
+// the Route passes TLS through to the HTTPS port of the Knative ingress.
+func (o OpenShiftRouteManager) prepareResource(capp cappv1alpha1.Capp, routeHostname RouteHostname) routev1.Route {
+	weight := int32(routeTargetWeight)
+	route := routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      routeHostname.Hostname,
+			Namespace: o.Routing.RouteIngress.Namespace,
+			Labels: map[string]string{
+				utils.CappResourceKey:   capp.Name,
+				utils.CappNamespaceKey:  capp.Namespace,
+				utils.ManagedByLabelKey: utils.CappKey,
+			},
+		},
+		Spec: routev1.RouteSpec{
+			Host: routeHostname.Hostname,
+			To: routev1.RouteTargetReference{
+				Kind:   referenceKind,
+				Name:   o.Routing.RouteIngress.Name,
+				Weight: &weight,
+			},
+			Port:           &routev1.RoutePort{TargetPort: intstr.FromString(routeTargetPort)},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+
+	if timeoutSeconds := capp.Spec.RouteSpec.RouteTimeoutSeconds; timeoutSeconds != nil {
+		route.Annotations = map[string]string{routeTimeoutAnnotationKey: fmt.Sprintf("%ds", *timeoutSeconds)}
+	}
+
+	if capp.Spec.RouteSpec.TlsEnabled {
+		route.Spec.Port = &routev1.RoutePort{TargetPort: intstr.FromString(routeTLSTargetPort)}
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationPassthrough,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		}
+	}
+
+	return route
+}
+
+// CleanUp attempts to delete the associated OpenShift Routes for a given Capp resource.
+// There is nothing to clean up if the cluster is not OpenShift.
+func (o OpenShiftRouteManager) CleanUp(capp cappv1alpha1.Capp) error {
+	if !o.K8sclient.Scheme().Recognizes(routev1.GroupVersion.WithKind("Route")) {
+		return nil
+	}
+
+	resourceManager := rclient.ResourceManagerClient{Ctx: o.Ctx, K8sclient: o.K8sclient, Log: o.Log}
+
+	routes, err := o.getPreviousRoutes(capp)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return o.deletePreviousRoutes(routes, resourceManager, nil)
+}
+
+// IsRequired is responsible to determine if resource OpenShift Route is required.
+func (o OpenShiftRouteManager) IsRequired(capp cappv1alpha1.Capp) bool {
+	return isHostnameRouted(capp) && isRoutingBackend(o.Routing.Backend, RoutingBackendOpenShiftRoute)
+}
+
+// Manage creates or updates an OpenShift Route resource based on the provided Capp if it's required.
+// If it's not, then it cleans up the resource if it exists.
+func (o OpenShiftRouteManager) Manage(capp cappv1alpha1.Capp) error {
+	if o.IsRequired(capp) {
+		return o.createOrUpdate(capp)
+	}
+
+	return o.CleanUp(capp)
+}
+
+// createOrUpdate creates or updates the OpenShift Route resources of every hostname which a Capp owns.
+func (o OpenShiftRouteManager) createOrUpdate(capp cappv1alpha1.Capp) error {
+	routeHostnames, err := getRouteHostnames(o.Ctx, o.K8sclient, capp, "")
+	if err != nil {
+		return fmt.Errorf("failed to get hostnames: %w", err)
+	}
+
+	resourceManager := rclient.ResourceManagerClient{Ctx: o.Ctx, K8sclient: o.K8sclient, Log: o.Log}
+	for _, routeHostname := range routeHostnames {
+		if err := o.createOrUpdateRoute(capp, routeHostname, resourceManager); err != nil {
+			return err
+		}
+	}
+
+	if err := o.handlePreviousRoutes(capp, resourceManager, routeHostnames); err != nil {
+		return fmt.Errorf("failed to delete previous Routes: %w", err)
+	}
+
+	return nil
+}
+
+// createOrUpdateRoute creates or updates the OpenShift Route resource of a hostname of a Capp.
+func (o OpenShiftRouteManager) createOrUpdateRoute(capp cappv1alpha1.Capp, routeHostname RouteHostname, resourceManager rclient.ResourceManagerClient) error {
+	routeFromCapp := o.prepareResource(capp, routeHostname)
+	route := routev1.Route{}
+	if err := o.K8sclient.Get(o.Ctx, types.NamespacedName{Namespace: routeFromCapp.Namespace, Name: routeFromCapp.Name}, &route); err != nil {
+		if errors.IsNotFound(err) {
+			return o.createRoute(capp, routeFromCapp, resourceManager)
+		}
+		return fmt.Errorf("failed to get Route %q: %w", routeFromCapp.Name, err)
+	}
+
+	timeout := routeFromCapp.Annotations[routeTimeoutAnnotationKey]
+	if !reflect.DeepEqual(route.Spec, routeFromCapp.Spec) || route.Annotations[routeTimeoutAnnotationKey] != timeout {
+		route.Spec = routeFromCapp.Spec
+		if timeout != "" {
+			if route.Annotations == nil {
+				route.Annotations = map[string]string{}
+			}
+			route.Annotations[routeTimeoutAnnotationKey] = timeout
+		} else {
+			delete(route.Annotations, routeTimeoutAnnotationKey)
+		}
+		return resourceManager.UpdateResource(&route)
+	}
+
+	return nil
+}
+
+// createRoute creates a new OpenShift Route and emits an event.
+func (o OpenShiftRouteManager) createRoute(capp cappv1alpha1.Capp, routeFromCapp routev1.Route, resourceManager rclient.ResourceManagerClient) error {
+	if err := resourceManager.CreateResource(&routeFromCapp); err != nil {
+		o.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappOpenShiftRouteCreationFailed,
+			fmt.Sprintf("Failed to create Route %s", routeFromCapp.Name))
+
+		return err
+	}
+
+	o.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventCappOpenShiftRouteCreated,
+		fmt.Sprintf("Created Route %s", routeFromCapp.Name))
+
+	return nil
+}
+
+// handlePreviousRoutes takes care of removing unneeded OpenShift Route objects. If the DNSRecord of the
+// hostname of the Capp is not yet available then return early and do not delete the previous Routes.
+// When the Capp has no hostnames left, all of its Routes are deleted.
+func (o OpenShiftRouteManager) handlePreviousRoutes(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, routeHostnames []RouteHostname) error {
+	if len(routeHostnames) > 0 {
+		available, err := isDNSRecordAvailable(o.Ctx, o.K8sclient, routeHostnames[0].Hostname, capp.Namespace)
+		if err != nil {
+			return err
+		}
+
+		if !available {
+			return nil
+		}
+	}
+
+	routes, err := o.getPreviousRoutes(capp)
+	if err != nil {
+		return err
+	}
+
+	return o.deletePreviousRoutes(routes, resourceManager, hostnameSet(routeHostnames))
+}
+
+// getPreviousRoutes returns a list of all OpenShift Route objects that are related to the given Capp.
+// The Routes are not in the namespace of the Capp, so they are matched by the namespace label as well.
+func (o OpenShiftRouteManager) getPreviousRoutes(capp cappv1alpha1.Capp) (routev1.RouteList, error) {
+	routes := routev1.RouteList{}
+
+	set := labels.Set{
+		utils.CappResourceKey:  capp.Name,
+		utils.CappNamespaceKey: capp.Namespace,
+	}
+
+	listOptions := utils.GetListOptions(set)
+	if err := o.K8sclient.List(o.Ctx, &routes, &listOptions); err != nil {
+		return routes, fmt.Errorf("unable to list Routes of Capp %q: %w", capp.Name, err)
+	}
+
+	return routes, nil
+}
+
+// deletePreviousRoutes deletes all OpenShift Routes associated with a Capp which are not of one of the given hostnames.
+func (o OpenShiftRouteManager) deletePreviousRoutes(routes routev1.RouteList, resourceManager rclient.ResourceManagerClient, hostnames map[string]bool) error {
+	for _, route := range routes.Items {
+		if hostnames[route.Name] {
+			continue
+		}
+
+		bareRoute := rclient.GetBareOpenShiftRoute(route.Name, route.Namespace)
+		if err := resourceManager.DeleteResource(&bareRoute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestManageOpenShiftRoutes(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = routev1.Install(s)
+	_ = knativev1beta1.AddToScheme(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": testZone, "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app.capp-zone.com-tls", Namespace: "test-ns"},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig, tlsSecret).Build()
+	routing := RoutingConfig{
+		Backend:      RoutingBackendOpenShiftRoute,
+		RouteIngress: types.NamespacedName{Namespace: "knative-serving-ingress", Name: "kourier"},
+	}
+	manager := OpenShiftRouteManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10), Routing: routing}
+	ctx := context.Background()
+
+	capp := newTaggedCapp("preview")
+	capp.Spec.RouteSpec.TlsEnabled = true
+	assert.NoError(t, manager.Manage(capp))
+
+	route := routev1.Route{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "knative-serving-ingress", Name: "app.capp-zone.com"}, &route))
+	assert.Equal(t, "app.capp-zone.com", route.Spec.Host)
+	assert.Equal(t, "kourier", route.Spec.To.Name)
+	assert.Equal(t, "test-ns", route.Labels[utils.CappNamespaceKey])
+	assert.Equal(t, routev1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+	assert.Equal(t, "https", route.Spec.Port.TargetPort.StrVal)
+	assert.Empty(t, route.Spec.TLS.Certificate)
+	assert.Empty(t, route.Spec.TLS.Key)
+
+	// The Route of a hostname whose TLS secret does not exist yet passes TLS through as well.
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "knative-serving-ingress", Name: "preview-app.capp-zone.com"}, &route))
+	assert.Equal(t, routev1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+
+	capp.Spec.RouteSpec.TlsEnabled = false
+	assert.NoError(t, manager.Manage(capp))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "knative-serving-ingress", Name: "app.capp-zone.com"}, &route))
+	assert.Nil(t, route.Spec.TLS)
+	assert.Equal(t, "http2", route.Spec.Port.TargetPort.StrVal)
+
+	domainMappingManager := KnativeDomainMappingManager{Ctx: ctx, K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10), Routing: routing}
+	assert.True(t, domainMappingManager.IsRequired(capp))
+	capp.Spec.RouteSpec.TlsEnabled = true
+	assert.NoError(t, domainMappingManager.Manage(capp))
+	domainMapping := knativev1beta1.DomainMapping{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "app.capp-zone.com"}, &domainMapping))
+	assert.Equal(t, "app.capp-zone.com-tls", domainMapping.Spec.TLS.SecretName)
+	assert.Equal(t, "true", domainMapping.Annotations[disableRouteAnnotationKey])
+
+	assert.NoError(t, manager.CleanUp(capp))
+	routes := routev1.RouteList{}
+	assert.NoError(t, k8sClient.List(ctx, &routes))
+	assert.Empty(t, routes.Items)
+}
@@ -0,0 +1,299 @@
+package resourcemanagers
+
+import (
+	"reflect"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	dnsrecordsetv1alpha1 "github.com/dana-team/provider-dns/apis/recordset/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// providerDNSBackend manages the DNS records of hostnames using cluster-scoped crossplane provider-dns objects,
+// with one object for each type of DNS record of a hostname.
+type providerDNSBackend struct{}
+
+func (providerDNSBackend) name() string {
+	return utils.DNSBackendProviderDNS
+}
+
+func (providerDNSBackend) recordKinds() []dnsRecordKind {
+	return []dnsRecordKind{cnameRecordKind{}, aRecordSetKind{}, aaaaRecordSetKind{}, txtRecordSetKind{}}
+}
+
+func (providerDNSBackend) requiredRecordKinds(options dnsRecordOptions) []dnsRecordKind {
+	kinds := map[string]dnsRecordKind{
+		RecordTypeCNAME: cnameRecordKind{},
+		RecordTypeA:     aRecordSetKind{},
+		RecordTypeAAAA:  aaaaRecordSetKind{},
+		RecordTypeTXT:   txtRecordSetKind{},
+	}
+
+	var requiredKinds []dnsRecordKind
+	for _, recordType := range options.recordTypes() {
+		requiredKinds = append(requiredKinds, kinds[recordType])
+	}
+
+	return requiredKinds
+}
+
+// providerDNSReadyCondition returns the Ready condition of the provider-dns record of the given type.
+func providerDNSReadyCondition(dnsRecordStatus cappv1alpha1.DNSRecordObjectStatus, recordType string) xpcommonv1.Condition {
+	switch recordType {
+	case RecordTypeA:
+		return dnsRecordStatus.ARecordSetObjectStatus.GetCondition(xpcommonv1.TypeReady)
+	case RecordTypeAAAA:
+		return dnsRecordStatus.AAAARecordSetObjectStatus.GetCondition(xpcommonv1.TypeReady)
+	case RecordTypeTXT:
+		return dnsRecordStatus.TXTRecordSetObjectStatus.GetCondition(xpcommonv1.TypeReady)
+	default:
+		return dnsRecordStatus.CNAMERecordObjectStatus.GetCondition(xpcommonv1.TypeReady)
+	}
+}
+
+// providerDNSObjectMeta returns the ObjectMeta of a cluster-scoped provider-dns record.
+func providerDNSObjectMeta(key types.NamespacedName, recordLabels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: key.Name, Labels: recordLabels}
+}
+
+// providerDNSResourceSpec returns the ResourceSpec of a provider-dns record, which references the provider config.
+func providerDNSResourceSpec(options dnsRecordOptions) xpcommonv1.ResourceSpec {
+	return xpcommonv1.ResourceSpec{
+		ProviderConfigReference: &xpcommonv1.Reference{
+			Name: options.xpProvider,
+		},
+	}
+}
+
+// providerDNSFloatTTL returns the TTL of the options in the format of the CNAMERecord and TXTRecordSet APIs.
+func providerDNSFloatTTL(options dnsRecordOptions) *float64 {
+	if options.ttl == nil {
+		return nil
+	}
+
+	ttl := float64(*options.ttl)
+	return &ttl
+}
+
+// stringPointers returns pointers to the given strings.
+func stringPointers(values []string) []*string {
+	pointers := make([]*string, 0, len(values))
+	for i := range values {
+		pointers = append(pointers, &values[i])
+	}
+
+	return pointers
+}
+
+// cnameRecordKind is the CNAMERecord of a hostname, which points at the canonical name from the DNS ConfigMap.
+type cnameRecordKind struct{}
+
+func (cnameRecordKind) groupVersionKind() schema.GroupVersionKind {
+	return dnsrecordv1alpha1.CNAMERecord_GroupVersionKind
+}
+
+func (cnameRecordKind) recordKey(hostname, _ string) types.NamespacedName {
+	return types.NamespacedName{Name: hostname}
+}
+
+func (cnameRecordKind) newRecord(key types.NamespacedName) client.Object {
+	return &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+}
+
+func (cnameRecordKind) newRecordList() client.ObjectList {
+	return &dnsrecordv1alpha1.CNAMERecordList{}
+}
+
+func (cnameRecordKind) prepareRecord(key types.NamespacedName, hostname string, recordLabels map[string]string, options dnsRecordOptions) (client.Object, error) {
+	recordName := utils.GenerateRecordName(hostname, options.zone)
+	zone, cname := options.zone, options.cname
+
+	return &dnsrecordv1alpha1.CNAMERecord{
+		ObjectMeta: providerDNSObjectMeta(key, recordLabels),
+		Spec: dnsrecordv1alpha1.CNAMERecordSpec{
+			ForProvider: dnsrecordv1alpha1.CNAMERecordParameters{
+				Name:  &recordName,
+				Zone:  &zone,
+				Cname: &cname,
+				TTL:   providerDNSFloatTTL(options),
+			},
+			ResourceSpec: providerDNSResourceSpec(options),
+		},
+	}, nil
+}
+
+func (cnameRecordKind) updateRecord(existing, desired client.Object) bool {
+	existingRecord := existing.(*dnsrecordv1alpha1.CNAMERecord)
+	desiredRecord := desired.(*dnsrecordv1alpha1.CNAMERecord)
+	if reflect.DeepEqual(existingRecord.Spec, desiredRecord.Spec) {
+		return false
+	}
+
+	existingRecord.Spec = desiredRecord.Spec
+	return true
+}
+
+func (cnameRecordKind) setRecordStatus(record client.Object, dnsRecordStatus *cappv1alpha1.DNSRecordObjectStatus) {
+	dnsRecordStatus.CNAMERecordObjectStatus = record.(*dnsrecordv1alpha1.CNAMERecord).Status
+}
+
+// aRecordSetKind is the ARecordSet of a hostname, which points at the IPv4 addresses from the DNS ConfigMap.
+type aRecordSetKind struct{}
+
+func (aRecordSetKind) groupVersionKind() schema.GroupVersionKind {
+	return dnsrecordsetv1alpha1.ARecordSet_GroupVersionKind
+}
+
+func (aRecordSetKind) recordKey(hostname, _ string) types.NamespacedName {
+	return types.NamespacedName{Name: hostname}
+}
+
+func (aRecordSetKind) newRecord(key types.NamespacedName) client.Object {
+	return &dnsrecordsetv1alpha1.ARecordSet{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+}
+
+func (aRecordSetKind) newRecordList() client.ObjectList {
+	return &dnsrecordsetv1alpha1.ARecordSetList{}
+}
+
+func (aRecordSetKind) prepareRecord(key types.NamespacedName, hostname string, recordLabels map[string]string, options dnsRecordOptions) (client.Object, error) {
+	recordName := utils.GenerateRecordName(hostname, options.zone)
+	zone := options.zone
+
+	return &dnsrecordsetv1alpha1.ARecordSet{
+		ObjectMeta: providerDNSObjectMeta(key, recordLabels),
+		Spec: dnsrecordsetv1alpha1.ARecordSetSpec{
+			ForProvider: dnsrecordsetv1alpha1.ARecordSetParameters{
+				Name:      &recordName,
+				Zone:      &zone,
+				Addresses: stringPointers(options.addressesOfType(RecordTypeA)),
+				TTL:       options.ttl,
+			},
+			ResourceSpec: providerDNSResourceSpec(options),
+		},
+	}, nil
+}
+
+func (aRecordSetKind) updateRecord(existing, desired client.Object) bool {
+	existingRecord := existing.(*dnsrecordsetv1alpha1.ARecordSet)
+	desiredRecord := desired.(*dnsrecordsetv1alpha1.ARecordSet)
+	if reflect.DeepEqual(existingRecord.Spec, desiredRecord.Spec) {
+		return false
+	}
+
+	existingRecord.Spec = desiredRecord.Spec
+	return true
+}
+
+func (aRecordSetKind) setRecordStatus(record client.Object, dnsRecordStatus *cappv1alpha1.DNSRecordObjectStatus) {
+	dnsRecordStatus.ARecordSetObjectStatus = record.(*dnsrecordsetv1alpha1.ARecordSet).Status
+}
+
+// aaaaRecordSetKind is the AAAARecordSet of a hostname, which points at the IPv6 addresses from the DNS ConfigMap.
+type aaaaRecordSetKind struct{}
+
+func (aaaaRecordSetKind) groupVersionKind() schema.GroupVersionKind {
+	return dnsrecordsetv1alpha1.AAAARecordSet_GroupVersionKind
+}
+
+func (aaaaRecordSetKind) recordKey(hostname, _ string) types.NamespacedName {
+	return types.NamespacedName{Name: hostname}
+}
+
+func (aaaaRecordSetKind) newRecord(key types.NamespacedName) client.Object {
+	return &dnsrecordsetv1alpha1.AAAARecordSet{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+}
+
+func (aaaaRecordSetKind) newRecordList() client.ObjectList {
+	return &dnsrecordsetv1alpha1.AAAARecordSetList{}
+}
+
+func (aaaaRecordSetKind) prepareRecord(key types.NamespacedName, hostname string, recordLabels map[string]string, options dnsRecordOptions) (client.Object, error) {
+	recordName := utils.GenerateRecordName(hostname, options.zone)
+	zone := options.zone
+
+	return &dnsrecordsetv1alpha1.AAAARecordSet{
+		ObjectMeta: providerDNSObjectMeta(key, recordLabels),
+		Spec: dnsrecordsetv1alpha1.AAAARecordSetSpec{
+			ForProvider: dnsrecordsetv1alpha1.AAAARecordSetParameters{
+				Name:      &recordName,
+				Zone:      &zone,
+				Addresses: stringPointers(options.addressesOfType(RecordTypeAAAA)),
+				TTL:       options.ttl,
+			},
+			ResourceSpec: providerDNSResourceSpec(options),
+		},
+	}, nil
+}
+
+func (aaaaRecordSetKind) updateRecord(existing, desired client.Object) bool {
+	existingRecord := existing.(*dnsrecordsetv1alpha1.AAAARecordSet)
+	desiredRecord := desired.(*dnsrecordsetv1alpha1.AAAARecordSet)
+	if reflect.DeepEqual(existingRecord.Spec, desiredRecord.Spec) {
+		return false
+	}
+
+	existingRecord.Spec = desiredRecord.Spec
+	return true
+}
+
+func (aaaaRecordSetKind) setRecordStatus(record client.Object, dnsRecordStatus *cappv1alpha1.DNSRecordObjectStatus) {
+	dnsRecordStatus.AAAARecordSetObjectStatus = record.(*dnsrecordsetv1alpha1.AAAARecordSet).Status
+}
+
+// txtRecordSetKind is the TXTRecordSet of a hostname, which holds its ownership record.
+type txtRecordSetKind struct{}
+
+func (txtRecordSetKind) groupVersionKind() schema.GroupVersionKind {
+	return dnsrecordsetv1alpha1.TXTRecordSet_GroupVersionKind
+}
+
+func (txtRecordSetKind) recordKey(hostname, _ string) types.NamespacedName {
+	return types.NamespacedName{Name: hostname}
+}
+
+func (txtRecordSetKind) newRecord(key types.NamespacedName) client.Object {
+	return &dnsrecordsetv1alpha1.TXTRecordSet{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+}
+
+func (txtRecordSetKind) newRecordList() client.ObjectList {
+	return &dnsrecordsetv1alpha1.TXTRecordSetList{}
+}
+
+func (txtRecordSetKind) prepareRecord(key types.NamespacedName, hostname string, recordLabels map[string]string, options dnsRecordOptions) (client.Object, error) {
+	recordName := OwnershipRecordPrefix + utils.GenerateRecordName(hostname, options.zone)
+	zone := options.zone
+
+	return &dnsrecordsetv1alpha1.TXTRecordSet{
+		ObjectMeta: providerDNSObjectMeta(key, recordLabels),
+		Spec: dnsrecordsetv1alpha1.TXTRecordSetSpec{
+			ForProvider: dnsrecordsetv1alpha1.TXTRecordSetParameters{
+				Name: &recordName,
+				Zone: &zone,
+				Txt:  stringPointers([]string{options.ownershipRecordValue()}),
+				TTL:  providerDNSFloatTTL(options),
+			},
+			ResourceSpec: providerDNSResourceSpec(options),
+		},
+	}, nil
+}
+
+func (txtRecordSetKind) updateRecord(existing, desired client.Object) bool {
+	existingRecord := existing.(*dnsrecordsetv1alpha1.TXTRecordSet)
+	desiredRecord := desired.(*dnsrecordsetv1alpha1.TXTRecordSet)
+	if reflect.DeepEqual(existingRecord.Spec, desiredRecord.Spec) {
+		return false
+	}
+
+	existingRecord.Spec = desiredRecord.Spec
+	return true
+}
+
+func (txtRecordSetKind) setRecordStatus(record client.Object, dnsRecordStatus *cappv1alpha1.DNSRecordObjectStatus) {
+	dnsRecordStatus.TXTRecordSetObjectStatus = record.(*dnsrecordsetv1alpha1.TXTRecordSet).Status
+}
@@ -0,0 +1,93 @@
+package resourcemanagers
+
+import (
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// RoutingBackendDomainMapping routes the hostnames of Capps using Knative DomainMappings.
+	RoutingBackendDomainMapping = "domainMapping"
+
+	// RoutingBackendGatewayAPI routes the hostnames of Capps using Gateway API HTTPRoutes.
+	RoutingBackendGatewayAPI = "gatewayAPI"
+
+	// RoutingBackendOpenShiftRoute exposes the hostnames of Capps using OpenShift Routes to the Knative ingress.
+	// The Knative ingress still learns the hostnames from DomainMappings, but TLS is terminated by the Routes.
+	RoutingBackendOpenShiftRoute = "openshiftRoute"
+
+	// DefaultClusterDomain is the default DNS domain of the cluster.
+	DefaultClusterDomain = "cluster.local"
+
+	// DefaultMaintenanceServicePort is the default port of the Service of the maintenance page.
+	DefaultMaintenanceServicePort = 80
+)
+
+// RoutingBackends are the supported backends for routing the hostnames of Capps.
+var RoutingBackends = []string{RoutingBackendDomainMapping, RoutingBackendGatewayAPI, RoutingBackendOpenShiftRoute}
+
+// RoutingConfig defines how the custom hostnames of Capps are routed.
+type RoutingConfig struct {
+	// Backend is the routing backend, one of RoutingBackends. It defaults to RoutingBackendDomainMapping.
+	Backend string
+
+	// Gateway is the Gateway which HTTPRoutes are attached to when using RoutingBackendGatewayAPI.
+	Gateway types.NamespacedName
+
+	// GatewayBackend is the Service of the Knative ingress which HTTPRoutes send requests to
+	// when using RoutingBackendGatewayAPI.
+	GatewayBackend types.NamespacedName
+
+	// RouteIngress is the Service of the Knative ingress which OpenShift Routes send requests to
+	// when using RoutingBackendOpenShiftRoute. The Routes are created in its namespace.
+	RouteIngress types.NamespacedName
+
+	// MaintenanceService is the Service of the maintenance page which the hostnames of disabled Capps are
+	// routed to. The hostnames of disabled Capps are not routed anywhere if it is not set.
+	MaintenanceService types.NamespacedName
+
+	// MaintenanceServicePort is the port of the Service of the maintenance page. It defaults to
+	// DefaultMaintenanceServicePort.
+	MaintenanceServicePort int32
+
+	// ClusterDomain is the DNS domain of the cluster, which the internal hostnames of Services end with.
+	// It defaults to DefaultClusterDomain.
+	ClusterDomain string
+}
+
+// maintenanceServicePort returns the port of the Service of the maintenance page.
+func (r RoutingConfig) maintenanceServicePort() int32 {
+	if r.MaintenanceServicePort == 0 {
+		return DefaultMaintenanceServicePort
+	}
+
+	return r.MaintenanceServicePort
+}
+
+// ServiceHostname returns the internal hostname of a Service in the cluster domain.
+func (r RoutingConfig) ServiceHostname(name, namespace string) string {
+	clusterDomain := r.ClusterDomain
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+
+	return fmt.Sprintf("%s.%s.svc.%s", name, namespace, clusterDomain)
+}
+
+// isRoutingBackend returns a boolean indicating whether the configured routing backend is the given one.
+// An empty configured routing backend is the DomainMapping backend.
+func isRoutingBackend(configured, backend string) bool {
+	if configured == "" {
+		configured = RoutingBackendDomainMapping
+	}
+
+	return configured == backend
+}
+
+// isHostnameRouted returns a boolean indicating whether the custom hostnames of a Capp need to be routed.
+func isHostnameRouted(capp cappv1alpha1.Capp) bool {
+	return utils.IsCustomHostnameSet(capp.Spec.RouteSpec.Hostname) && !utils.IsClusterLocal(capp.Spec.RouteSpec)
+}
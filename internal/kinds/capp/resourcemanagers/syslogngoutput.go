@@ -27,7 +27,7 @@ const (
 	SyslogNGOutput                        = "syslogNGOutput"
 	eventCappSyslogNGOutputCreationFailed = "SyslogNGOutputCreationFailed"
 	eventCappSyslogNGlSOutputCreated      = "SyslogNGOutputCreated"
-	logTypeElastic                        = "elastic"
+	LogTypeElastic                        = "elastic"
 	elasticSSLVersion                     = "tlsv1_2"
 	elasticTemplate                       = "$(format-json --subkeys json# --key-delimiter #)"
 	elasticSecretKey                      = "elastic"
@@ -42,7 +42,7 @@ type SyslogNGOutputManager struct {
 
 // syslogNGOutputCreators is a map that associates log types with their corresponding SyslogNGOutput creation functions.
 var syslogNGOutputCreators = map[string]func(cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec{
-	logTypeElastic: createElasticsearchOutput,
+	LogTypeElastic: createElasticsearchOutput,
 }
 
 // createElasticsearchOutput creates an Elasticsearch SyslogNGOutput object based on the provided logSpec.
@@ -0,0 +1,86 @@
+package resourcemanagers
+
+import (
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
+	"github.com/dana-team/container-app-operator/internal/kinds/capprevision/adapters"
+	"k8s.io/apimachinery/pkg/api/equality"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const eventCappTrafficResolutionFailed = "TrafficResolutionFailed"
+
+// GetTrafficTargets returns the traffic targets of a Capp, falling back to the deprecated
+// single TrafficTarget for Capps which were not updated since TrafficTargets was added.
+func GetTrafficTargets(capp cappv1alpha1.Capp) []cappv1alpha1.CappTrafficTarget {
+	routeSpec := capp.Spec.RouteSpec
+	if len(routeSpec.TrafficTargets) > 0 {
+		return routeSpec.TrafficTargets
+	}
+
+	if !equality.Semantic.DeepEqual(routeSpec.TrafficTarget, knativev1.TrafficTarget{}) {
+		return []cappv1alpha1.CappTrafficTarget{{TrafficTarget: routeSpec.TrafficTarget}}
+	}
+
+	return nil
+}
+
+// resolveTraffic translates the traffic targets of a Capp into the traffic block of a Knative Service,
+// resolving CappRevision numbers to the Knative Revisions recorded in the status of the CappRevisions.
+// Capps with a rollout get the traffic split of their current rollout step instead.
+func (k KnativeServiceManager) resolveTraffic(capp cappv1alpha1.Capp) ([]knativev1.TrafficTarget, error) {
+	if rollout.IsEnabled(capp) {
+		return rollout.Traffic(capp), nil
+	}
+
+	trafficTargets := GetTrafficTargets(capp)
+	if len(trafficTargets) == 0 {
+		return nil, nil
+	}
+
+	var knativeRevisionNames map[int]string
+	traffic := make([]knativev1.TrafficTarget, 0, len(trafficTargets))
+	for _, trafficTarget := range trafficTargets {
+		target := *trafficTarget.TrafficTarget.DeepCopy()
+
+		if trafficTarget.CappRevisionNumber != 0 {
+			if knativeRevisionNames == nil {
+				var err error
+				if knativeRevisionNames, err = k.getKnativeRevisionNames(capp); err != nil {
+					return nil, err
+				}
+			}
+
+			revisionName, ok := knativeRevisionNames[trafficTarget.CappRevisionNumber]
+			if !ok {
+				return nil, fmt.Errorf("CappRevision %d of Capp %q does not exist or has no Knative Revision yet",
+					trafficTarget.CappRevisionNumber, capp.Name)
+			}
+			target.RevisionName = revisionName
+			target.LatestRevision = new(bool)
+		}
+
+		traffic = append(traffic, target)
+	}
+
+	return traffic, nil
+}
+
+// getKnativeRevisionNames returns the names of the Knative Revisions of the CappRevisions of a Capp by RevisionNumber.
+func (k KnativeServiceManager) getKnativeRevisionNames(capp cappv1alpha1.Capp) (map[int]string, error) {
+	cappRevisions, err := adapters.GetCappRevisions(k.Ctx, k.K8sclient, capp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CappRevisions of Capp %q: %w", capp.Name, err)
+	}
+
+	knativeRevisionNames := map[int]string{}
+	for _, cappRevision := range cappRevisions {
+		if cappRevision.Status.KnativeRevisionName != "" {
+			knativeRevisionNames[cappRevision.Spec.RevisionNumber] = cappRevision.Status.KnativeRevisionName
+		}
+	}
+
+	return knativeRevisionNames, nil
+}
@@ -0,0 +1,52 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveTraffic(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = cappv1alpha1.AddToScheme(s)
+	cappRevision := &cappv1alpha1.CappRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-capp-00001",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"rcs.dana.io/cappName": "test-capp"},
+		},
+		Spec:   cappv1alpha1.CappRevisionSpec{RevisionNumber: 1},
+		Status: cappv1alpha1.CappRevisionStatus{KnativeRevisionName: "test-capp-00003"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(cappRevision).Build()
+	manager := KnativeServiceManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(1)}
+
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	traffic, err := manager.resolveTraffic(capp)
+	assert.NoError(t, err)
+	assert.Nil(t, traffic)
+
+	percent := func(value int64) *int64 { return &value }
+	capp.Spec.RouteSpec.TrafficTargets = []cappv1alpha1.CappTrafficTarget{
+		{CappRevisionNumber: 1, TrafficTarget: knativev1.TrafficTarget{Percent: percent(90)}},
+		{TrafficTarget: knativev1.TrafficTarget{RevisionName: "test-capp-00004", Tag: "canary", Percent: percent(10)}},
+	}
+	traffic, err = manager.resolveTraffic(capp)
+	assert.NoError(t, err)
+	assert.Equal(t, []knativev1.TrafficTarget{
+		{RevisionName: "test-capp-00003", LatestRevision: new(bool), Percent: percent(90)},
+		{RevisionName: "test-capp-00004", Tag: "canary", Percent: percent(10)},
+	}, traffic)
+
+	capp.Spec.RouteSpec.TrafficTargets[0].CappRevisionNumber = 2
+	_, err = manager.resolveTraffic(capp)
+	assert.Error(t, err)
+}
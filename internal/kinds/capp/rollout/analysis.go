@@ -0,0 +1,292 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	queryPath         = "/api/v1/query"
+	queryTimeout      = 10 * time.Second
+	resultTypeVector  = "vector"
+	resultTypeScalar  = "scalar"
+	querySuccessState = "success"
+
+	// analysisPollInterval is the interval at which a running analysis is checked for its results.
+	analysisPollInterval = 5 * time.Second
+
+	// analysisRetryInterval is the interval after which a failed analysis is retried.
+	analysisRetryInterval = 30 * time.Second
+)
+
+// MetricsProvider runs queries against a Prometheus-compatible query API.
+type MetricsProvider interface {
+	// Query returns the single value returned by a query, and false if the query returned no data.
+	Query(ctx context.Context, address, query string) (float64, bool, error)
+}
+
+// PrometheusProvider is a MetricsProvider which uses the HTTP API of Prometheus.
+type PrometheusProvider struct {
+	Client *http.Client
+}
+
+// queryResponse is the response of the instant query endpoint of the Prometheus HTTP API.
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// vectorSample is a single sample of a vector result.
+type vectorSample struct {
+	Value []interface{} `json:"value"`
+}
+
+// NewPrometheusProvider returns a PrometheusProvider with a default HTTP client.
+func NewPrometheusProvider() PrometheusProvider {
+	return PrometheusProvider{Client: &http.Client{Timeout: queryTimeout}}
+}
+
+// Query runs an instant query and returns its value. Vector results must hold at most a single sample.
+func (p PrometheusProvider) Query(ctx context.Context, address, query string) (float64, bool, error) {
+	queryURL := strings.TrimSuffix(address, "/") + queryPath + "?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create query request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to run query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response := queryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, false, fmt.Errorf("failed to decode query response with status %d: %w", resp.StatusCode, err)
+	}
+	if response.Status != querySuccessState {
+		return 0, false, fmt.Errorf("query failed: %s", response.Error)
+	}
+
+	var sample []interface{}
+	switch response.Data.ResultType {
+	case resultTypeScalar:
+		if err := json.Unmarshal(response.Data.Result, &sample); err != nil {
+			return 0, false, fmt.Errorf("failed to decode scalar result: %w", err)
+		}
+	case resultTypeVector:
+		var samples []vectorSample
+		if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return 0, false, fmt.Errorf("failed to decode vector result: %w", err)
+		}
+		if len(samples) == 0 {
+			return 0, false, nil
+		}
+		if len(samples) > 1 {
+			return 0, false, fmt.Errorf("query returned %d samples instead of a single one", len(samples))
+		}
+		sample = samples[0].Value
+	default:
+		return 0, false, fmt.Errorf("unsupported result type %q", response.Data.ResultType)
+	}
+
+	return parseSampleValue(sample)
+}
+
+// parseSampleValue parses the value of a [timestamp, "value"] sample, which is returned as a string.
+func parseSampleValue(sample []interface{}) (float64, bool, error) {
+	if len(sample) != 2 {
+		return 0, false, fmt.Errorf("malformed sample %v", sample)
+	}
+
+	rawValue, ok := sample[1].(string)
+	if !ok {
+		return 0, false, fmt.Errorf("malformed sample value %v", sample[1])
+	}
+
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("malformed sample value %q: %w", rawValue, err)
+	}
+	if math.IsNaN(value) {
+		return 0, false, nil
+	}
+
+	return value, true, nil
+}
+
+// Analyzer runs the analyses of rollouts in the background, so that slow queries do not block reconciling Capps.
+// It runs at most a single analysis per Capp, and keeps its results until they are collected.
+type Analyzer struct {
+	// PrometheusAddress is the address of the Prometheus-compatible query API which metrics are queried from.
+	PrometheusAddress string
+	Provider          MetricsProvider
+
+	mu   sync.Mutex
+	runs map[types.NamespacedName]*analysisRun
+}
+
+// analysisRun is an analysis of a Capp which is running or whose results were not collected yet.
+type analysisRun struct {
+	id      string
+	done    bool
+	results []cappv1alpha1.AnalysisResult
+	passed  bool
+}
+
+// ValidatePrometheusAddress returns an error if a Prometheus address is set but is not an absolute URL.
+func ValidatePrometheusAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+
+	if parsedAddress, err := url.Parse(address); err != nil || parsedAddress.Scheme == "" || parsedAddress.Host == "" {
+		return fmt.Errorf("%q must be an absolute URL", address)
+	}
+
+	return nil
+}
+
+// NewAnalyzer returns an Analyzer which queries the Prometheus HTTP API at the given address.
+func NewAnalyzer(prometheusAddress string) *Analyzer {
+	return &Analyzer{PrometheusAddress: prometheusAddress, Provider: NewPrometheusProvider()}
+}
+
+// Analyze returns the results of the analysis with the given ID of a Capp and whether all of them passed, once the
+// analysis is done. Otherwise, it starts the analysis in the background unless it is already running, and returns
+// false. Starting an analysis discards the results of any previous analysis of the Capp which were not collected.
+func (a *Analyzer) Analyze(capp types.NamespacedName, id string, analysis cappv1alpha1.AnalysisSpec, parameters queryParameters) ([]cappv1alpha1.AnalysisResult, bool, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if run, ok := a.runs[capp]; ok && run.id == id {
+		if !run.done {
+			return nil, false, false
+		}
+		delete(a.runs, capp)
+		return run.results, run.passed, true
+	}
+
+	if a.runs == nil {
+		a.runs = map[types.NamespacedName]*analysisRun{}
+	}
+	run := &analysisRun{id: id}
+	a.runs[capp] = run
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), queryTimeout*time.Duration(len(analysis.Metrics)))
+		defer cancel()
+		results, passed := analyze(ctx, a.Provider, a.PrometheusAddress, analysis, parameters)
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		run.results, run.passed, run.done = results, passed, true
+	}()
+
+	return nil, false, false
+}
+
+// Forget discards the analysis of a Capp, if any.
+func (a *Analyzer) Forget(capp types.NamespacedName) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.runs, capp)
+}
+
+// queryParameters are the values which can be referenced in the query of an AnalysisMetric.
+type queryParameters struct {
+	Namespace string
+	Capp      string
+	Revision  string
+}
+
+// ValidateQuery returns an error if the query of an AnalysisMetric can not be rendered.
+func ValidateQuery(query string) error {
+	_, err := renderQuery(query, queryParameters{})
+	return err
+}
+
+// renderQuery renders the query template of an AnalysisMetric.
+func renderQuery(query string, parameters queryParameters) (string, error) {
+	queryTemplate, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	renderedQuery := strings.Builder{}
+	if err := queryTemplate.Execute(&renderedQuery, parameters); err != nil {
+		return "", fmt.Errorf("failed to render query: %w", err)
+	}
+
+	return renderedQuery.String(), nil
+}
+
+// analyze checks every metric of an AnalysisSpec against the given revision and returns
+// the results and whether all of them passed.
+func analyze(ctx context.Context, provider MetricsProvider, address string, analysis cappv1alpha1.AnalysisSpec, parameters queryParameters) ([]cappv1alpha1.AnalysisResult, bool) {
+	results := make([]cappv1alpha1.AnalysisResult, 0, len(analysis.Metrics))
+	passed := true
+
+	for _, metric := range analysis.Metrics {
+		result := checkMetric(ctx, provider, address, metric, parameters)
+		passed = passed && result.Passed
+		results = append(results, result)
+	}
+
+	return results, passed
+}
+
+// checkMetric checks a single metric. A metric which returns no data passes, since a revision which received
+// no requests during the step has no errors or latency to report. A metric whose query fails does not pass,
+// so that a rollout never proceeds without its analysis.
+func checkMetric(ctx context.Context, provider MetricsProvider, address string, metric cappv1alpha1.AnalysisMetric, parameters queryParameters) cappv1alpha1.AnalysisResult {
+	result := cappv1alpha1.AnalysisResult{Name: metric.Name}
+	if address == "" {
+		result.Message = "no Prometheus address is configured on the operator"
+		return result
+	}
+
+	query, err := renderQuery(metric.Query, parameters)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	value, found, err := provider.Query(ctx, address, query)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	if !found {
+		result.Passed = true
+		result.Message = "query returned no data"
+		return result
+	}
+
+	result.Value = strconv.FormatFloat(value, 'g', -1, 64)
+	if value > metric.Max.AsApproximateFloat64() {
+		result.Message = fmt.Sprintf("value is above the maximum of %s", metric.Max.String())
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
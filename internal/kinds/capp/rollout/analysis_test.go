@@ -0,0 +1,120 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPrometheusProviderQuery(t *testing.T) {
+	responses := map[string]string{
+		"vector":  `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.25"]}]}}`,
+		"scalar":  `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"3"]}}`,
+		"empty":   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		"nan":     `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"NaN"]}}`,
+		"many":    `{"status":"success","data":{"resultType":"vector","result":[{"value":[1,"1"]},{"value":[1,"2"]}]}}`,
+		"invalid": `{"status":"error","error":"parse error"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, queryPath, r.URL.Path)
+		_, _ = fmt.Fprint(w, responses[r.URL.Query().Get("query")])
+	}))
+	defer server.Close()
+
+	provider := NewPrometheusProvider()
+	ctx := context.Background()
+
+	value, found, err := provider.Query(ctx, server.URL, "vector")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 0.25, value)
+
+	value, found, err = provider.Query(ctx, server.URL+"/", "scalar")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 3.0, value)
+
+	for _, query := range []string{"empty", "nan"} {
+		_, found, err = provider.Query(ctx, server.URL, query)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	}
+
+	for _, query := range []string{"many", "invalid"} {
+		_, _, err = provider.Query(ctx, server.URL, query)
+		assert.Error(t, err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	provider := fakeMetricsProvider{values: map[string]float64{
+		`errors{revision="test-capp-00002"}`:  0.01,
+		`latency{revision="test-capp-00002"}`: 0.8,
+	}}
+	analysis := cappv1alpha1.AnalysisSpec{
+		Metrics: []cappv1alpha1.AnalysisMetric{
+			{Name: "error-rate", Query: `errors{revision="{{ .Revision }}"}`, Max: resource.MustParse("0.05")},
+			{Name: "requests", Query: `requests{revision="{{ .Revision }}"}`, Max: resource.MustParse("10")},
+		},
+	}
+	parameters := queryParameters{Namespace: "test-ns", Capp: "test-capp", Revision: "test-capp-00002"}
+
+	results, passed := analyze(context.Background(), provider, prometheusAddress, analysis, parameters)
+	assert.True(t, passed)
+	assert.Equal(t, []cappv1alpha1.AnalysisResult{
+		{Name: "error-rate", Value: "0.01", Passed: true},
+		{Name: "requests", Passed: true, Message: "query returned no data"},
+	}, results)
+
+	analysis.Metrics = append(analysis.Metrics,
+		cappv1alpha1.AnalysisMetric{Name: "latency", Query: `latency{revision="{{ .Revision }}"}`, Max: resource.MustParse("500m")})
+	results, passed = analyze(context.Background(), provider, prometheusAddress, analysis, parameters)
+	assert.False(t, passed)
+	assert.Equal(t, cappv1alpha1.AnalysisResult{
+		Name: "latency", Value: "0.8", Message: "value is above the maximum of 500m",
+	}, results[2])
+
+	results, passed = analyze(context.Background(), provider, "", analysis, parameters)
+	assert.False(t, passed)
+	assert.Equal(t, "no Prometheus address is configured on the operator", results[0].Message)
+}
+
+func TestAnalyzer(t *testing.T) {
+	analyzer := &Analyzer{PrometheusAddress: prometheusAddress, Provider: fakeMetricsProvider{values: map[string]float64{"errors": 0.2}}}
+	analysis := cappv1alpha1.AnalysisSpec{Metrics: []cappv1alpha1.AnalysisMetric{{Name: "error-rate", Query: "errors", Max: resource.MustParse("0.05")}}}
+	capp := types.NamespacedName{Namespace: "test-ns", Name: "test-capp"}
+
+	_, _, done := analyzer.Analyze(capp, "test-capp-00002/1/0", analysis, queryParameters{})
+	assert.False(t, done)
+	waitForAnalysis(t, analyzer)
+
+	// A different analysis of the same Capp discards the results of the previous one.
+	_, _, done = analyzer.Analyze(capp, "test-capp-00002/1/1", analysis, queryParameters{})
+	assert.False(t, done)
+	waitForAnalysis(t, analyzer)
+
+	results, passed, done := analyzer.Analyze(capp, "test-capp-00002/1/1", analysis, queryParameters{})
+	assert.True(t, done)
+	assert.False(t, passed)
+	assert.Equal(t, "0.2", results[0].Value)
+	assert.Empty(t, analyzer.runs)
+}
+
+func TestValidatePrometheusAddress(t *testing.T) {
+	assert.NoError(t, ValidatePrometheusAddress(""))
+	assert.NoError(t, ValidatePrometheusAddress(prometheusAddress))
+	assert.Error(t, ValidatePrometheusAddress("prometheus"))
+}
+
+func TestValidateQuery(t *testing.T) {
+	assert.NoError(t, ValidateQuery(`sum(rate(requests{namespace="{{ .Namespace }}", service="{{ .Capp }}"}[1m]))`))
+	assert.Error(t, ValidateQuery("{{ .Revision"))
+	assert.Error(t, ValidateQuery("{{ .Pod }}"))
+}
@@ -0,0 +1,205 @@
+package rollout
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capprevision/adapters"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PreviewTag is the Knative traffic tag of the revision awaiting promotion.
+	PreviewTag = "preview"
+
+	// PreviousTag is the Knative traffic tag of the previously active revision while it is kept warm.
+	PreviousTag = "previous"
+
+	defaultScaleDownDelaySeconds = 600
+
+	eventRolloutPreviewReady = "RolloutPreviewReady"
+	eventRolloutPromoted     = "RolloutPromoted"
+	eventRolloutAborted      = "RolloutAborted"
+)
+
+// getPromotedRevision returns the name of the Knative Revision of the CappRevision in the PromoteRevisionAnnotation
+// of a Capp, or an empty string if the annotation is not set or its CappRevision has no Knative Revision yet.
+func (m Manager) getPromotedRevision(capp cappv1alpha1.Capp) (string, error) {
+	value, ok := capp.Annotations[cappv1alpha1.PromoteRevisionAnnotation]
+	if !ok {
+		return "", nil
+	}
+
+	revisionNumber, err := strconv.Atoi(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid revision number %q in annotation %q", value, cappv1alpha1.PromoteRevisionAnnotation)
+	}
+
+	cappRevisions, err := adapters.GetCappRevisions(m.Ctx, m.K8sclient, capp)
+	if err != nil {
+		return "", fmt.Errorf("failed to get CappRevisions of Capp %q: %w", capp.Name, err)
+	}
+
+	for _, cappRevision := range cappRevisions {
+		if cappRevision.Spec.RevisionNumber == revisionNumber {
+			return cappRevision.Status.KnativeRevisionName, nil
+		}
+	}
+
+	return "", nil
+}
+
+// progressBlueGreen returns the next state of a blue/green rollout of a Capp given the latest ready revision of its
+// Knative Service and the revision requested in its PromoteRevisionAnnotation. A new revision awaits promotion without
+// traffic; promoting it sends all traffic to it and keeps the previous revision warm, and promoting the previous
+// revision back while it is warm aborts the promotion.
+func (m Manager) progressBlueGreen(capp cappv1alpha1.Capp, latestReadyRevision, promotedRevision string, now time.Time) (*cappv1alpha1.RolloutStatus, time.Duration) {
+	scaleDownDelay := time.Duration(defaultScaleDownDelaySeconds) * time.Second
+	if blueGreen := capp.Spec.RolloutSpec.BlueGreen; blueGreen != nil {
+		scaleDownDelay = time.Duration(blueGreen.ScaleDownDelaySeconds) * time.Second
+	}
+
+	status := &cappv1alpha1.RolloutStatus{}
+	if capp.Status.RolloutStatus != nil {
+		status = capp.Status.RolloutStatus.DeepCopy()
+	}
+	status.CanaryRevision = ""
+	status.CurrentStep = 0
+	status.CanaryPercent = 0
+
+	if status.StableRevision == "" {
+		if latestReadyRevision != "" {
+			status.StableRevision = latestReadyRevision
+			status.Phase = cappv1alpha1.RolloutPhaseSucceeded
+			status.Message = fmt.Sprintf("Revision %q is active", latestReadyRevision)
+		}
+		return status, 0
+	}
+
+	isNewRevision := latestReadyRevision != "" && latestReadyRevision != status.StableRevision &&
+		latestReadyRevision != status.PreviewRevision && latestReadyRevision != status.PreviousRevision
+	if isNewRevision {
+		status.Phase = cappv1alpha1.RolloutPhaseAwaitingPromotion
+		status.PreviewRevision = latestReadyRevision
+		status.Message = fmt.Sprintf("Revision %q is awaiting promotion", latestReadyRevision)
+		m.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventRolloutPreviewReady, status.Message)
+	}
+
+	switch {
+	case promotedRevision == "" || promotedRevision == status.StableRevision:
+	case promotedRevision == status.PreviewRevision:
+		status.Phase = cappv1alpha1.RolloutPhaseSucceeded
+		status.PreviousRevision = status.StableRevision
+		status.StableRevision = status.PreviewRevision
+		status.PreviewRevision = ""
+		status.PromotionTime = metav1.NewTime(now)
+		status.Message = fmt.Sprintf("Revision %q was promoted, revision %q is kept warm", status.StableRevision, status.PreviousRevision)
+		m.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventRolloutPromoted, status.Message)
+	case promotedRevision == status.PreviousRevision:
+		status.Phase = cappv1alpha1.RolloutPhaseAwaitingPromotion
+		status.PreviewRevision = status.StableRevision
+		status.StableRevision = status.PreviousRevision
+		status.PreviousRevision = ""
+		status.Message = fmt.Sprintf("Promotion of revision %q was aborted, revision %q is active again", status.PreviewRevision, status.StableRevision)
+		m.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventRolloutAborted, status.Message)
+	default:
+		status.Message = fmt.Sprintf("Revision %q can not be promoted since it is neither awaiting promotion nor kept warm", promotedRevision)
+	}
+
+	if status.PreviousRevision == "" {
+		return status, 0
+	}
+
+	elapsed := now.Sub(status.PromotionTime.Time)
+	if elapsed < scaleDownDelay {
+		return status, scaleDownDelay - elapsed
+	}
+
+	status.PreviousRevision = ""
+	return status, 0
+}
+
+// syncWarmRevision keeps the previous revision of a blue/green rollout warm by setting the minimum scale of its
+// PodAutoscaler to its current scale, and restores the minimum scale of the revision once it is released.
+// Knative only copies the annotations of a Revision to its PodAutoscaler when creating it, so they can be overridden.
+func (m Manager) syncWarmRevision(namespace string, oldStatus, newStatus *cappv1alpha1.RolloutStatus) error {
+	var oldPrevious, newPrevious string
+	if oldStatus != nil {
+		oldPrevious = oldStatus.PreviousRevision
+	}
+	if newStatus != nil {
+		newPrevious = newStatus.PreviousRevision
+	}
+
+	if oldPrevious == newPrevious {
+		return nil
+	}
+
+	if oldPrevious != "" {
+		if err := m.setMinScale(namespace, oldPrevious, false); err != nil {
+			return err
+		}
+	}
+
+	if newPrevious != "" {
+		return m.setMinScale(namespace, newPrevious, true)
+	}
+
+	return nil
+}
+
+// setMinScale overrides the minimum scale of the PodAutoscaler of a revision with its current scale if keepWarm is
+// true, and restores the minimum scale set on the revision otherwise.
+func (m Manager) setMinScale(namespace, revisionName string, keepWarm bool) error {
+	podAutoscaler := autoscalingv1alpha1.PodAutoscaler{}
+	if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: namespace, Name: revisionName}, &podAutoscaler); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get PodAutoscaler %q: %w", revisionName, err)
+	}
+
+	minScale := ""
+	if keepWarm {
+		scale := int32(1)
+		if podAutoscaler.Status.ActualScale != nil && *podAutoscaler.Status.ActualScale > scale {
+			scale = *podAutoscaler.Status.ActualScale
+		}
+		minScale = strconv.Itoa(int(scale))
+	} else {
+		revision := knativev1.Revision{}
+		if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: namespace, Name: revisionName}, &revision); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get Revision %q: %w", revisionName, err)
+		}
+		minScale = revision.Annotations[autoscaling.MinScaleAnnotationKey]
+	}
+
+	originalPodAutoscaler := podAutoscaler.DeepCopy()
+	if minScale == "" {
+		delete(podAutoscaler.Annotations, autoscaling.MinScaleAnnotationKey)
+	} else {
+		if podAutoscaler.Annotations == nil {
+			podAutoscaler.Annotations = map[string]string{}
+		}
+		podAutoscaler.Annotations[autoscaling.MinScaleAnnotationKey] = minScale
+	}
+
+	if err := m.K8sclient.Patch(m.Ctx, &podAutoscaler, client.MergeFrom(originalPodAutoscaler)); err != nil {
+		return fmt.Errorf("failed to patch PodAutoscaler %q: %w", revisionName, err)
+	}
+
+	m.Log.Info(fmt.Sprintf("Set minimum scale of PodAutoscaler %q to %q", revisionName, minScale))
+	return nil
+}
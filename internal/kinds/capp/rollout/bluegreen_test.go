@@ -0,0 +1,131 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newBlueGreenCapp() cappv1alpha1.Capp {
+	return cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			State: enabledState,
+			RolloutSpec: &cappv1alpha1.RolloutSpec{
+				Strategy:  cappv1alpha1.RolloutStrategyBlueGreen,
+				BlueGreen: &cappv1alpha1.BlueGreenSpec{ScaleDownDelaySeconds: 300},
+			},
+		},
+		Status: cappv1alpha1.CappStatus{RolloutStatus: &cappv1alpha1.RolloutStatus{
+			Phase:          cappv1alpha1.RolloutPhaseSucceeded,
+			StableRevision: "test-capp-00001",
+		}},
+	}
+}
+
+func TestProgressBlueGreenPromotesAndAborts(t *testing.T) {
+	manager := newRolloutManager(nil)
+	capp := newBlueGreenCapp()
+	now := time.Now()
+
+	status, requeueAfter := manager.progressBlueGreen(capp, "test-capp-00002", "", now)
+	assert.Equal(t, cappv1alpha1.RolloutPhaseAwaitingPromotion, status.Phase)
+	assert.Equal(t, "test-capp-00001", status.StableRevision)
+	assert.Equal(t, "test-capp-00002", status.PreviewRevision)
+	assert.Zero(t, requeueAfter)
+
+	capp.Status.RolloutStatus = status
+	status, requeueAfter = manager.progressBlueGreen(capp, "test-capp-00002", "test-capp-00002", now)
+	assert.Equal(t, cappv1alpha1.RolloutPhaseSucceeded, status.Phase)
+	assert.Equal(t, "test-capp-00002", status.StableRevision)
+	assert.Equal(t, "test-capp-00001", status.PreviousRevision)
+	assert.Empty(t, status.PreviewRevision)
+	assert.Equal(t, 5*time.Minute, requeueAfter)
+
+	capp.Status.RolloutStatus = status
+	aborted, requeueAfter := manager.progressBlueGreen(capp, "test-capp-00002", "test-capp-00001", now.Add(time.Minute))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseAwaitingPromotion, aborted.Phase)
+	assert.Equal(t, "test-capp-00001", aborted.StableRevision)
+	assert.Equal(t, "test-capp-00002", aborted.PreviewRevision)
+	assert.Empty(t, aborted.PreviousRevision)
+	assert.Zero(t, requeueAfter)
+
+	released, requeueAfter := manager.progressBlueGreen(capp, "test-capp-00002", "test-capp-00002", now.Add(5*time.Minute))
+	assert.Equal(t, "test-capp-00002", released.StableRevision)
+	assert.Empty(t, released.PreviousRevision)
+	assert.Zero(t, requeueAfter)
+
+	capp.Status.RolloutStatus = released
+	status, _ = manager.progressBlueGreen(capp, "test-capp-00002", "test-capp-00001", now.Add(6*time.Minute))
+	assert.Equal(t, "test-capp-00002", status.StableRevision)
+	assert.Contains(t, status.Message, "can not be promoted")
+}
+
+func TestSyncWarmRevision(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = knativev1.AddToScheme(s)
+	_ = autoscalingv1alpha1.AddToScheme(s)
+	actualScale := int32(3)
+	podAutoscaler := &autoscalingv1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp-00001", Namespace: "test-ns"},
+		Status:     autoscalingv1alpha1.PodAutoscalerStatus{ActualScale: &actualScale},
+	}
+	revision := &knativev1.Revision{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test-capp-00001",
+		Namespace:   "test-ns",
+		Annotations: map[string]string{autoscaling.MinScaleAnnotationKey: "1"},
+	}}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(podAutoscaler, revision).Build()
+	manager := Manager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(1)}
+
+	warm := &cappv1alpha1.RolloutStatus{StableRevision: "test-capp-00002", PreviousRevision: "test-capp-00001"}
+	assert.NoError(t, manager.syncWarmRevision("test-ns", &cappv1alpha1.RolloutStatus{}, warm))
+	assert.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test-ns", Name: "test-capp-00001"}, podAutoscaler))
+	assert.Equal(t, "3", podAutoscaler.Annotations[autoscaling.MinScaleAnnotationKey])
+
+	assert.NoError(t, manager.syncWarmRevision("test-ns", warm, &cappv1alpha1.RolloutStatus{StableRevision: "test-capp-00002"}))
+	assert.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test-ns", Name: "test-capp-00001"}, podAutoscaler))
+	assert.Equal(t, "1", podAutoscaler.Annotations[autoscaling.MinScaleAnnotationKey])
+}
+
+func TestProgressSavesStatus(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = knativev1.AddToScheme(s)
+	_ = autoscalingv1alpha1.AddToScheme(s)
+	capp := newBlueGreenCapp()
+	knativeService := &knativev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	knativeService.Status.LatestReadyRevisionName = "test-capp-00002"
+	recorder := record.NewFakeRecorder(1)
+
+	// No event is emitted if the status can not be saved.
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(knativeService).Build()
+	manager := Manager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: recorder}
+	_, _, err := manager.Progress(capp)
+	assert.Error(t, err)
+	assert.Empty(t, recorder.Events)
+
+	k8sClient = fake.NewClientBuilder().WithScheme(s).WithObjects(&capp, knativeService).WithStatusSubresource(&capp).Build()
+	manager.K8sclient = k8sClient
+	assert.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test-ns", Name: "test-capp"}, &capp))
+	status, _, err := manager.Progress(capp)
+	assert.NoError(t, err)
+	assert.Equal(t, cappv1alpha1.RolloutPhaseAwaitingPromotion, status.Phase)
+	assert.Contains(t, <-recorder.Events, eventRolloutPreviewReady)
+
+	savedCapp := cappv1alpha1.Capp{}
+	assert.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test-ns", Name: "test-capp"}, &savedCapp))
+	assert.Equal(t, status, savedCapp.Status.RolloutStatus)
+}
@@ -0,0 +1,334 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CanaryTag is the Knative traffic tag of the revision being rolled out, which makes it reachable on its own URL.
+	CanaryTag = "canary"
+
+	defaultStepIntervalSeconds = 60
+	enabledState               = "enabled"
+
+	eventRolloutStarted    = "RolloutStarted"
+	eventRolloutStepPassed = "RolloutStepPassed"
+	eventRolloutSucceeded  = "RolloutSucceeded"
+	eventRolloutRolledBack = "RolloutRolledBack"
+)
+
+// Manager progresses the rollout of new revisions of a Capp.
+type Manager struct {
+	Ctx           context.Context
+	K8sclient     client.Client
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+	Analyzer      *Analyzer
+}
+
+// IsEnabled returns a boolean indicating whether new revisions of the Capp are rolled out by the operator.
+func IsEnabled(capp cappv1alpha1.Capp) bool {
+	return capp.Spec.RolloutSpec != nil && capp.Spec.State == enabledState
+}
+
+// Traffic returns the traffic block of the Knative Service of a Capp according to the state of its rollout.
+// It returns nil if no stable revision is known yet, in which case all traffic goes to the latest ready revision.
+// Revisions awaiting promotion or kept warm get no traffic but are tagged, which keeps them routable.
+func Traffic(capp cappv1alpha1.Capp) []knativev1.TrafficTarget {
+	status := capp.Status.RolloutStatus
+	if status == nil || status.StableRevision == "" {
+		return nil
+	}
+
+	var canaryPercent int64
+	if status.Phase == cappv1alpha1.RolloutPhaseProgressing && status.CanaryRevision != "" {
+		canaryPercent = status.CanaryPercent
+	}
+
+	traffic := []knativev1.TrafficTarget{newTrafficTarget(status.StableRevision, 100-canaryPercent, "")}
+	if canaryPercent > 0 {
+		traffic = append(traffic, newTrafficTarget(status.CanaryRevision, canaryPercent, CanaryTag))
+	}
+	if status.PreviewRevision != "" {
+		traffic = append(traffic, newTrafficTarget(status.PreviewRevision, 0, PreviewTag))
+	}
+	if status.PreviousRevision != "" {
+		traffic = append(traffic, newTrafficTarget(status.PreviousRevision, 0, PreviousTag))
+	}
+
+	return traffic
+}
+
+// newTrafficTarget returns a traffic target which sends the given percentage of traffic to a revision.
+func newTrafficTarget(revisionName string, percent int64, tag string) knativev1.TrafficTarget {
+	return knativev1.TrafficTarget{
+		RevisionName:   revisionName,
+		Percent:        &percent,
+		Tag:            tag,
+		LatestRevision: new(bool),
+	}
+}
+
+// Progress returns the next state of the rollout of a Capp, and the duration after which it should be progressed
+// again, or zero if the rollout is not waiting for its next step. It saves the state in the status of the Capp
+// before returning it, so that a transition is kept even if reconciling the rest of the Capp fails. It emits an
+// event on every transition of the rollout once the transition is saved, and keeps the previous revision of a
+// blue/green rollout warm.
+func (m Manager) Progress(capp cappv1alpha1.Capp) (*cappv1alpha1.RolloutStatus, time.Duration, error) {
+	recorder := m.EventRecorder
+	events := &pendingEvents{}
+	m.EventRecorder = events
+
+	status, requeueAfter, err := m.nextStatus(capp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.saveStatus(capp, status); err != nil {
+		return nil, 0, err
+	}
+
+	events.flush(recorder)
+	return status, requeueAfter, nil
+}
+
+// nextStatus returns the next state of the rollout of a Capp and the duration after which it should be progressed.
+func (m Manager) nextStatus(capp cappv1alpha1.Capp) (*cappv1alpha1.RolloutStatus, time.Duration, error) {
+	if !IsEnabled(capp) {
+		if m.Analyzer != nil {
+			m.Analyzer.Forget(types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name})
+		}
+		return nil, 0, m.syncWarmRevision(capp.Namespace, capp.Status.RolloutStatus, nil)
+	}
+
+	knativeService := knativev1.Service{}
+	if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}, &knativeService); err != nil {
+		if errors.IsNotFound(err) {
+			return capp.Status.RolloutStatus, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to get KnativeService %q: %w", capp.Name, err)
+	}
+
+	latestReadyRevision := knativeService.Status.LatestReadyRevisionName
+	var status *cappv1alpha1.RolloutStatus
+	var requeueAfter time.Duration
+	if capp.Spec.RolloutSpec.Strategy == cappv1alpha1.RolloutStrategyBlueGreen {
+		promotedRevision, err := m.getPromotedRevision(capp)
+		if err != nil {
+			return nil, 0, err
+		}
+		status, requeueAfter = m.progressBlueGreen(capp, latestReadyRevision, promotedRevision, time.Now())
+	} else {
+		status, requeueAfter = m.progress(capp, latestReadyRevision, time.Now())
+	}
+
+	if err := m.syncWarmRevision(capp.Namespace, capp.Status.RolloutStatus, status); err != nil {
+		return nil, 0, err
+	}
+
+	return status, requeueAfter, nil
+}
+
+// pendingEvents is an EventRecorder which holds events until they are flushed to another EventRecorder.
+type pendingEvents struct {
+	events []pendingEvent
+}
+
+// pendingEvent is an event held by pendingEvents.
+type pendingEvent struct {
+	object    runtime.Object
+	eventType string
+	reason    string
+	message   string
+}
+
+// Event holds an event.
+func (p *pendingEvents) Event(object runtime.Object, eventType, reason, message string) {
+	p.events = append(p.events, pendingEvent{object: object, eventType: eventType, reason: reason, message: message})
+}
+
+// Eventf holds an event with a formatted message.
+func (p *pendingEvents) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	p.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf holds an event with a formatted message. The annotations are dropped.
+func (p *pendingEvents) AnnotatedEventf(object runtime.Object, _ map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	p.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+// flush emits the held events to a recorder.
+func (p *pendingEvents) flush(recorder record.EventRecorder) {
+	for _, event := range p.events {
+		recorder.Event(event.object, event.eventType, event.reason, event.message)
+	}
+	p.events = nil
+}
+
+// saveStatus patches the rollout status of a Capp if it differs from the given status.
+func (m Manager) saveStatus(capp cappv1alpha1.Capp, status *cappv1alpha1.RolloutStatus) error {
+	if equality.Semantic.DeepEqual(capp.Status.RolloutStatus, status) {
+		return nil
+	}
+
+	updatedCapp := capp.DeepCopy()
+	updatedCapp.Status.RolloutStatus = status
+	if err := m.K8sclient.Status().Patch(m.Ctx, updatedCapp, client.MergeFrom(&capp)); err != nil {
+		return fmt.Errorf("failed to patch rollout status of Capp %q: %w", capp.Name, err)
+	}
+
+	return nil
+}
+
+// progress returns the next state of the rollout of a Capp given the latest ready revision of its Knative Service.
+func (m Manager) progress(capp cappv1alpha1.Capp, latestReadyRevision string, now time.Time) (*cappv1alpha1.RolloutStatus, time.Duration) {
+	canary := capp.Spec.RolloutSpec.Canary
+	stepInterval := time.Duration(defaultStepIntervalSeconds) * time.Second
+	if canary.StepIntervalSeconds > 0 {
+		stepInterval = time.Duration(canary.StepIntervalSeconds) * time.Second
+	}
+
+	status := &cappv1alpha1.RolloutStatus{}
+	if capp.Status.RolloutStatus != nil {
+		status = capp.Status.RolloutStatus.DeepCopy()
+	}
+	status.PreviewRevision = ""
+	status.PreviousRevision = ""
+
+	if status.StableRevision == "" {
+		if latestReadyRevision != "" {
+			status.StableRevision = latestReadyRevision
+			status.Phase = cappv1alpha1.RolloutPhaseSucceeded
+			status.Message = fmt.Sprintf("Revision %q is stable", latestReadyRevision)
+		}
+		return status, 0
+	}
+
+	isNewRevision := latestReadyRevision != "" && latestReadyRevision != status.StableRevision &&
+		latestReadyRevision != status.CanaryRevision && latestReadyRevision != status.FailedRevision
+	if isNewRevision {
+		m.startRollout(&capp, status, latestReadyRevision, now)
+		return status, stepInterval
+	}
+
+	if status.Phase != cappv1alpha1.RolloutPhaseProgressing {
+		return status, 0
+	}
+
+	elapsed := now.Sub(status.LastStepTime.Time)
+	if elapsed < stepInterval {
+		return status, stepInterval - elapsed
+	}
+
+	if canary.Analysis != nil {
+		if status.AnalysisFailures > 0 {
+			if sinceAnalysis := now.Sub(status.LastAnalysisTime.Time); sinceAnalysis < analysisRetryInterval {
+				return status, analysisRetryInterval - sinceAnalysis
+			}
+		}
+
+		parameters := queryParameters{Namespace: capp.Namespace, Capp: capp.Name, Revision: status.CanaryRevision}
+		analysisID := fmt.Sprintf("%s/%d/%d", status.CanaryRevision, status.CurrentStep, status.AnalysisFailures)
+		results, passed, done := m.Analyzer.Analyze(types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name},
+			analysisID, *canary.Analysis, parameters)
+		if !done {
+			status.Message = fmt.Sprintf("Analyzing revision %q at step %d", status.CanaryRevision, status.CurrentStep)
+			return status, analysisPollInterval
+		}
+
+		status.AnalysisResults = results
+		status.LastAnalysisTime = metav1.NewTime(now)
+		if !passed {
+			status.AnalysisFailures++
+			if status.AnalysisFailures > canary.Analysis.FailureLimit {
+				m.rollBack(&capp, status)
+				return status, 0
+			}
+			status.Message = fmt.Sprintf("Analysis of revision %q at step %d failed %d of %d tolerated times",
+				status.CanaryRevision, status.CurrentStep, status.AnalysisFailures, canary.Analysis.FailureLimit)
+			return status, analysisRetryInterval
+		}
+	}
+
+	if status.CurrentStep < len(canary.Steps) {
+		m.advanceStep(&capp, status, canary.Steps, now)
+		return status, stepInterval
+	}
+
+	m.promote(&capp, status)
+	return status, 0
+}
+
+// startRollout starts rolling out a new revision at the first step.
+func (m Manager) startRollout(capp *cappv1alpha1.Capp, status *cappv1alpha1.RolloutStatus, revision string, now time.Time) {
+	status.Phase = cappv1alpha1.RolloutPhaseProgressing
+	status.CanaryRevision = revision
+	status.CurrentStep = 1
+	status.CanaryPercent = capp.Spec.RolloutSpec.Canary.Steps[0]
+	status.LastStepTime = metav1.NewTime(now)
+	status.AnalysisResults = nil
+	status.AnalysisFailures = 0
+	status.Message = fmt.Sprintf("Rolling out revision %q at step 1 with %d%% of traffic", revision, status.CanaryPercent)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeNormal, eventRolloutStarted, status.Message)
+}
+
+// advanceStep moves the rollout to its next step.
+func (m Manager) advanceStep(capp *cappv1alpha1.Capp, status *cappv1alpha1.RolloutStatus, steps []int64, now time.Time) {
+	status.CurrentStep++
+	status.CanaryPercent = steps[status.CurrentStep-1]
+	status.LastStepTime = metav1.NewTime(now)
+	status.AnalysisFailures = 0
+	status.Message = fmt.Sprintf("Rolling out revision %q at step %d with %d%% of traffic",
+		status.CanaryRevision, status.CurrentStep, status.CanaryPercent)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeNormal, eventRolloutStepPassed, status.Message)
+}
+
+// promote makes the revision being rolled out the stable revision.
+func (m Manager) promote(capp *cappv1alpha1.Capp, status *cappv1alpha1.RolloutStatus) {
+	status.Phase = cappv1alpha1.RolloutPhaseSucceeded
+	status.StableRevision = status.CanaryRevision
+	status.CanaryRevision = ""
+	status.CurrentStep = 0
+	status.CanaryPercent = 0
+	status.AnalysisFailures = 0
+	status.Message = fmt.Sprintf("Revision %q was rolled out and is stable", status.StableRevision)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeNormal, eventRolloutSucceeded, status.Message)
+}
+
+// rollBack sends all traffic back to the stable revision after the revision being rolled out failed its analysis.
+func (m Manager) rollBack(capp *cappv1alpha1.Capp, status *cappv1alpha1.RolloutStatus) {
+	var failedMetrics []string
+	for _, result := range status.AnalysisResults {
+		if !result.Passed {
+			failedMetrics = append(failedMetrics, fmt.Sprintf("%s (%s)", result.Name, result.Message))
+		}
+	}
+
+	status.Phase = cappv1alpha1.RolloutPhaseRolledBack
+	status.FailedRevision = status.CanaryRevision
+	status.CanaryRevision = ""
+	status.CurrentStep = 0
+	status.CanaryPercent = 0
+	status.AnalysisFailures = 0
+	status.Message = fmt.Sprintf("Revision %q was rolled back to %q at failed analysis of %v",
+		status.FailedRevision, status.StableRevision, failedMetrics)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeWarning, eventRolloutRolledBack, status.Message)
+}
@@ -0,0 +1,214 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const prometheusAddress = "http://prometheus:9090"
+
+// fakeMetricsProvider returns the value of a query from a map, and no data for any other query.
+type fakeMetricsProvider struct {
+	values map[string]float64
+}
+
+func (f fakeMetricsProvider) Query(_ context.Context, _, query string) (float64, bool, error) {
+	value, ok := f.values[query]
+	return value, ok, nil
+}
+
+// waitForAnalysis waits until all the analyses started by an Analyzer are done.
+func waitForAnalysis(t *testing.T, analyzer *Analyzer) {
+	assert.Eventually(t, func() bool {
+		analyzer.mu.Lock()
+		defer analyzer.mu.Unlock()
+		for _, run := range analyzer.runs {
+			if !run.done {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func newRolloutCapp() cappv1alpha1.Capp {
+	return cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			State: enabledState,
+			RolloutSpec: &cappv1alpha1.RolloutSpec{
+				Strategy: cappv1alpha1.RolloutStrategyCanary,
+				Canary: &cappv1alpha1.CanarySpec{
+					Steps:               []int64{10, 50},
+					StepIntervalSeconds: 60,
+					Analysis: &cappv1alpha1.AnalysisSpec{
+						Metrics: []cappv1alpha1.AnalysisMetric{
+							{Name: "error-rate", Query: "errors{revision=\"{{ .Revision }}\"}", Max: resource.MustParse("0.05")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newRolloutManager(values map[string]float64) Manager {
+	return Manager{
+		Ctx:           context.Background(),
+		Log:           logr.Discard(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Analyzer:      &Analyzer{PrometheusAddress: prometheusAddress, Provider: fakeMetricsProvider{values: values}},
+	}
+}
+
+func TestProgressPromotesHealthyRevision(t *testing.T) {
+	manager := newRolloutManager(map[string]float64{`errors{revision="test-capp-00002"}`: 0.01})
+	capp := newRolloutCapp()
+	now := time.Now()
+
+	status, requeueAfter := manager.progress(capp, "test-capp-00001", now)
+	assert.Equal(t, cappv1alpha1.RolloutPhaseSucceeded, status.Phase)
+	assert.Equal(t, "test-capp-00001", status.StableRevision)
+	assert.Zero(t, requeueAfter)
+
+	capp.Status.RolloutStatus = status
+	status, requeueAfter = manager.progress(capp, "test-capp-00002", now)
+	assert.Equal(t, cappv1alpha1.RolloutPhaseProgressing, status.Phase)
+	assert.Equal(t, "test-capp-00002", status.CanaryRevision)
+	assert.Equal(t, int64(10), status.CanaryPercent)
+	assert.Equal(t, time.Minute, requeueAfter)
+
+	capp.Status.RolloutStatus = status
+	status, requeueAfter = manager.progress(capp, "test-capp-00002", now.Add(20*time.Second))
+	assert.Equal(t, int64(10), status.CanaryPercent)
+	assert.Equal(t, 40*time.Second, requeueAfter)
+
+	// The analysis runs in the background, and the step advances once it is done.
+	status, requeueAfter = manager.progress(capp, "test-capp-00002", now.Add(time.Minute))
+	assert.Equal(t, 1, status.CurrentStep)
+	assert.Equal(t, analysisPollInterval, requeueAfter)
+	waitForAnalysis(t, manager.Analyzer)
+
+	status, _ = manager.progress(capp, "test-capp-00002", now.Add(time.Minute))
+	assert.Equal(t, 2, status.CurrentStep)
+	assert.Equal(t, int64(50), status.CanaryPercent)
+	assert.True(t, status.AnalysisResults[0].Passed)
+
+	capp.Status.RolloutStatus = status
+	_, _ = manager.progress(capp, "test-capp-00002", now.Add(2*time.Minute))
+	waitForAnalysis(t, manager.Analyzer)
+	status, requeueAfter = manager.progress(capp, "test-capp-00002", now.Add(2*time.Minute))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseSucceeded, status.Phase)
+	assert.Equal(t, "test-capp-00002", status.StableRevision)
+	assert.Empty(t, status.CanaryRevision)
+	assert.Zero(t, requeueAfter)
+}
+
+func TestProgressRollsBackUnhealthyRevision(t *testing.T) {
+	manager := newRolloutManager(map[string]float64{`errors{revision="test-capp-00002"}`: 0.2})
+	capp := newRolloutCapp()
+	now := time.Now()
+	capp.Status.RolloutStatus = &cappv1alpha1.RolloutStatus{
+		Phase:          cappv1alpha1.RolloutPhaseProgressing,
+		StableRevision: "test-capp-00001",
+		CanaryRevision: "test-capp-00002",
+		CurrentStep:    1,
+		CanaryPercent:  10,
+		LastStepTime:   metav1.NewTime(now),
+	}
+
+	_, _ = manager.progress(capp, "test-capp-00002", now.Add(time.Minute))
+	waitForAnalysis(t, manager.Analyzer)
+	status, requeueAfter := manager.progress(capp, "test-capp-00002", now.Add(time.Minute))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseRolledBack, status.Phase)
+	assert.Equal(t, "test-capp-00001", status.StableRevision)
+	assert.Equal(t, "test-capp-00002", status.FailedRevision)
+	assert.Empty(t, status.CanaryRevision)
+	assert.False(t, status.AnalysisResults[0].Passed)
+	assert.Zero(t, requeueAfter)
+
+	capp.Status.RolloutStatus = status
+	status, _ = manager.progress(capp, "test-capp-00002", now.Add(2*time.Minute))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseRolledBack, status.Phase)
+
+	status, _ = manager.progress(capp, "test-capp-00003", now.Add(2*time.Minute))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseProgressing, status.Phase)
+	assert.Equal(t, "test-capp-00003", status.CanaryRevision)
+	assert.Equal(t, 1, status.CurrentStep)
+}
+
+func TestProgressRetriesFailedAnalysis(t *testing.T) {
+	manager := newRolloutManager(map[string]float64{`errors{revision="test-capp-00002"}`: 0.2})
+	capp := newRolloutCapp()
+	capp.Spec.RolloutSpec.Canary.Analysis.FailureLimit = 1
+	now := time.Now()
+	capp.Status.RolloutStatus = &cappv1alpha1.RolloutStatus{
+		Phase:          cappv1alpha1.RolloutPhaseProgressing,
+		StableRevision: "test-capp-00001",
+		CanaryRevision: "test-capp-00002",
+		CurrentStep:    1,
+		CanaryPercent:  10,
+		LastStepTime:   metav1.NewTime(now),
+	}
+
+	_, _ = manager.progress(capp, "test-capp-00002", now.Add(time.Minute))
+	waitForAnalysis(t, manager.Analyzer)
+	status, requeueAfter := manager.progress(capp, "test-capp-00002", now.Add(time.Minute))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseProgressing, status.Phase)
+	assert.Equal(t, int32(1), status.AnalysisFailures)
+	assert.Equal(t, analysisRetryInterval, requeueAfter)
+
+	capp.Status.RolloutStatus = status
+	status, requeueAfter = manager.progress(capp, "test-capp-00002", now.Add(time.Minute+10*time.Second))
+	assert.Equal(t, int32(1), status.AnalysisFailures)
+	assert.Equal(t, analysisRetryInterval-10*time.Second, requeueAfter)
+
+	_, _ = manager.progress(capp, "test-capp-00002", now.Add(time.Minute+analysisRetryInterval))
+	waitForAnalysis(t, manager.Analyzer)
+	status, _ = manager.progress(capp, "test-capp-00002", now.Add(time.Minute+analysisRetryInterval))
+	assert.Equal(t, cappv1alpha1.RolloutPhaseRolledBack, status.Phase)
+	assert.Zero(t, status.AnalysisFailures)
+}
+
+func TestTraffic(t *testing.T) {
+	percent := func(value int64) *int64 { return &value }
+	capp := newRolloutCapp()
+	assert.Nil(t, Traffic(capp))
+
+	capp.Status.RolloutStatus = &cappv1alpha1.RolloutStatus{
+		Phase:          cappv1alpha1.RolloutPhaseProgressing,
+		StableRevision: "test-capp-00001",
+		CanaryRevision: "test-capp-00002",
+		CanaryPercent:  10,
+	}
+	assert.Equal(t, []knativev1.TrafficTarget{
+		{RevisionName: "test-capp-00001", Percent: percent(90), LatestRevision: new(bool)},
+		{RevisionName: "test-capp-00002", Percent: percent(10), Tag: CanaryTag, LatestRevision: new(bool)},
+	}, Traffic(capp))
+
+	capp.Status.RolloutStatus.Phase = cappv1alpha1.RolloutPhaseRolledBack
+	assert.Equal(t, []knativev1.TrafficTarget{
+		{RevisionName: "test-capp-00001", Percent: percent(100), LatestRevision: new(bool)},
+	}, Traffic(capp))
+
+	capp.Status.RolloutStatus = &cappv1alpha1.RolloutStatus{
+		Phase:            cappv1alpha1.RolloutPhaseAwaitingPromotion,
+		StableRevision:   "test-capp-00002",
+		PreviewRevision:  "test-capp-00003",
+		PreviousRevision: "test-capp-00001",
+	}
+	assert.Equal(t, []knativev1.TrafficTarget{
+		{RevisionName: "test-capp-00002", Percent: percent(100), LatestRevision: new(bool)},
+		{RevisionName: "test-capp-00003", Percent: percent(0), Tag: PreviewTag, LatestRevision: new(bool)},
+		{RevisionName: "test-capp-00001", Percent: percent(0), Tag: PreviousTag, LatestRevision: new(bool)},
+	}, Traffic(capp))
+}
@@ -7,6 +7,7 @@ import (
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -22,20 +23,27 @@ func CreateStateStatus(stateStatus *cappv1alpha1.StateStatus, cappStateFromSpec
 }
 
 // SyncStatus is the main function that synchronizes the status of the Capp CRD with the Knative service and revisions associated with it.
-// It gets the Capp CRD, builds the ApplicationLinks and RevisionInfo statuses, and updates the status of the Capp CRD if it has changed.
+// It gets the Capp CRD, builds the ApplicationLinks, RevisionInfo and subsystem statuses and the conditions derived from them,
+// and patches the status of the Capp CRD only if it has changed.
 func SyncStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r client.Client, onOpenshift bool, resourceManagers map[string]rmanagers.ResourceManager) error {
 	cappObject := cappv1alpha1.Capp{}
 	if err := r.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}, &cappObject); err != nil {
 		return err
 	}
 
+	originalCappObject := cappObject.DeepCopy()
+
+	isRequired := map[string]bool{}
+	for name, manager := range resourceManagers {
+		isRequired[name] = manager.IsRequired(capp)
+	}
+
 	applicationLinks, err := buildApplicationLinks(ctx, log, r, onOpenshift)
 	if err != nil {
 		return err
 	}
 
-	knativeServiceManager := resourceManagers[rmanagers.KnativeServing]
-	knativeObjectStatus, revisionInfo, err := buildKnativeStatus(ctx, r, capp, knativeServiceManager.IsRequired(capp))
+	knativeObjectStatus, revisionInfo, err := buildKnativeStatus(ctx, r, capp, isRequired[rmanagers.KnativeServing])
 	if err != nil {
 		return err
 	}
@@ -43,38 +51,43 @@ func SyncStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r
 	cappObject.Status.KnativeObjectStatus = knativeObjectStatus
 	cappObject.Status.RevisionInfo = revisionInfo
 
-	syslogNGFlowManager := resourceManagers[rmanagers.SyslogNGFlow]
-	loggingStatus, err := buildLoggingStatus(ctx, capp, log, r, syslogNGFlowManager.IsRequired(capp))
+	loggingStatus, err := buildLoggingStatus(ctx, cappObject, log, r, isRequired[rmanagers.SyslogNGFlow])
 	if err != nil {
 		return err
 	}
 	cappObject.Status.LoggingStatus = loggingStatus
 
-	routeRequired := map[string]bool{
-		rmanagers.DomainMapping: resourceManagers[rmanagers.DomainMapping].IsRequired(capp),
-		rmanagers.DNSRecord:     resourceManagers[rmanagers.DNSRecord].IsRequired(capp),
-		rmanagers.Certificate:   resourceManagers[rmanagers.Certificate].IsRequired(capp),
-	}
-	routeStatus, err := buildRouteStatus(ctx, r, capp, routeRequired)
+	routeStatus, err := buildRouteStatus(ctx, r, capp, isRequired)
 	if err != nil {
 		return err
 	}
 	cappObject.Status.RouteStatus = routeStatus
 
-	nfspvcManager := resourceManagers[rmanagers.NfsPVC]
-	volumesStatus, err := buildVolumesStatus(ctx, r, capp, nfspvcManager.IsRequired(capp))
+	volumesStatus, err := buildVolumesStatus(ctx, r, capp, isRequired[rmanagers.NfsPVC])
 	if err != nil {
 		return err
 	}
 	cappObject.Status.VolumesStatus = volumesStatus
+	cappObject.Status.RolloutStatus = capp.Status.RolloutStatus
+
+	if err := buildURLStatus(ctx, r, capp, &cappObject.Status, isRequired); err != nil {
+		return err
+	}
+
+	buildConditions(&cappObject.Status, isRequired, capp.Generation)
 
 	CreateStateStatus(&cappObject.Status.StateStatus, capp.Spec.State)
 	cappObject.Status.KnativeObjectStatus = knativeObjectStatus
 	cappObject.Status.RevisionInfo = revisionInfo
 	cappObject.Status.ApplicationLinks = *applicationLinks
+	cappObject.Status.ObservedGeneration = capp.Generation
+
+	if equality.Semantic.DeepEqual(originalCappObject.Status, cappObject.Status) {
+		return nil
+	}
 
-	if err := r.Status().Update(ctx, &cappObject); err != nil {
-		log.Error(err, "failed to update Capp status")
+	if err := r.Status().Patch(ctx, &cappObject, client.MergeFrom(originalCappObject)); err != nil {
+		log.Error(err, "failed to patch Capp status")
 		return err
 	}
 
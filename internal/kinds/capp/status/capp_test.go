@@ -0,0 +1,61 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// notRequiredManager is a ResourceManager whose resources are never required.
+type notRequiredManager struct{}
+
+func (notRequiredManager) Manage(cappv1alpha1.Capp) error    { return nil }
+func (notRequiredManager) CleanUp(cappv1alpha1.Capp) error   { return nil }
+func (notRequiredManager) IsRequired(cappv1alpha1.Capp) bool { return false }
+
+func TestSyncStatusOnlyPatchesChanges(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+
+	capp := &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns", Generation: 3},
+		Spec:       cappv1alpha1.CappSpec{State: "enabled"},
+	}
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": "capp-zone.com."},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(capp, dnsConfig).WithStatusSubresource(capp).Build()
+
+	resourceManagers := map[string]rmanagers.ResourceManager{}
+	for _, name := range []string{rmanagers.KnativeServing, rmanagers.DNSRecord, rmanagers.Certificate, rmanagers.DomainMapping,
+		rmanagers.SyslogNGFlow, rmanagers.SyslogNGOutput, rmanagers.NfsPVC} {
+		resourceManagers[name] = notRequiredManager{}
+	}
+
+	assert.NoError(t, SyncStatus(ctx, *capp, logr.Discard(), k8sClient, false, resourceManagers))
+
+	syncedCapp := &cappv1alpha1.Capp{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(capp), syncedCapp))
+	assert.Equal(t, int64(3), syncedCapp.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionTrue(syncedCapp.Status.Conditions, cappv1alpha1.ConditionTypeReady))
+
+	assert.NoError(t, SyncStatus(ctx, *syncedCapp, logr.Discard(), k8sClient, false, resourceManagers))
+
+	resyncedCapp := &cappv1alpha1.Capp{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(capp), resyncedCapp))
+	assert.Equal(t, syncedCapp.ResourceVersion, resyncedCapp.ResourceVersion)
+}
@@ -0,0 +1,369 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	reasonReady             = "Ready"
+	reasonNotReady          = "NotReady"
+	reasonPending           = "Pending"
+	reasonAllReady          = "AllSubsystemsReady"
+	reasonSubsystemNotReady = "SubsystemNotReady"
+	reasonSubsystemPending  = "SubsystemPending"
+	reasonHostnameConflict  = "HostnameConflict"
+	reasonHostnameClaimed   = "HostnameClaimed"
+	reasonNoConflict        = "NoConflict"
+	reasonZoneNotAllowed    = "ZoneNotAllowed"
+	reasonAllZonesAllowed   = "AllZonesAllowed"
+	pvcPhaseBound           = "Bound"
+)
+
+// subsystemConditionTypes are the condition types which are aggregated into the Ready condition, in order.
+var subsystemConditionTypes = []string{
+	cappv1alpha1.ConditionTypeKnativeServiceReady,
+	cappv1alpha1.ConditionTypeDomainMappingReady,
+	cappv1alpha1.ConditionTypeHTTPRouteReady,
+	cappv1alpha1.ConditionTypeRouteAdmitted,
+	cappv1alpha1.ConditionTypeDNSRecordReady,
+	cappv1alpha1.ConditionTypeCertificateReady,
+	cappv1alpha1.ConditionTypeVolumesReady,
+	cappv1alpha1.ConditionTypeLoggingReady,
+}
+
+// routeConditionTypes are the condition types of the objects of the hostname of a Capp.
+var routeConditionTypes = []string{
+	cappv1alpha1.ConditionTypeDomainMappingReady,
+	cappv1alpha1.ConditionTypeHTTPRouteReady,
+	cappv1alpha1.ConditionTypeRouteAdmitted,
+	cappv1alpha1.ConditionTypeDNSRecordReady,
+	cappv1alpha1.ConditionTypeCertificateReady,
+}
+
+// buildConditions sets the subsystem conditions of the Capp from the statuses of its child objects,
+// which must already be built, and the aggregated Ready condition. Conditions of subsystems which
+// are not required by the Capp are removed. The transition time of a condition only changes with its status.
+func buildConditions(cappStatus *cappv1alpha1.CappStatus, isRequired map[string]bool, generation int64) {
+	conditions := map[string]*metav1.Condition{}
+
+	if isRequired[rmanagers.KnativeServing] {
+		conditions[cappv1alpha1.ConditionTypeKnativeServiceReady] = knativeCondition(cappv1alpha1.ConditionTypeKnativeServiceReady,
+			cappStatus.KnativeObjectStatus.GetCondition(apis.ConditionReady))
+	}
+	if isRequired[rmanagers.DomainMapping] {
+		conditions[cappv1alpha1.ConditionTypeDomainMappingReady] = knativeCondition(cappv1alpha1.ConditionTypeDomainMappingReady,
+			cappStatus.RouteStatus.DomainMappingObjectStatus.GetCondition(apis.ConditionReady))
+	}
+	if isRequired[rmanagers.HTTPRoute] {
+		conditions[cappv1alpha1.ConditionTypeHTTPRouteReady] = HTTPRouteCondition(cappStatus.RouteStatus.HTTPRouteObjectStatus)
+	}
+	if isRequired[rmanagers.OpenShiftRoute] {
+		conditions[cappv1alpha1.ConditionTypeRouteAdmitted] = openShiftRouteCondition(cappStatus.RouteStatus.OpenShiftRouteObjectStatus)
+	}
+	if isRequired[rmanagers.DNSRecord] {
+		conditions[cappv1alpha1.ConditionTypeDNSRecordReady] = DNSRecordCondition(cappStatus.RouteStatus.DNSRecordObjectStatus)
+	}
+	if isRequired[rmanagers.Certificate] {
+		conditions[cappv1alpha1.ConditionTypeCertificateReady] = CertificateCondition(cappStatus.RouteStatus.CertificateObjectStatus)
+	}
+	if isRequired[rmanagers.NfsPVC] {
+		conditions[cappv1alpha1.ConditionTypeVolumesReady] = volumesCondition(cappStatus.VolumesStatus)
+	}
+	if isRequired[rmanagers.SyslogNGFlow] {
+		conditions[cappv1alpha1.ConditionTypeLoggingReady] = loggingCondition(cappStatus.LoggingStatus)
+	}
+
+	if hostnames := cappStatus.RouteStatus.Hostnames; len(hostnames) > 0 && (hostnames[0].NotAllowed || hostnames[0].ClaimedBy != "") {
+		for _, conditionType := range routeConditionTypes {
+			if conditions[conditionType] == nil {
+				continue
+			}
+			if hostnames[0].NotAllowed {
+				conditions[conditionType] = HostnameNotAllowedRouteCondition(conditionType, hostnames[0].Hostname)
+			} else {
+				conditions[conditionType] = HostnameConflictRouteCondition(conditionType, hostnames[0].Hostname, hostnames[0].ClaimedBy)
+			}
+		}
+	}
+
+	for _, conditionType := range subsystemConditionTypes {
+		condition := conditions[conditionType]
+		if condition == nil {
+			meta.RemoveStatusCondition(&cappStatus.Conditions, conditionType)
+			continue
+		}
+		condition.ObservedGeneration = generation
+		meta.SetStatusCondition(&cappStatus.Conditions, *condition)
+	}
+
+	if isRequired[rmanagers.DNSRecord] {
+		hostnameConflict := HostnameConflictCondition(cappStatus.RouteStatus.Hostnames)
+		hostnameConflict.ObservedGeneration = generation
+		meta.SetStatusCondition(&cappStatus.Conditions, hostnameConflict)
+	} else {
+		meta.RemoveStatusCondition(&cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameConflict)
+	}
+
+	if len(cappStatus.RouteStatus.Hostnames) > 0 {
+		hostnameNotAllowed := HostnameNotAllowedCondition(cappStatus.RouteStatus.Hostnames)
+		hostnameNotAllowed.ObservedGeneration = generation
+		meta.SetStatusCondition(&cappStatus.Conditions, hostnameNotAllowed)
+	} else {
+		meta.RemoveStatusCondition(&cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameNotAllowed)
+	}
+
+	ready := ReadyCondition(cappStatus.Conditions, subsystemConditionTypes)
+	ready.ObservedGeneration = generation
+	meta.SetStatusCondition(&cappStatus.Conditions, ready)
+}
+
+// HostnameConflictCondition sets the HostnameConflict condition according to whether any of the hostnames
+// was claimed by another Capp or CappRouter. Unlike the subsystem conditions, it is true when there is a problem.
+func HostnameConflictCondition(hostnamesStatus []cappv1alpha1.HostnameStatus) metav1.Condition {
+	var conflicts []string
+	for _, hostnameStatus := range hostnamesStatus {
+		if hostnameStatus.ClaimedBy != "" {
+			conflicts = append(conflicts, fmt.Sprintf("%s is claimed by %s", hostnameStatus.Hostname, hostnameStatus.ClaimedBy))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return newCondition(cappv1alpha1.ConditionTypeHostnameConflict, metav1.ConditionTrue, reasonHostnameClaimed, strings.Join(conflicts, "; "))
+	}
+
+	return newCondition(cappv1alpha1.ConditionTypeHostnameConflict, metav1.ConditionFalse, reasonNoConflict, "")
+}
+
+// HostnameNotAllowedCondition sets the HostnameNotAllowed condition according to whether the namespace of the Capp
+// may not use the zone of any of its hostnames. Unlike the subsystem conditions, it is true when there is a problem.
+func HostnameNotAllowedCondition(hostnamesStatus []cappv1alpha1.HostnameStatus) metav1.Condition {
+	var notAllowed []string
+	for _, hostnameStatus := range hostnamesStatus {
+		if hostnameStatus.NotAllowed {
+			notAllowed = append(notAllowed, hostnameStatus.Hostname)
+		}
+	}
+
+	if len(notAllowed) > 0 {
+		return newCondition(cappv1alpha1.ConditionTypeHostnameNotAllowed, metav1.ConditionTrue, reasonZoneNotAllowed,
+			fmt.Sprintf("the namespace is not allowed to use the zone of %s", strings.Join(notAllowed, ", ")))
+	}
+
+	return newCondition(cappv1alpha1.ConditionTypeHostnameNotAllowed, metav1.ConditionFalse, reasonAllZonesAllowed, "")
+}
+
+// HostnameNotAllowedRouteCondition returns the condition of the given type of a hostname in a zone which the
+// namespace of the Capp or CappRouter may not use, whose objects are not managed.
+func HostnameNotAllowedRouteCondition(conditionType, hostname string) *metav1.Condition {
+	condition := newCondition(conditionType, metav1.ConditionFalse, reasonZoneNotAllowed,
+		fmt.Sprintf("the namespace is not allowed to use the zone of hostname %s", hostname))
+	return &condition
+}
+
+// HostnameConflictRouteCondition returns the condition of the given type of a hostname which is owned by
+// another Capp, CappRouter or cluster, whose objects are not managed.
+func HostnameConflictRouteCondition(conditionType, hostname, claimedBy string) *metav1.Condition {
+	condition := newCondition(conditionType, metav1.ConditionFalse, reasonHostnameConflict,
+		fmt.Sprintf("hostname %s is claimed by %s", hostname, claimedBy))
+	return &condition
+}
+
+// ReadyCondition aggregates the subsystem conditions of the given types into the Ready condition. It is false
+// if any subsystem is not ready, unknown if any subsystem is still pending, and true otherwise.
+func ReadyCondition(conditions []metav1.Condition, conditionTypes []string) metav1.Condition {
+	var notReady, pending []string
+
+	for _, conditionType := range conditionTypes {
+		condition := meta.FindStatusCondition(conditions, conditionType)
+		if condition == nil {
+			continue
+		}
+
+		message := conditionType
+		if condition.Message != "" {
+			message = fmt.Sprintf("%s: %s", conditionType, condition.Message)
+		}
+
+		switch condition.Status {
+		case metav1.ConditionFalse:
+			notReady = append(notReady, message)
+		case metav1.ConditionUnknown:
+			pending = append(pending, message)
+		}
+	}
+
+	switch {
+	case len(notReady) > 0:
+		return newCondition(cappv1alpha1.ConditionTypeReady, metav1.ConditionFalse, reasonSubsystemNotReady, strings.Join(notReady, "; "))
+	case len(pending) > 0:
+		return newCondition(cappv1alpha1.ConditionTypeReady, metav1.ConditionUnknown, reasonSubsystemPending, strings.Join(pending, "; "))
+	default:
+		return newCondition(cappv1alpha1.ConditionTypeReady, metav1.ConditionTrue, reasonAllReady, "all required subsystems are ready")
+	}
+}
+
+// knativeCondition converts the Ready condition of a Knative object to a Capp condition of the given type.
+func knativeCondition(conditionType string, knativeReady *apis.Condition) *metav1.Condition {
+	if knativeReady == nil {
+		condition := newCondition(conditionType, metav1.ConditionUnknown, reasonPending, "waiting for the Ready condition to be reported")
+		return &condition
+	}
+
+	condition := newCondition(conditionType, metav1.ConditionStatus(knativeReady.Status), conditionReason(knativeReady.Reason, knativeReady.Status), knativeReady.Message)
+	return &condition
+}
+
+// HTTPRouteCondition sets the HTTPRouteReady condition according to whether the HTTPRoute was accepted by its
+// Gateway and all of its backends were resolved.
+func HTTPRouteCondition(httpRouteStatus gatewayv1.HTTPRouteStatus) *metav1.Condition {
+	if len(httpRouteStatus.Parents) == 0 {
+		condition := newCondition(cappv1alpha1.ConditionTypeHTTPRouteReady, metav1.ConditionUnknown, reasonPending, "waiting for the Gateway to accept the HTTPRoute")
+		return &condition
+	}
+
+	for _, parent := range httpRouteStatus.Parents {
+		for _, conditionType := range []gatewayv1.RouteConditionType{gatewayv1.RouteConditionAccepted, gatewayv1.RouteConditionResolvedRefs} {
+			routeCondition := meta.FindStatusCondition(parent.Conditions, string(conditionType))
+			if routeCondition == nil {
+				condition := newCondition(cappv1alpha1.ConditionTypeHTTPRouteReady, metav1.ConditionUnknown, reasonPending,
+					fmt.Sprintf("waiting for the %s condition to be reported", conditionType))
+				return &condition
+			}
+			if routeCondition.Status != metav1.ConditionTrue {
+				condition := newCondition(cappv1alpha1.ConditionTypeHTTPRouteReady, routeCondition.Status, routeCondition.Reason, routeCondition.Message)
+				return &condition
+			}
+		}
+	}
+
+	condition := newCondition(cappv1alpha1.ConditionTypeHTTPRouteReady, metav1.ConditionTrue, reasonReady, "")
+	return &condition
+}
+
+// openShiftRouteCondition sets the RouteAdmitted condition according to whether the OpenShift Route was
+// admitted by every router which reported on it.
+func openShiftRouteCondition(routeStatus routev1.RouteStatus) *metav1.Condition {
+	if len(routeStatus.Ingress) == 0 {
+		condition := newCondition(cappv1alpha1.ConditionTypeRouteAdmitted, metav1.ConditionUnknown, reasonPending, "waiting for the router to admit the Route")
+		return &condition
+	}
+
+	for _, ingress := range routeStatus.Ingress {
+		for _, ingressCondition := range ingress.Conditions {
+			if ingressCondition.Type != routev1.RouteAdmitted {
+				continue
+			}
+
+			if ingressCondition.Status != corev1.ConditionTrue {
+				condition := newCondition(cappv1alpha1.ConditionTypeRouteAdmitted, metav1.ConditionStatus(ingressCondition.Status),
+					conditionReason(ingressCondition.Reason, ingressCondition.Status), ingressCondition.Message)
+				return &condition
+			}
+		}
+	}
+
+	condition := newCondition(cappv1alpha1.ConditionTypeRouteAdmitted, metav1.ConditionTrue, reasonReady, "")
+	return &condition
+}
+
+// DNSRecordCondition converts the Ready condition of the DNS records to the DNSRecordReady condition,
+// in the same way for every DNS backend.
+func DNSRecordCondition(dnsRecordStatus cappv1alpha1.DNSRecordObjectStatus) *metav1.Condition {
+	xpReady := rmanagers.DNSRecordReadyCondition(dnsRecordStatus)
+
+	condition := newCondition(cappv1alpha1.ConditionTypeDNSRecordReady, metav1.ConditionStatus(xpReady.Status),
+		conditionReason(string(xpReady.Reason), xpReady.Status), xpReady.Message)
+	return &condition
+}
+
+// CertificateCondition converts the Ready condition of the Certificate to the CertificateReady condition.
+func CertificateCondition(certificateStatus cmapi.CertificateStatus) *metav1.Condition {
+	for _, certificateCondition := range certificateStatus.Conditions {
+		if certificateCondition.Type != cmapi.CertificateConditionReady {
+			continue
+		}
+
+		status := corev1.ConditionStatus(certificateCondition.Status)
+		condition := newCondition(cappv1alpha1.ConditionTypeCertificateReady, metav1.ConditionStatus(status),
+			conditionReason(certificateCondition.Reason, status), certificateCondition.Message)
+		return &condition
+	}
+
+	condition := newCondition(cappv1alpha1.ConditionTypeCertificateReady, metav1.ConditionUnknown, reasonPending, "waiting for the Ready condition to be reported")
+	return &condition
+}
+
+// volumesCondition sets the VolumesReady condition according to whether the PVCs of all the NFS volumes are bound.
+func volumesCondition(volumesStatus cappv1alpha1.VolumesStatus) *metav1.Condition {
+	var unbound []string
+	for _, nfsVolumeStatus := range volumesStatus.NFSVolumesStatus {
+		if nfsVolumeStatus.NFSPVCStatus.PvcPhase != pvcPhaseBound {
+			unbound = append(unbound, nfsVolumeStatus.VolumeName)
+		}
+	}
+
+	if len(unbound) > 0 {
+		condition := newCondition(cappv1alpha1.ConditionTypeVolumesReady, metav1.ConditionUnknown, reasonPending,
+			fmt.Sprintf("waiting for volumes to be bound: %s", strings.Join(unbound, ", ")))
+		return &condition
+	}
+
+	condition := newCondition(cappv1alpha1.ConditionTypeVolumesReady, metav1.ConditionTrue, reasonReady, "")
+	return &condition
+}
+
+// loggingCondition sets the LoggingReady condition according to the problems reported by the SyslogNGFlow and SyslogNGOutput.
+func loggingCondition(loggingStatus cappv1alpha1.LoggingStatus) *metav1.Condition {
+	flowProblems := loggingStatus.SyslogNGFlow.ProblemsCount
+	outputProblems := loggingStatus.SyslogNGOutput.ProblemsCount
+
+	if flowProblems != 0 || outputProblems != 0 {
+		condition := newCondition(cappv1alpha1.ConditionTypeLoggingReady, metav1.ConditionFalse, loggingResourceInvalid,
+			fmt.Sprintf("SyslogNGFlow has %d problems and SyslogNGOutput has %d problems", flowProblems, outputProblems))
+		return &condition
+	}
+
+	condition := newCondition(cappv1alpha1.ConditionTypeLoggingReady, metav1.ConditionTrue, reasonReady, "")
+	return &condition
+}
+
+// conditionReason returns the given reason, or a default reason matching the status if it is empty.
+func conditionReason(reason string, status corev1.ConditionStatus) string {
+	if reason != "" {
+		return reason
+	}
+
+	switch status {
+	case corev1.ConditionTrue:
+		return reasonReady
+	case corev1.ConditionFalse:
+		return reasonNotReady
+	default:
+		return reasonPending
+	}
+}
+
+// newCondition returns a condition of the given type, status, reason and message.
+func newCondition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	if status == "" {
+		status = metav1.ConditionUnknown
+	}
+
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
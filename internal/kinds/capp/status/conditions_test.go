@@ -0,0 +1,187 @@
+package status
+
+import (
+	"testing"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func readyKnativeStatus(status corev1.ConditionStatus) duckv1.Status {
+	return duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionReady, Status: status, Reason: "RevisionMissing"}}}
+}
+
+func TestBuildConditions(t *testing.T) {
+	cappStatus := cappv1alpha1.CappStatus{}
+	cappStatus.KnativeObjectStatus.Status = readyKnativeStatus(corev1.ConditionTrue)
+	cappStatus.RouteStatus.CertificateObjectStatus = cmapi.CertificateStatus{
+		Conditions: []cmapi.CertificateCondition{{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionFalse, Reason: "Failed", Message: "issuer not found"}},
+	}
+	isRequired := map[string]bool{rmanagers.KnativeServing: true, rmanagers.Certificate: true, rmanagers.DomainMapping: true}
+
+	buildConditions(&cappStatus, isRequired, 1)
+
+	assert.Equal(t, metav1.ConditionTrue, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeKnativeServiceReady).Status)
+	assert.Equal(t, metav1.ConditionFalse, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeCertificateReady).Status)
+	assert.Equal(t, metav1.ConditionUnknown, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeDomainMappingReady).Status)
+	assert.Nil(t, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeDNSRecordReady))
+
+	ready := meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeReady)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, reasonSubsystemNotReady, ready.Reason)
+	assert.Equal(t, "CertificateReady: issuer not found", ready.Message)
+
+	cappStatus.RouteStatus.CertificateObjectStatus.Conditions[0].Status = cmmeta.ConditionTrue
+	buildConditions(&cappStatus, isRequired, 1)
+
+	ready = meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeReady)
+	assert.Equal(t, metav1.ConditionUnknown, ready.Status)
+	assert.Equal(t, reasonSubsystemPending, ready.Reason)
+
+	buildConditions(&cappStatus, map[string]bool{rmanagers.KnativeServing: true}, 2)
+
+	assert.Nil(t, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeCertificateReady))
+	ready = meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeReady)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, reasonAllReady, ready.Reason)
+	assert.Equal(t, int64(2), ready.ObservedGeneration)
+}
+
+func TestHostnameConflictCondition(t *testing.T) {
+	cappStatus := cappv1alpha1.CappStatus{}
+	cappStatus.RouteStatus.Hostnames = []cappv1alpha1.HostnameStatus{
+		{Hostname: "app.capp-zone.com", ClaimedBy: "Capp other-ns/app"},
+		{Hostname: "legacy.capp-zone.com"},
+	}
+	isRequired := map[string]bool{rmanagers.DNSRecord: true}
+
+	buildConditions(&cappStatus, isRequired, 1)
+
+	hostnameConflict := meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameConflict)
+	assert.Equal(t, metav1.ConditionTrue, hostnameConflict.Status)
+	assert.Equal(t, reasonHostnameClaimed, hostnameConflict.Reason)
+	assert.Equal(t, "app.capp-zone.com is claimed by Capp other-ns/app", hostnameConflict.Message)
+
+	dnsRecordReady := meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeDNSRecordReady)
+	assert.Equal(t, metav1.ConditionFalse, dnsRecordReady.Status)
+	assert.Equal(t, reasonHostnameConflict, dnsRecordReady.Reason)
+	assert.Equal(t, metav1.ConditionFalse, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeReady).Status)
+
+	cappStatus.RouteStatus.Hostnames[0].ClaimedBy = ""
+	buildConditions(&cappStatus, isRequired, 1)
+
+	hostnameConflict = meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameConflict)
+	assert.Equal(t, metav1.ConditionFalse, hostnameConflict.Status)
+	assert.Equal(t, reasonNoConflict, hostnameConflict.Reason)
+
+	buildConditions(&cappStatus, map[string]bool{}, 2)
+	assert.Nil(t, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameConflict))
+}
+
+func TestHostnameNotAllowedCondition(t *testing.T) {
+	cappStatus := cappv1alpha1.CappStatus{}
+	cappStatus.RouteStatus.Hostnames = []cappv1alpha1.HostnameStatus{
+		{Hostname: "app.internal.capp-zone.com", NotAllowed: true},
+		{Hostname: "legacy.capp-zone.com"},
+	}
+	isRequired := map[string]bool{rmanagers.DomainMapping: true, rmanagers.DNSRecord: true}
+
+	buildConditions(&cappStatus, isRequired, 1)
+
+	hostnameNotAllowed := meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameNotAllowed)
+	assert.Equal(t, metav1.ConditionTrue, hostnameNotAllowed.Status)
+	assert.Equal(t, reasonZoneNotAllowed, hostnameNotAllowed.Reason)
+	assert.Equal(t, "the namespace is not allowed to use the zone of app.internal.capp-zone.com", hostnameNotAllowed.Message)
+
+	for _, conditionType := range []string{cappv1alpha1.ConditionTypeDomainMappingReady, cappv1alpha1.ConditionTypeDNSRecordReady} {
+		condition := meta.FindStatusCondition(cappStatus.Conditions, conditionType)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, reasonZoneNotAllowed, condition.Reason)
+	}
+	assert.Equal(t, metav1.ConditionFalse, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeReady).Status)
+
+	cappStatus.RouteStatus.Hostnames[0].NotAllowed = false
+	buildConditions(&cappStatus, isRequired, 1)
+
+	hostnameNotAllowed = meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameNotAllowed)
+	assert.Equal(t, metav1.ConditionFalse, hostnameNotAllowed.Status)
+	assert.Equal(t, reasonAllZonesAllowed, hostnameNotAllowed.Reason)
+
+	cappStatus.RouteStatus.Hostnames = nil
+	buildConditions(&cappStatus, map[string]bool{}, 2)
+	assert.Nil(t, meta.FindStatusCondition(cappStatus.Conditions, cappv1alpha1.ConditionTypeHostnameNotAllowed))
+}
+
+func TestVolumesCondition(t *testing.T) {
+	volumesStatus := cappv1alpha1.VolumesStatus{NFSVolumesStatus: []cappv1alpha1.NFSVolumeStatus{
+		{VolumeName: "data"},
+		{VolumeName: "logs"},
+	}}
+	volumesStatus.NFSVolumesStatus[0].NFSPVCStatus.PvcPhase = pvcPhaseBound
+
+	condition := volumesCondition(volumesStatus)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, "waiting for volumes to be bound: logs", condition.Message)
+
+	volumesStatus.NFSVolumesStatus[1].NFSPVCStatus.PvcPhase = pvcPhaseBound
+	assert.Equal(t, metav1.ConditionTrue, volumesCondition(volumesStatus).Status)
+}
+
+func TestDNSRecordCondition(t *testing.T) {
+	dnsRecordStatus := cappv1alpha1.DNSRecordObjectStatus{Backend: utils.DNSBackendProviderDNS}
+	dnsRecordStatus.CNAMERecordObjectStatus.SetConditions(xpv1.Available())
+	assert.Equal(t, metav1.ConditionTrue, DNSRecordCondition(dnsRecordStatus).Status)
+
+	dnsRecordStatus = cappv1alpha1.DNSRecordObjectStatus{Backend: utils.DNSBackendExternalDNS}
+	dnsRecordStatus.DNSEndpointObjectStatus.Generation = 2
+	dnsRecordStatus.DNSEndpointObjectStatus.ObservedGeneration = 1
+	condition := DNSRecordCondition(dnsRecordStatus)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, reasonPending, condition.Reason)
+
+	dnsRecordStatus.DNSEndpointObjectStatus.ObservedGeneration = 2
+	assert.Equal(t, metav1.ConditionTrue, DNSRecordCondition(dnsRecordStatus).Status)
+}
+
+func TestHTTPRouteCondition(t *testing.T) {
+	httpRouteStatus := gatewayv1.HTTPRouteStatus{}
+	assert.Equal(t, metav1.ConditionUnknown, HTTPRouteCondition(httpRouteStatus).Status)
+
+	httpRouteStatus.Parents = []gatewayv1.RouteParentStatus{{Conditions: []metav1.Condition{
+		{Type: string(gatewayv1.RouteConditionAccepted), Status: metav1.ConditionTrue},
+		{Type: string(gatewayv1.RouteConditionResolvedRefs), Status: metav1.ConditionFalse, Reason: "RefNotPermitted", Message: "backend not permitted"},
+	}}}
+	condition := HTTPRouteCondition(httpRouteStatus)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "RefNotPermitted", condition.Reason)
+
+	httpRouteStatus.Parents[0].Conditions[1].Status = metav1.ConditionTrue
+	assert.Equal(t, metav1.ConditionTrue, HTTPRouteCondition(httpRouteStatus).Status)
+}
+
+func TestOpenShiftRouteCondition(t *testing.T) {
+	routeStatus := routev1.RouteStatus{}
+	assert.Equal(t, metav1.ConditionUnknown, openShiftRouteCondition(routeStatus).Status)
+
+	routeStatus.Ingress = []routev1.RouteIngress{{Conditions: []routev1.RouteIngressCondition{
+		{Type: routev1.RouteAdmitted, Status: corev1.ConditionFalse, Reason: "HostAlreadyClaimed"},
+	}}}
+	condition := openShiftRouteCondition(routeStatus)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "HostAlreadyClaimed", condition.Reason)
+
+	routeStatus.Ingress[0].Conditions[0].Status = corev1.ConditionTrue
+	assert.Equal(t, metav1.ConditionTrue, openShiftRouteCondition(routeStatus).Status)
+}
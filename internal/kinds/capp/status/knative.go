@@ -1,6 +1,8 @@
 package status
 
 import (
+	"sort"
+
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"golang.org/x/net/context"
 	"k8s.io/apimachinery/pkg/labels"
@@ -16,7 +18,7 @@ const (
 )
 
 // This function builds the RevisionInfo status of the Capp CRD by getting the list of revisions associated with the Knative service.
-// It returns a slice of RevisionInfo structs.
+// It returns a slice of RevisionInfo structs sorted by the revision name, so that the status is stable between reconciles.
 func buildRevisionsStatus(ctx context.Context, capp cappv1alpha1.Capp, r client.Client) ([]cappv1alpha1.RevisionInfo, error) {
 	knativeRevisions := knativev1.RevisionList{}
 	var revisionsInfo []cappv1alpha1.RevisionInfo
@@ -42,6 +44,9 @@ func buildRevisionsStatus(ctx context.Context, capp cappv1alpha1.Capp, r client.
 			RevisionStatus: revision.Status,
 		})
 	}
+	sort.Slice(revisionsInfo, func(i, j int) bool {
+		return revisionsInfo[i].RevisionName < revisionsInfo[j].RevisionName
+	})
 
 	return revisionsInfo, nil
 }
@@ -2,7 +2,6 @@ package status
 
 import (
 	"context"
-	"time"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/go-logr/logr"
@@ -20,7 +19,8 @@ const (
 )
 
 // buildLoggingStatus builds the Logging status of the Capp CRD by getting the SyslogNGFlow and SyslogNGOutput objects
-// bundled to the Capp and adding their status. It also creates a condition in accordance with their situation.
+// bundled to the Capp and adding their status. It also creates a condition in accordance with their situation,
+// keeping the transition time of the existing condition if its status did not change.
 func buildLoggingStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r client.Client, isRequired bool) (cappv1alpha1.LoggingStatus, error) {
 	logger := log.WithValues("SyslogNGFlowName", capp.Name, "SyslogNGOutputName", capp.Name)
 	loggingStatus := cappv1alpha1.LoggingStatus{}
@@ -28,6 +28,7 @@ func buildLoggingStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Lo
 	if !isRequired {
 		return loggingStatus, nil
 	}
+	loggingStatus.Conditions = append([]metav1.Condition(nil), capp.Status.LoggingStatus.Conditions...)
 
 	syslogNGFlow := &loggingv1beta1.SyslogNGFlow{}
 	logger.Info("Building logger status")
@@ -57,7 +58,7 @@ func buildLoggingStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Lo
 	condition := metav1.Condition{
 		Type:               loggingReady,
 		Status:             metav1.ConditionStatus(problems),
-		LastTransitionTime: metav1.Time{Time: time.Now()},
+		ObservedGeneration: capp.Generation,
 		Reason:             reason,
 	}
 
@@ -2,17 +2,23 @@ package status
 
 import (
 	"context"
+	"fmt"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
 	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
-	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
 	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // buildRouteStatus constructs the Route Status of the Capp object in accordance to the
@@ -25,42 +31,184 @@ func buildRouteStatus(ctx context.Context, kubeClient client.Client, capp cappv1
 		return routeStatus, err
 	}
 
-	zone, err := utils.GetZoneFromConfig(dnsConfig)
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
 	if err != nil {
 		return routeStatus, err
 	}
 
-	domainMappingStatus, err := buildDomainMappingStatus(ctx, kubeClient, capp, isRequired[rmanagers.DomainMapping], zone)
+	// The objects of a hostname in a zone which the namespace may not use, or which is owned by others,
+	// are not managed, so they are not looked up.
+	hostname := zones.ResourceName(capp.Spec.RouteSpec.Hostname)
+	hostnameManaged := zones.Match(hostname).IsNamespaceAllowed(capp.Namespace)
+	if hostnameManaged && capp.Spec.RouteSpec.Hostname != "" {
+		conflict, err := rmanagers.GetHostnameConflict(ctx, kubeClient, hostname, capp, "")
+		if err != nil {
+			return routeStatus, err
+		}
+		hostnameManaged = conflict == ""
+	}
+
+	domainMappingStatus, err := buildDomainMappingStatus(ctx, kubeClient, capp, isRequired[rmanagers.DomainMapping] && hostnameManaged, zones)
+	if err != nil {
+		return routeStatus, err
+	}
+
+	httpRouteStatus, err := buildHTTPRouteStatus(ctx, kubeClient, capp, isRequired[rmanagers.HTTPRoute] && hostnameManaged, zones)
+	if err != nil {
+		return routeStatus, err
+	}
+
+	openShiftRouteStatus, err := buildOpenShiftRouteStatus(ctx, kubeClient, capp, isRequired[rmanagers.OpenShiftRoute] && hostnameManaged, zones)
+	if err != nil {
+		return routeStatus, err
+	}
+
+	dnsRecordStatus, err := buildDNSRecordStatus(ctx, kubeClient, capp, isRequired[rmanagers.DNSRecord] && hostnameManaged, zones)
 	if err != nil {
 		return routeStatus, err
 	}
 
-	dnsRecordStatus, err := buildDNSRecordStatus(ctx, kubeClient, capp, isRequired[rmanagers.DNSRecord], zone)
+	certificateStatus, err := buildCertificateStatus(ctx, kubeClient, capp, isRequired[rmanagers.Certificate] && hostnameManaged, zones)
 	if err != nil {
 		return routeStatus, err
 	}
 
-	certificateStatus, err := buildCertificateStatus(ctx, kubeClient, capp, isRequired[rmanagers.Certificate], zone)
+	hostnamesStatus, err := buildHostnamesStatus(ctx, kubeClient, capp, isRequired, zones)
 	if err != nil {
 		return routeStatus, err
 	}
 
 	routeStatus.DomainMappingObjectStatus = domainMappingStatus
+	routeStatus.HTTPRouteObjectStatus = httpRouteStatus
+	routeStatus.OpenShiftRouteObjectStatus = openShiftRouteStatus
 	routeStatus.DNSRecordObjectStatus = dnsRecordStatus
 	routeStatus.CertificateObjectStatus = certificateStatus
+	routeStatus.Hostnames = hostnamesStatus
 
 	return routeStatus, nil
 }
 
+// buildHostnamesStatus partly constructs the Route Status of the Capp object in accordance to the
+// status of the DomainMapping, DNSRecord and Certificate objects of every hostname of the Capp.
+// The objects which do not exist yet are reported as not yet known to be ready, the hostnames in zones
+// which the namespace of the Capp may not use are only reported as not allowed, and the hostnames which
+// are owned by others are only reported with their owner and a DNS record which is not ready.
+func buildHostnamesStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired map[string]bool, zones utils.DNSZones) ([]cappv1alpha1.HostnameStatus, error) {
+	if !isRequired[rmanagers.DomainMapping] && !isRequired[rmanagers.HTTPRoute] {
+		return nil, nil
+	}
+
+	scheme := httpScheme
+	if capp.Spec.RouteSpec.TlsEnabled {
+		scheme = httpsScheme
+	}
+
+	var openShiftRoutes map[string]routev1.Route
+	if isRequired[rmanagers.OpenShiftRoute] {
+		var err error
+		if openShiftRoutes, err = getOpenShiftRoutes(ctx, kubeClient, capp); err != nil {
+			return nil, err
+		}
+	}
+
+	var hostnamesStatus []cappv1alpha1.HostnameStatus
+	for _, routeHostname := range rmanagers.GetRouteHostnames(capp, zones) {
+		hostnameStatus := cappv1alpha1.HostnameStatus{
+			Hostname: routeHostname.Hostname,
+			Tag:      routeHostname.Tag,
+			URL:      fmt.Sprintf("%s://%s", scheme, routeHostname.Hostname),
+		}
+
+		if !zones.Match(routeHostname.Hostname).IsNamespaceAllowed(capp.Namespace) {
+			hostnameStatus.NotAllowed = true
+			hostnamesStatus = append(hostnamesStatus, hostnameStatus)
+			continue
+		}
+
+		conflict, err := rmanagers.GetHostnameConflict(ctx, kubeClient, routeHostname.Hostname, capp, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if conflict != "" {
+			hostnameStatus.ClaimedBy = conflict
+			if isRequired[rmanagers.DNSRecord] {
+				hostnameStatus.DNSRecordReady = metav1.ConditionFalse
+			}
+			hostnamesStatus = append(hostnamesStatus, hostnameStatus)
+			continue
+		}
+
+		if isRequired[rmanagers.DomainMapping] {
+			domainMapping := &knativev1beta1.DomainMapping{}
+			if err := getIfExists(ctx, kubeClient, types.NamespacedName{Namespace: capp.Namespace, Name: routeHostname.Hostname}, domainMapping); err != nil {
+				return nil, err
+			}
+			hostnameStatus.DomainMappingReady = knativeCondition(cappv1alpha1.ConditionTypeDomainMappingReady,
+				domainMapping.Status.GetCondition(apis.ConditionReady)).Status
+		}
+
+		if isRequired[rmanagers.HTTPRoute] {
+			httpRoute := &gatewayv1.HTTPRoute{}
+			if err := getIfExists(ctx, kubeClient, types.NamespacedName{Namespace: capp.Namespace, Name: routeHostname.Hostname}, httpRoute); err != nil {
+				return nil, err
+			}
+			hostnameStatus.HTTPRouteReady = HTTPRouteCondition(httpRoute.Status).Status
+		}
+
+		if isRequired[rmanagers.OpenShiftRoute] {
+			hostnameStatus.RouteAdmitted = openShiftRouteCondition(openShiftRoutes[routeHostname.Hostname].Status).Status
+		}
+
+		if isRequired[rmanagers.DNSRecord] {
+			if err := buildHostnameDNSRecordStatus(ctx, kubeClient, capp, &hostnameStatus); err != nil {
+				return nil, err
+			}
+		}
+
+		if isRequired[rmanagers.Certificate] {
+			certificate := &cmapi.Certificate{}
+			if err := getIfExists(ctx, kubeClient, types.NamespacedName{Namespace: capp.Namespace, Name: routeHostname.CertificateName}, certificate); err != nil {
+				return nil, err
+			}
+			hostnameStatus.CertificateReady = CertificateCondition(certificate.Status).Status
+		}
+
+		hostnamesStatus = append(hostnamesStatus, hostnameStatus)
+	}
+
+	return hostnamesStatus, nil
+}
+
+// buildHostnameDNSRecordStatus sets the readiness of the DNS record of a hostname of a Capp.
+func buildHostnameDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, hostnameStatus *cappv1alpha1.HostnameStatus) error {
+	dnsRecordStatus, err := rmanagers.GetDNSRecordStatus(ctx, kubeClient, hostnameStatus.Hostname, capp.Namespace)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	hostnameStatus.DNSRecordReady = DNSRecordCondition(dnsRecordStatus).Status
+
+	return nil
+}
+
+// getIfExists gets an object, leaving it empty if it does not exist.
+func getIfExists(ctx context.Context, kubeClient client.Client, key types.NamespacedName, obj client.Object) error {
+	if err := kubeClient.Get(ctx, key, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
 // buildDomainMappingStatus partly constructs the Route Status of the Capp object in accordance to the
 // status of the corresponding DomainMapping object.
-func buildDomainMappingStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) (knativev1beta1.DomainMappingStatus, error) {
+func buildDomainMappingStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zones utils.DNSZones) (knativev1beta1.DomainMappingStatus, error) {
 	if !isRequired {
 		return knativev1beta1.DomainMappingStatus{}, nil
 	}
 
 	domainMapping := &knativev1beta1.DomainMapping{}
-	domainMappingName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	domainMappingName := zones.ResourceName(capp.Spec.RouteSpec.Hostname)
 	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingName}, domainMapping); err != nil {
 		return knativev1beta1.DomainMappingStatus{}, err
 	}
@@ -68,15 +216,72 @@ func buildDomainMappingStatus(ctx context.Context, kubeClient client.Client, cap
 	return domainMapping.Status, nil
 }
 
+// buildHTTPRouteStatus partly constructs the Route Status of the Capp object in accordance to the
+// status of the corresponding HTTPRoute object.
+func buildHTTPRouteStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zones utils.DNSZones) (gatewayv1.HTTPRouteStatus, error) {
+	if !isRequired {
+		return gatewayv1.HTTPRouteStatus{}, nil
+	}
+
+	httpRoute := &gatewayv1.HTTPRoute{}
+	httpRouteName := zones.ResourceName(capp.Spec.RouteSpec.Hostname)
+	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: httpRouteName}, httpRoute); err != nil {
+		return gatewayv1.HTTPRouteStatus{}, err
+	}
+
+	return httpRoute.Status, nil
+}
+
+// buildOpenShiftRouteStatus partly constructs the Route Status of the Capp object in accordance to the
+// status of the corresponding OpenShift Route object.
+func buildOpenShiftRouteStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zones utils.DNSZones) (routev1.RouteStatus, error) {
+	if !isRequired {
+		return routev1.RouteStatus{}, nil
+	}
+
+	routes, err := getOpenShiftRoutes(ctx, kubeClient, capp)
+	if err != nil {
+		return routev1.RouteStatus{}, err
+	}
+
+	routeName := zones.ResourceName(capp.Spec.RouteSpec.Hostname)
+	route, ok := routes[routeName]
+	if !ok {
+		return routev1.RouteStatus{}, errors.NewNotFound(routev1.Resource("routes"), routeName)
+	}
+
+	return route.Status, nil
+}
+
+// getOpenShiftRoutes returns the OpenShift Routes of a Capp by name. The Routes are in the namespace
+// of the Knative ingress, so they are found by their labels.
+func getOpenShiftRoutes(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp) (map[string]routev1.Route, error) {
+	routeList := routev1.RouteList{}
+	listOptions := utils.GetListOptions(labels.Set{
+		utils.CappResourceKey:  capp.Name,
+		utils.CappNamespaceKey: capp.Namespace,
+	})
+	if err := kubeClient.List(ctx, &routeList, &listOptions); err != nil {
+		return nil, err
+	}
+
+	routes := make(map[string]routev1.Route, len(routeList.Items))
+	for _, route := range routeList.Items {
+		routes[route.Name] = route
+	}
+
+	return routes, nil
+}
+
 // buildCertificateStatus partly constructs the Route Status of the Capp object in accordance to the
 // status of the corresponding Certificate object.
-func buildCertificateStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) (cmapi.CertificateStatus, error) {
+func buildCertificateStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zones utils.DNSZones) (cmapi.CertificateStatus, error) {
 	if !isRequired {
 		return cmapi.CertificateStatus{}, nil
 	}
 
 	certificate := &cmapi.Certificate{}
-	certificateName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	certificateName := zones.ResourceName(capp.Spec.RouteSpec.Hostname)
 
 	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: certificateName}, certificate); err != nil {
 		return cmapi.CertificateStatus{}, err
@@ -86,31 +291,11 @@ func buildCertificateStatus(ctx context.Context, kubeClient client.Client, capp
 }
 
 // buildDNSRecordStatus partly constructs the Route Status of the Capp object in accordance to the
-// status of the corresponding DNSRecord object.
-func buildDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) (cappv1alpha1.DNSRecordObjectStatus, error) {
-	dnsStatus := cappv1alpha1.DNSRecordObjectStatus{}
-	var err error
-
+// status of the corresponding DNSRecord object of the DNS backend in use.
+func buildDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zones utils.DNSZones) (cappv1alpha1.DNSRecordObjectStatus, error) {
 	if !isRequired {
-		return dnsStatus, nil
-	}
-
-	dnsStatus.CNAMERecordObjectStatus, err = buildCNAMERecordStatus(ctx, kubeClient, capp, zone)
-	if err != nil {
-		return dnsStatus, err
-	}
-
-	return dnsStatus, nil
-}
-
-// buildCNAMERecordStatus partly constructs the Route Status of the Capp object in accordance to the
-// status of the corresponding CNAMERecord object.
-func buildCNAMERecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, zone string) (dnsrecordv1alpha1.CNAMERecordStatus, error) {
-	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{}
-	cnameRecordName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
-	if err := kubeClient.Get(ctx, types.NamespacedName{Name: cnameRecordName}, cnameRecord); err != nil {
-		return dnsrecordv1alpha1.CNAMERecordStatus{}, err
+		return cappv1alpha1.DNSRecordObjectStatus{}, nil
 	}
 
-	return cnameRecord.Status, nil
+	return rmanagers.GetDNSRecordStatus(ctx, kubeClient, zones.ResourceName(capp.Spec.RouteSpec.Hostname), capp.Namespace)
 }
@@ -0,0 +1,68 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildHostnamesStatus(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = knativev1beta1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	domainMapping := &knativev1beta1.DomainMapping{ObjectMeta: metav1.ObjectMeta{Name: "app.capp-zone.com", Namespace: "test-ns"}}
+	domainMapping.Status.SetConditions(apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}})
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(domainMapping).Build()
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			RouteSpec: cappv1alpha1.RouteSpec{Hostname: "app", AdditionalHostnames: []string{"legacy"}},
+		},
+	}
+
+	hostnamesStatus, err := buildHostnamesStatus(context.Background(), k8sClient, capp, map[string]bool{rmanagers.DomainMapping: true}, utils.DNSZones{{Name: "capp-zone.com."}})
+	assert.NoError(t, err)
+	assert.Equal(t, []cappv1alpha1.HostnameStatus{
+		{Hostname: "app.capp-zone.com", URL: "http://app.capp-zone.com", DomainMappingReady: metav1.ConditionTrue},
+		{Hostname: "legacy.capp-zone.com", URL: "http://legacy.capp-zone.com", DomainMappingReady: metav1.ConditionUnknown},
+	}, hostnamesStatus)
+
+	// The hostnames in zones which the namespace may not use are only reported as not allowed.
+	zones := utils.DNSZones{{Name: "capp-zone.com."}, {Name: "internal.capp-zone.com.", Namespaces: []string{"other-ns"}}}
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"legacy.internal.capp-zone.com"}
+	hostnamesStatus, err = buildHostnamesStatus(context.Background(), k8sClient, capp, map[string]bool{rmanagers.DomainMapping: true}, zones)
+	assert.NoError(t, err)
+	assert.Equal(t, []cappv1alpha1.HostnameStatus{
+		{Hostname: "app.capp-zone.com", URL: "http://app.capp-zone.com", DomainMappingReady: metav1.ConditionTrue},
+		{Hostname: "legacy.internal.capp-zone.com", URL: "http://legacy.internal.capp-zone.com", NotAllowed: true},
+	}, hostnamesStatus)
+
+	// The hostnames which are claimed by others are only reported with their owner.
+	claim := &cappv1alpha1.HostnameClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy.capp-zone.com"},
+		Spec:       cappv1alpha1.HostnameClaimSpec{Owner: cappv1alpha1.HostnameClaimOwner{Kind: "Capp", Namespace: "other-ns", Name: "legacy"}},
+	}
+	assert.NoError(t, k8sClient.Create(context.Background(), claim))
+	capp.Spec.RouteSpec.AdditionalHostnames = []string{"legacy"}
+	hostnamesStatus, err = buildHostnamesStatus(context.Background(), k8sClient, capp, map[string]bool{rmanagers.DomainMapping: true}, utils.DNSZones{{Name: "capp-zone.com."}})
+	assert.NoError(t, err)
+	assert.Equal(t, []cappv1alpha1.HostnameStatus{
+		{Hostname: "app.capp-zone.com", URL: "http://app.capp-zone.com", DomainMappingReady: metav1.ConditionTrue},
+		{Hostname: "legacy.capp-zone.com", URL: "http://legacy.capp-zone.com", ClaimedBy: "Capp other-ns/legacy"},
+	}, hostnamesStatus)
+
+	hostnamesStatus, err = buildHostnamesStatus(context.Background(), k8sClient, capp, map[string]bool{}, utils.DNSZones{{Name: "capp-zone.com."}})
+	assert.NoError(t, err)
+	assert.Nil(t, hostnamesStatus)
+}
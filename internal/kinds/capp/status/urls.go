@@ -0,0 +1,69 @@
+package status
+
+import (
+	"context"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	httpScheme  = "http"
+	httpsScheme = "https"
+)
+
+// buildURLStatus sets the top-level URL, revision and traffic fields of the Capp status from
+// the already built Knative status. The public URL is the custom hostname if one is set, the
+// internal URL if the Capp is cluster-local, and otherwise the URL of the Knative Service.
+// The fields are empty when the Capp is disabled.
+func buildURLStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, cappStatus *cappv1alpha1.CappStatus, isRequired map[string]bool) error {
+	cappStatus.URL = ""
+	cappStatus.InternalURL = ""
+	cappStatus.LatestCreatedRevisionName = ""
+	cappStatus.LatestReadyRevisionName = ""
+	cappStatus.Traffic = nil
+
+	if !isRequired[rmanagers.KnativeServing] {
+		return nil
+	}
+
+	knativeStatus := cappStatus.KnativeObjectStatus
+	if knativeStatus.URL != nil {
+		cappStatus.URL = knativeStatus.URL.String()
+	}
+	if knativeStatus.Address != nil && knativeStatus.Address.URL != nil {
+		cappStatus.InternalURL = knativeStatus.Address.URL.String()
+	}
+	cappStatus.LatestCreatedRevisionName = knativeStatus.LatestCreatedRevisionName
+	cappStatus.LatestReadyRevisionName = knativeStatus.LatestReadyRevisionName
+	cappStatus.Traffic = knativeStatus.Traffic
+
+	if utils.IsClusterLocal(capp.Spec.RouteSpec) && cappStatus.InternalURL != "" {
+		cappStatus.URL = cappStatus.InternalURL
+	}
+
+	if !isRequired[rmanagers.DomainMapping] && !isRequired[rmanagers.HTTPRoute] {
+		return nil
+	}
+
+	dnsConfig, err := utils.GetDNSConfig(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	if err != nil {
+		return err
+	}
+
+	scheme := httpScheme
+	if capp.Spec.RouteSpec.TlsEnabled {
+		scheme = httpsScheme
+	}
+	cappStatus.URL = fmt.Sprintf("%s://%s", scheme, zones.ResourceName(capp.Spec.RouteSpec.Hostname))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildURLStatus(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": "capp-zone.com."},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig).Build()
+
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	cappStatus := cappv1alpha1.CappStatus{}
+	cappStatus.KnativeObjectStatus.URL = apis.HTTP("test-capp.test-ns.example.com")
+	cappStatus.KnativeObjectStatus.Address = &duckv1.Addressable{URL: apis.HTTP("test-capp.test-ns.svc.cluster.local")}
+	cappStatus.KnativeObjectStatus.LatestCreatedRevisionName = "test-capp-00002"
+	cappStatus.KnativeObjectStatus.LatestReadyRevisionName = "test-capp-00001"
+	cappStatus.KnativeObjectStatus.Traffic = []knativev1.TrafficTarget{{RevisionName: "test-capp-00001"}}
+
+	isRequired := map[string]bool{rmanagers.KnativeServing: true}
+	assert.NoError(t, buildURLStatus(ctx, k8sClient, capp, &cappStatus, isRequired))
+	assert.Equal(t, "http://test-capp.test-ns.example.com", cappStatus.URL)
+	assert.Equal(t, "http://test-capp.test-ns.svc.cluster.local", cappStatus.InternalURL)
+	assert.Equal(t, "test-capp-00002", cappStatus.LatestCreatedRevisionName)
+	assert.Equal(t, "test-capp-00001", cappStatus.LatestReadyRevisionName)
+	assert.Len(t, cappStatus.Traffic, 1)
+
+	capp.Spec.RouteSpec.Visibility = cappv1alpha1.RouteVisibilityClusterLocal
+	assert.NoError(t, buildURLStatus(ctx, k8sClient, capp, &cappStatus, isRequired))
+	assert.Equal(t, "http://test-capp.test-ns.svc.cluster.local", cappStatus.URL)
+
+	capp.Spec.RouteSpec = cappv1alpha1.RouteSpec{Hostname: "app", TlsEnabled: true}
+	isRequired[rmanagers.DomainMapping] = true
+	assert.NoError(t, buildURLStatus(ctx, k8sClient, capp, &cappStatus, isRequired))
+	assert.Equal(t, "https://app.capp-zone.com", cappStatus.URL)
+
+	assert.NoError(t, buildURLStatus(ctx, k8sClient, capp, &cappStatus, map[string]bool{rmanagers.DomainMapping: true}))
+	assert.Empty(t, cappStatus.URL)
+	assert.Empty(t, cappStatus.LatestReadyRevisionName)
+	assert.Nil(t, cappStatus.Traffic)
+}
@@ -3,11 +3,12 @@ package utils
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
-	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
-
-	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	certv1alpha1 "github.com/dana-team/cert-external-issuer/api/v1alpha1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,30 +19,37 @@ const (
 	zoneKey             = "zone"
 	cnameKey            = "cname"
 	issuerKey           = "issuer"
+	issuerKindKey       = "issuerKind"
+	issuerGroupKey      = "issuerGroup"
 	providerKey         = "provider"
+	backendKey          = "backend"
+	addressesKey        = "addresses"
+	ttlKey              = "ttl"
+	txtOwnerIDKey       = "txtOwnerID"
 	placeholderZone     = "capp.com."
 	placeholderIssuer   = "cert-issuer"
 	placeholderProvider = "dns-default"
 	dot                 = "."
 	maxCommonNameLength = 64
-)
 
-// IsDNSRecordAvailable returns a boolean indicating whether a CNAMERecord is currently available.
-func IsDNSRecordAvailable(ctx context.Context, k8sClient client.Client, name, namespace string) (bool, error) {
-	var available bool
+	// DNSBackendProviderDNS manages the DNS records of hostnames using crossplane provider-dns CNAMERecords.
+	DNSBackendProviderDNS = "provider-dns"
 
-	dnsRecord := dnsrecordv1alpha1.CNAMERecord{}
-	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &dnsRecord); err != nil {
-		return false, fmt.Errorf("failed getting DNSRecord: %w", err)
-	}
+	// DNSBackendExternalDNS manages the DNS records of hostnames using external-dns DNSEndpoints.
+	DNSBackendExternalDNS = "external-dns"
 
-	if dnsRecord.Status.Conditions != nil {
-		readyCondition := dnsRecord.Status.GetCondition(xpcommonv1.TypeReady)
-		available = readyCondition.Equal(xpcommonv1.Available())
-	}
+	// DefaultIssuerKind is the kind of the issuer of Certificates when it is not set.
+	DefaultIssuerKind = "ClusterIssuer"
 
-	return available, nil
-}
+	// IssuerKind is the kind of the namespaced issuers, which are the only issuers a Capp may set for its Certificates.
+	IssuerKind = "Issuer"
+
+	// IssuerGroup is the API group of the issuer of a Capp when it is not set.
+	IssuerGroup = "cert-manager.io"
+
+	// VisibilityLabelKey is the label of a Knative Service which sets the visibility of its route.
+	VisibilityLabelKey = "networking.knative.dev/visibility"
+)
 
 // GetDNSConfig returns the data of the DNS ConfigMap.
 func GetDNSConfig(ctx context.Context, k8sClient client.Client) (map[string]string, error) {
@@ -104,6 +112,64 @@ func GetXPProviderFromConfig(dnsConfig map[string]string) (string, error) {
 	return provider, nil
 }
 
+// GetDNSBackendFromConfig returns the DNS backend which manages the DNS records from a ConfigMap.
+// It defaults to DNSBackendProviderDNS when the key is not set.
+func GetDNSBackendFromConfig(dnsConfig map[string]string) (string, error) {
+	backend, ok := dnsConfig[backendKey]
+	if !ok {
+		return DNSBackendProviderDNS, nil
+	}
+
+	if backend != DNSBackendProviderDNS && backend != DNSBackendExternalDNS {
+		return backend, fmt.Errorf("%q must be one of %q or %q in ConfigMap %q, got %q",
+			backendKey, DNSBackendProviderDNS, DNSBackendExternalDNS, dnsCM, backend)
+	}
+
+	return backend, nil
+}
+
+// GetAddressesFromConfig returns the IP addresses of the ingress which A and AAAA records point at from a ConfigMap.
+// It returns no addresses when the key is not set, in which case CNAME records are used instead.
+func GetAddressesFromConfig(dnsConfig map[string]string) ([]net.IP, error) {
+	value, ok := dnsConfig[addressesKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var addresses []net.IP
+	for _, address := range strings.Split(value, ",") {
+		ip := net.ParseIP(strings.TrimSpace(address))
+		if ip == nil {
+			return nil, fmt.Errorf("%q contains an invalid IP address %q in ConfigMap %q", addressesKey, address, dnsCM)
+		}
+		addresses = append(addresses, ip)
+	}
+
+	return addresses, nil
+}
+
+// GetTTLFromConfig returns the TTL in seconds of the DNS records from a ConfigMap,
+// or nil when the key is not set, in which case the default TTL of the DNS backend is used.
+func GetTTLFromConfig(dnsConfig map[string]string) (*int64, error) {
+	value, ok := dnsConfig[ttlKey]
+	if !ok {
+		return nil, nil
+	}
+
+	ttl, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ttl < 1 {
+		return nil, fmt.Errorf("%q must be a positive number of seconds in ConfigMap %q, got %q", ttlKey, dnsCM, value)
+	}
+
+	return &ttl, nil
+}
+
+// GetTXTOwnerIDFromConfig returns the ID of the owner which is written to the ownership TXT records from a ConfigMap.
+// Ownership TXT records are not created when it is not set.
+func GetTXTOwnerIDFromConfig(dnsConfig map[string]string) string {
+	return dnsConfig[txtOwnerIDKey]
+}
+
 // GetIssuerNameFromConfig returns the name of the Certificate Issuer
 // to be used for the Certificate from a ConfigMap.
 func GetIssuerNameFromConfig(dnsConfig map[string]string) (string, error) {
@@ -121,6 +187,29 @@ func GetIssuerNameFromConfig(dnsConfig map[string]string) (string, error) {
 	return issuer, nil
 }
 
+// GetIssuerRefFromConfig returns the issuer to be used for the Certificate from a ConfigMap. Its kind and group
+// are set using the issuerKind and issuerGroup keys, and default to a ClusterIssuer of the cert-external-issuer.
+func GetIssuerRefFromConfig(dnsConfig map[string]string) (cappv1alpha1.CertificateIssuerReference, error) {
+	issuer, err := GetIssuerNameFromConfig(dnsConfig)
+	if err != nil {
+		return cappv1alpha1.CertificateIssuerReference{}, err
+	}
+
+	issuerRef := cappv1alpha1.CertificateIssuerReference{
+		Name:  issuer,
+		Kind:  DefaultIssuerKind,
+		Group: certv1alpha1.GroupVersion.Group,
+	}
+	if kind := dnsConfig[issuerKindKey]; kind != "" {
+		issuerRef.Kind = kind
+	}
+	if group := dnsConfig[issuerGroupKey]; group != "" {
+		issuerRef.Group = group
+	}
+
+	return issuerRef, nil
+}
+
 // GenerateResourceName generates the hostname based on the provided suffix and a dot(".") trailing character.
 // If the hostname does not already end with the suffix (minus the trailing dot), it appends the suffix to the hostname.
 func GenerateResourceName(hostname, suffix string) string {
@@ -155,3 +244,14 @@ func TruncateCommonName(s string) string {
 	}
 	return s
 }
+
+// GenerateTagHostname generates the hostname of a tagged traffic target by prefixing
+// the first label of the hostname of the Capp with the tag.
+func GenerateTagHostname(tag, hostname string) string {
+	return fmt.Sprintf("%s-%s", tag, hostname)
+}
+
+// IsClusterLocal returns a boolean indicating whether a route is only reachable from within the cluster.
+func IsClusterLocal(routeSpec cappv1alpha1.RouteSpec) bool {
+	return routeSpec.Visibility == cappv1alpha1.RouteVisibilityClusterLocal
+}
@@ -18,6 +18,7 @@ var (
 	CappAPIGroup      = cappv1alpha1.GroupVersion.Group
 	CappNamespaceKey  = CappAPIGroup + "/parent-capp-ns"
 	CappResourceKey   = CappAPIGroup + "/parent-capp"
+	RouterResourceKey = CappAPIGroup + "/parent-capprouter"
 	ManagedByLabelKey = CappAPIGroup + "/managed-by"
 )
 
@@ -98,3 +99,15 @@ func GetListOptions(set labels.Set) client.ListOptions {
 
 	return listOptions
 }
+
+// WithoutLastModifiedBy returns a copy of the annotations without the LastModifiedByAnnotation,
+// which changes together with every other field of a Capp.
+func WithoutLastModifiedBy(annotations map[string]string) map[string]string {
+	filtered := map[string]string{}
+	for key, value := range annotations {
+		if key != cappv1alpha1.LastModifiedByAnnotation {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
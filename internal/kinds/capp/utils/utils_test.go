@@ -3,6 +3,7 @@ package utils_test
 import (
 	"testing"
 
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -22,3 +23,92 @@ func TestFilterKeysWithoutPrefix(t *testing.T) {
 
 	assert.Equal(t, expected, utils.FilterKeysWithoutPrefix(object, prefix))
 }
+
+func TestGetDNSZonesFromConfig(t *testing.T) {
+	dnsConfig := map[string]string{
+		"zone":   "capp-zone.com.",
+		"cname":  "ingress.capp-zone.com.",
+		"issuer": "cert-issuer",
+		"zones": `
+- name: internal.capp-zone.com.
+  cname: ingress.internal.capp-zone.com.
+  namespaces: [team-a]
+- name: other-zone.com.
+  issuer: other-issuer
+`,
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	assert.NoError(t, err)
+	assert.Len(t, zones, 3)
+
+	zone := zones.Match("app.internal.capp-zone.com")
+	assert.Equal(t, "internal.capp-zone.com.", zone.Name)
+	assert.Equal(t, "ingress.internal.capp-zone.com.", zone.Config["cname"])
+	assert.Equal(t, "cert-issuer", zone.Config["issuer"])
+	assert.True(t, zone.IsNamespaceAllowed("team-a"))
+	assert.False(t, zone.IsNamespaceAllowed("team-b"))
+
+	assert.Equal(t, "other-issuer", zones.Match("app.other-zone.com").Config["issuer"])
+	assert.Equal(t, "capp-zone.com.", zones.Match("app.capp-zone.com").Name)
+	assert.Equal(t, "app.capp-zone.com", zones.ResourceName("app"))
+	assert.Equal(t, "app.other-zone.com", zones.ResourceName("app.other-zone.com"))
+
+	assert.NoError(t, zones.ValidateNamespace("app.capp-zone.com", "team-b"))
+	assert.Error(t, zones.ValidateNamespace("app.internal.capp-zone.com", "team-b"))
+
+	// The namespaces key restricts the default zone, which hostnames outside of every zone are added to.
+	dnsConfig["namespaces"] = "team-a, team-c"
+	zones, err = utils.GetDNSZonesFromConfig(dnsConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-c"}, zones[0].Namespaces)
+	assert.NoError(t, zones.ValidateNamespace("app", "team-c"))
+	assert.Error(t, zones.ValidateNamespace("app", "team-b"))
+	assert.NoError(t, zones.ValidateNamespace("app.other-zone.com", "team-b"))
+}
+
+func TestGetIssuerRefFromConfig(t *testing.T) {
+	dnsConfig := map[string]string{
+		"zone":   "capp-zone.com.",
+		"issuer": "cert-issuer",
+		"zones": `
+- name: dev-zone.com.
+  issuer: selfsigned
+  issuerKind: Issuer
+  issuerGroup: cert-manager.io
+`,
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	assert.NoError(t, err)
+
+	issuerRef, err := utils.GetIssuerRefFromConfig(zones.Match("app.capp-zone.com").Config)
+	assert.NoError(t, err)
+	assert.Equal(t, cappv1alpha1.CertificateIssuerReference{Name: "cert-issuer", Kind: "ClusterIssuer", Group: "cert.dana.io"}, issuerRef)
+
+	issuerRef, err = utils.GetIssuerRefFromConfig(zones.Match("app.dev-zone.com").Config)
+	assert.NoError(t, err)
+	assert.Equal(t, cappv1alpha1.CertificateIssuerReference{Name: "selfsigned", Kind: "Issuer", Group: "cert-manager.io"}, issuerRef)
+
+	_, err = utils.GetIssuerRefFromConfig(map[string]string{"zone": "capp-zone.com."})
+	assert.Error(t, err)
+}
+
+func TestGetDNSZonesFromConfigErrors(t *testing.T) {
+	for name, zonesValue := range map[string]string{
+		"invalid list":         "name: capp-zone.com.",
+		"missing trailing dot": "- name: capp-zone.com",
+		"duplicate zone":       "- name: capp-zone.com.\n- name: capp-zone.com.",
+		"unknown key":          "- name: capp-zone.com.\n  namespace: team-a",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := utils.GetDNSZonesFromConfig(map[string]string{"zones": zonesValue})
+			assert.Error(t, err)
+		})
+	}
+
+	// The zones key makes the zone key optional, in which case the first of the zones is the default zone.
+	zones, err := utils.GetDNSZonesFromConfig(map[string]string{"zones": "- name: capp-zone.com."})
+	assert.NoError(t, err)
+	assert.Equal(t, "app.capp-zone.com", zones.ResourceName("app"))
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	zonesKey      = "zones"
+	namespacesKey = "namespaces"
+)
+
+// DNSZone is a zone in which the hostnames of Capps are created.
+type DNSZone struct {
+	// Name is the name of the zone, which ends with a dot.
+	Name string
+
+	// Namespaces are the namespaces which may use the zone. Every namespace may use it when it is empty.
+	Namespaces []string
+
+	// Config is the data of the DNS ConfigMap as seen by the hostnames of the zone, where the settings
+	// of the zone override those of the ConfigMap, so that it can be passed to the other getters.
+	Config map[string]string
+}
+
+// DNSZones are the zones of the DNS ConfigMap. The first zone is the default zone, to which
+// hostnames which are not in any of the zones are added.
+type DNSZones []DNSZone
+
+// dnsZoneEntry is an entry of the zones key of the DNS ConfigMap.
+type dnsZoneEntry struct {
+	Name        string   `json:"name"`
+	CNAME       string   `json:"cname,omitempty"`
+	Provider    string   `json:"provider,omitempty"`
+	Issuer      string   `json:"issuer,omitempty"`
+	IssuerKind  string   `json:"issuerKind,omitempty"`
+	IssuerGroup string   `json:"issuerGroup,omitempty"`
+	Namespaces  []string `json:"namespaces,omitempty"`
+}
+
+// GetDNSZonesFromConfig returns the zones of a ConfigMap. The zone key, if set, is the default zone and may be used
+// by the comma-separated namespaces of the namespaces key, or by every namespace when it is not set. The zones key holds a list of additional zones, each with its own cname, provider and issuer,
+// including the kind and group of the issuer, which default to those of the ConfigMap, and with the namespaces
+// which may use it.
+func GetDNSZonesFromConfig(dnsConfig map[string]string) (DNSZones, error) {
+	if len(dnsConfig) == 0 {
+		return DNSZones{{Name: placeholderZone, Config: dnsConfig}}, nil
+	}
+
+	var zones DNSZones
+	_, hasZones := dnsConfig[zonesKey]
+	if _, ok := dnsConfig[zoneKey]; ok || !hasZones {
+		zone, err := GetZoneFromConfig(dnsConfig)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, DNSZone{Name: zone, Namespaces: getNamespacesFromConfig(dnsConfig), Config: dnsConfig})
+	}
+
+	if !hasZones {
+		return zones, nil
+	}
+
+	var entries []dnsZoneEntry
+	if err := yaml.UnmarshalStrict([]byte(dnsConfig[zonesKey]), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %q in ConfigMap %q: %w", zonesKey, dnsCM, err)
+	}
+
+	for i, entry := range entries {
+		if entry.Name == "" || !strings.HasSuffix(entry.Name, dot) {
+			return nil, fmt.Errorf("the name of zone %d must end with a %q in ConfigMap %q, got %q", i, dot, dnsCM, entry.Name)
+		}
+		if slices.ContainsFunc(zones, func(zone DNSZone) bool { return zone.Name == entry.Name }) {
+			return nil, fmt.Errorf("zone %q is set more than once in ConfigMap %q", entry.Name, dnsCM)
+		}
+		zones = append(zones, DNSZone{Name: entry.Name, Namespaces: entry.Namespaces, Config: entry.config(dnsConfig)})
+	}
+
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("%q is empty in ConfigMap %q", zonesKey, dnsCM)
+	}
+
+	return zones, nil
+}
+
+// getNamespacesFromConfig returns the namespaces which may use the default zone from a ConfigMap.
+func getNamespacesFromConfig(dnsConfig map[string]string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(dnsConfig[namespacesKey], ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces
+}
+
+// config returns the data of the DNS ConfigMap with the settings of the zone.
+func (e dnsZoneEntry) config(dnsConfig map[string]string) map[string]string {
+	config := make(map[string]string, len(dnsConfig))
+	for key, value := range dnsConfig {
+		config[key] = value
+	}
+
+	config[zoneKey] = e.Name
+	for key, value := range map[string]string{cnameKey: e.CNAME, providerKey: e.Provider, issuerKey: e.Issuer,
+		issuerKindKey: e.IssuerKind, issuerGroupKey: e.IssuerGroup} {
+		if value != "" {
+			config[key] = value
+		}
+	}
+
+	return config
+}
+
+// Contains returns a boolean indicating whether a hostname is the zone or one of its subdomains.
+func (z DNSZone) Contains(hostname string) bool {
+	zoneWithoutTrailingDot := strings.TrimSuffix(z.Name, dot)
+	return hostname == zoneWithoutTrailingDot || strings.HasSuffix(hostname, dot+zoneWithoutTrailingDot)
+}
+
+// IsNamespaceAllowed returns a boolean indicating whether a namespace may use the zone.
+func (z DNSZone) IsNamespaceAllowed(namespace string) bool {
+	return len(z.Namespaces) == 0 || slices.Contains(z.Namespaces, namespace)
+}
+
+// Match returns the longest zone which contains the hostname, or the default zone if none of them does.
+func (z DNSZones) Match(hostname string) DNSZone {
+	match := z[0]
+	matched := false
+	for _, zone := range z {
+		if zone.Contains(hostname) && (!matched || len(zone.Name) > len(match.Name)) {
+			match, matched = zone, true
+		}
+	}
+
+	return match
+}
+
+// ResourceName returns the hostname in the zone which it matches, adding the default zone to it if needed.
+func (z DNSZones) ResourceName(hostname string) string {
+	return GenerateResourceName(hostname, z.Match(hostname).Name)
+}
+
+// ValidateNamespace returns an error if the zone of a hostname may not be used by the given namespace.
+func (z DNSZones) ValidateNamespace(hostname, namespace string) error {
+	zone := z.Match(hostname)
+	if !zone.IsNamespaceAllowed(namespace) {
+		return fmt.Errorf("namespace %q is not allowed to use zone %q of hostname %q", namespace, zone.Name, hostname)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/autoscale"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	defaultState       = "enabled"
+	defaultScaleMetric = "concurrency"
+)
+
+// metricDependentAnnotations are the resolved autoscale annotations whose values
+// depend on the ScaleMetric of the Capp.
+var metricDependentAnnotations = []string{
+	autoscale.KnativeAutoscaleClassKey,
+	autoscale.KnativeMetricKey,
+	autoscale.KnativeAutoscaleTargetKey,
+}
+
+// +kubebuilder:webhook:path=/mutate-rcs-dana-io-v1alpha1-capp,mutating=true,failurePolicy=fail,sideEffects=None,groups=rcs.dana.io,resources=capps,verbs=create;update,versions=v1alpha1,name=mcapp.rcs.dana.io,admissionReviewVersions=v1
+
+// CappDefaulter sets defaults on Capp objects on creation and update.
+type CappDefaulter struct {
+	Client client.Client
+}
+
+// SetupWebhookWithManager registers the Capp defaulting webhook with the Manager.
+func (d *CappDefaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&cappv1alpha1.Capp{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default sets the State and ScaleMetric defaults of a Capp and writes the resolved
+// autoscale annotations into its template, so the stored Capp shows the effective values.
+// It also records who made the change in the LastModifiedByAnnotation.
+func (d *CappDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	capp, ok := obj.(*cappv1alpha1.Capp)
+	if !ok {
+		return fmt.Errorf("expected a Capp but got a %T", obj)
+	}
+
+	if !capp.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	if capp.Spec.State == "" {
+		capp.Spec.State = defaultState
+	}
+	if capp.Spec.ScaleMetric == "" {
+		capp.Spec.ScaleMetric = defaultScaleMetric
+	}
+	migrateTrafficTarget(capp)
+
+	oldCapp, err := oldCappFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if oldCapp != nil && oldCapp.Spec.ScaleMetric != capp.Spec.ScaleMetric {
+		removeStaleAutoScaleAnnotations(oldCapp, capp)
+	}
+
+	autoScaleDefaults, err := autoscale.GetAutoScaleDefaults(ctx, d.Client)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	template := &capp.Spec.ConfigurationSpec.Template
+	template.Annotations = utils.MergeMaps(template.Annotations, autoscale.SetAutoScaler(*capp, autoScaleDefaults))
+
+	return setLastModifiedBy(ctx, oldCapp, capp)
+}
+
+// setLastModifiedBy stamps the user of the admission request in the context into the LastModifiedByAnnotation
+// of a Capp when it is created or when its spec, labels or annotations change. Otherwise, the annotation is
+// kept as it was before the update, so it can not be set by users directly. It is also kept when the update
+// completes a rollback, so that the rollback is attributed to the user who requested it.
+func setLastModifiedBy(ctx context.Context, oldCapp, capp *cappv1alpha1.Capp) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+
+	if oldCapp != nil && (!isModified(oldCapp, capp) || isRollbackCompletion(oldCapp, capp)) {
+		oldValue, ok := oldCapp.Annotations[cappv1alpha1.LastModifiedByAnnotation]
+		if !ok {
+			delete(capp.Annotations, cappv1alpha1.LastModifiedByAnnotation)
+			return nil
+		}
+		if capp.Annotations == nil {
+			capp.Annotations = map[string]string{}
+		}
+		capp.Annotations[cappv1alpha1.LastModifiedByAnnotation] = oldValue
+		return nil
+	}
+
+	modifiedBy, err := json.Marshal(cappv1alpha1.ModifiedBy{
+		Username:  req.UserInfo.Username,
+		Groups:    req.UserInfo.Groups,
+		Timestamp: metav1.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %q annotation: %w", cappv1alpha1.LastModifiedByAnnotation, err)
+	}
+
+	if capp.Annotations == nil {
+		capp.Annotations = map[string]string{}
+	}
+	capp.Annotations[cappv1alpha1.LastModifiedByAnnotation] = string(modifiedBy)
+	return nil
+}
+
+// isRollbackCompletion returns a boolean indicating whether an update removes the rollback annotation of a Capp.
+func isRollbackCompletion(oldCapp, capp *cappv1alpha1.Capp) bool {
+	_, wasRequested := oldCapp.Annotations[cappv1alpha1.RollbackToRevisionAnnotation]
+	_, isRequested := capp.Annotations[cappv1alpha1.RollbackToRevisionAnnotation]
+	return wasRequested && !isRequested
+}
+
+// isModified returns a boolean indicating whether the spec, labels or annotations of a Capp,
+// other than the LastModifiedByAnnotation, differ from the Capp before the update.
+func isModified(oldCapp, capp *cappv1alpha1.Capp) bool {
+	return !equality.Semantic.DeepEqual(oldCapp.Spec, capp.Spec) ||
+		!equality.Semantic.DeepEqual(oldCapp.Labels, capp.Labels) ||
+		!equality.Semantic.DeepEqual(utils.WithoutLastModifiedBy(oldCapp.Annotations), utils.WithoutLastModifiedBy(capp.Annotations))
+}
+
+// migrateTrafficTarget moves the deprecated single TrafficTarget of a Capp into TrafficTargets,
+// unless TrafficTargets is already set. As the only target, it receives all the traffic, and it
+// routes to the latest revision unless it sets a revision, as the ignored TrafficTarget used to.
+func migrateTrafficTarget(capp *cappv1alpha1.Capp) {
+	routeSpec := &capp.Spec.RouteSpec
+	if len(routeSpec.TrafficTargets) > 0 || equality.Semantic.DeepEqual(routeSpec.TrafficTarget, knativev1.TrafficTarget{}) {
+		return
+	}
+
+	trafficTarget := routeSpec.TrafficTarget
+	trafficTarget.Percent = ptr.To(int64(100))
+	if trafficTarget.RevisionName == "" {
+		trafficTarget.LatestRevision = ptr.To(true)
+	}
+
+	routeSpec.TrafficTargets = []cappv1alpha1.CappTrafficTarget{{TrafficTarget: trafficTarget}}
+	routeSpec.TrafficTarget = knativev1.TrafficTarget{}
+}
+
+// oldCappFromContext returns the Capp as it was before the update from the admission
+// request in the context, or nil if the request is not an update.
+func oldCappFromContext(ctx context.Context) (*cappv1alpha1.Capp, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation != admissionv1.Update || len(req.OldObject.Raw) == 0 {
+		return nil, nil
+	}
+
+	oldCapp := &cappv1alpha1.Capp{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldCapp); err != nil {
+		return nil, fmt.Errorf("failed to decode old Capp: %w", err)
+	}
+
+	return oldCapp, nil
+}
+
+// removeStaleAutoScaleAnnotations removes the metric dependent annotations which were
+// left unchanged by an update of the ScaleMetric, so that they are resolved again
+// for the new metric instead of being kept as if the user had set them.
+func removeStaleAutoScaleAnnotations(oldCapp, capp *cappv1alpha1.Capp) {
+	oldAnnotations := oldCapp.Spec.ConfigurationSpec.Template.Annotations
+	annotations := capp.Spec.ConfigurationSpec.Template.Annotations
+
+	for _, key := range metricDependentAnnotations {
+		value, ok := annotations[key]
+		if ok && oldAnnotations[key] == value {
+			delete(annotations, key)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/autoscale"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDefaultCapp(t *testing.T) {
+	defaulter := CappDefaulter{Client: newFakeClient()}
+
+	capp := newCapp()
+	capp.Spec.ConfigurationSpec.Template.Annotations = map[string]string{
+		"autoscaling.knative.dev/min-scale": "1",
+		autoscale.KnativeAutoscaleTargetKey: "20",
+	}
+	assert.NoError(t, defaulter.Default(context.Background(), capp))
+
+	assert.Equal(t, defaultState, capp.Spec.State)
+	assert.Equal(t, defaultScaleMetric, capp.Spec.ScaleMetric)
+	assert.Equal(t, map[string]string{
+		"autoscaling.knative.dev/min-scale":        "1",
+		"autoscaling.knative.dev/class":            "kpa.autoscaling.knative.dev",
+		"autoscaling.knative.dev/metric":           "concurrency",
+		"autoscaling.knative.dev/target":           "20",
+		"autoscaling.knative.dev/activation-scale": "3",
+	}, capp.Spec.ConfigurationSpec.Template.Annotations)
+}
+
+func TestDefaultCappWithAutoScaleDefaults(t *testing.T) {
+	k8sClient := newFakeClient()
+	autoScaleDefaults := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      autoscale.DefaultAutoScaleCM,
+			Namespace: utils.CappNS,
+		},
+		Data: map[string]string{"cpu": "60", "activationScale": "2"},
+	}
+	assert.NoError(t, k8sClient.Create(context.Background(), autoScaleDefaults))
+	defaulter := CappDefaulter{Client: k8sClient}
+
+	capp := newCapp()
+	capp.Spec.ScaleMetric = "cpu"
+	assert.NoError(t, defaulter.Default(context.Background(), capp))
+
+	assert.Equal(t, map[string]string{
+		"autoscaling.knative.dev/class":            "hpa.autoscaling.knative.dev",
+		"autoscaling.knative.dev/metric":           "cpu",
+		"autoscaling.knative.dev/target":           "60",
+		"autoscaling.knative.dev/activation-scale": "2",
+	}, capp.Spec.ConfigurationSpec.Template.Annotations)
+}
+
+func TestDefaultCappScaleMetricUpdate(t *testing.T) {
+	defaulter := CappDefaulter{Client: newFakeClient()}
+
+	oldCapp := newCapp()
+	oldCapp.Spec.ScaleMetric = "cpu"
+	assert.NoError(t, defaulter.Default(context.Background(), oldCapp))
+
+	capp := oldCapp.DeepCopy()
+	capp.Spec.ScaleMetric = "rps"
+	oldRaw, err := json.Marshal(oldCapp)
+	assert.NoError(t, err)
+
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+		},
+	})
+	assert.NoError(t, defaulter.Default(ctx, capp))
+
+	assert.Equal(t, map[string]string{
+		"autoscaling.knative.dev/class":            "kpa.autoscaling.knative.dev",
+		"autoscaling.knative.dev/metric":           "rps",
+		"autoscaling.knative.dev/target":           "200",
+		"autoscaling.knative.dev/activation-scale": "3",
+	}, capp.Spec.ConfigurationSpec.Template.Annotations)
+}
+
+func TestDefaultCappLastModifiedBy(t *testing.T) {
+	defaulter := CappDefaulter{Client: newFakeClient()}
+	userInfo := authenticationv1.UserInfo{Username: "alice", Groups: []string{"devs"}}
+
+	capp := newCapp()
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create, UserInfo: userInfo},
+	})
+	assert.NoError(t, defaulter.Default(ctx, capp))
+
+	modifiedBy := cappv1alpha1.ModifiedBy{}
+	assert.NoError(t, json.Unmarshal([]byte(capp.Annotations[cappv1alpha1.LastModifiedByAnnotation]), &modifiedBy))
+	assert.Equal(t, "alice", modifiedBy.Username)
+	assert.Equal(t, []string{"devs"}, modifiedBy.Groups)
+
+	oldCapp := capp.DeepCopy()
+	oldRaw, err := json.Marshal(oldCapp)
+	assert.NoError(t, err)
+	newUpdateContext := func(username string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: username},
+				OldObject: runtime.RawExtension{Raw: oldRaw},
+			},
+		})
+	}
+
+	capp.Finalizers = []string{"dana.io/capp-cleanup"}
+	capp.Annotations[cappv1alpha1.LastModifiedByAnnotation] = `{"username":"mallory"}`
+	assert.NoError(t, defaulter.Default(newUpdateContext("operator"), capp))
+	assert.Equal(t, oldCapp.Annotations[cappv1alpha1.LastModifiedByAnnotation], capp.Annotations[cappv1alpha1.LastModifiedByAnnotation])
+
+	capp.Spec.Site = "cluster-a"
+	assert.NoError(t, defaulter.Default(newUpdateContext("bob"), capp))
+	assert.NoError(t, json.Unmarshal([]byte(capp.Annotations[cappv1alpha1.LastModifiedByAnnotation]), &modifiedBy))
+	assert.Equal(t, "bob", modifiedBy.Username)
+}
+
+func TestDefaultCappMigratesTrafficTarget(t *testing.T) {
+	defaulter := CappDefaulter{Client: newFakeClient()}
+
+	capp := newCapp()
+	capp.Spec.RouteSpec.TrafficTarget = knativev1.TrafficTarget{RevisionName: "test-capp-00001"}
+	assert.NoError(t, defaulter.Default(context.Background(), capp))
+
+	assert.Empty(t, capp.Spec.RouteSpec.TrafficTarget)
+	assert.Equal(t, []cappv1alpha1.CappTrafficTarget{{TrafficTarget: knativev1.TrafficTarget{RevisionName: "test-capp-00001", Percent: ptr.To(int64(100))}}},
+		capp.Spec.RouteSpec.TrafficTargets)
+	assert.Empty(t, validateTrafficTargets(capp.Spec.RouteSpec, field.NewPath("spec", "routeSpec")))
+
+	// A leftover TrafficTarget without a revision routes all the traffic to the latest revision.
+	capp = newCapp()
+	capp.Spec.RouteSpec.TrafficTarget = knativev1.TrafficTarget{Tag: "current"}
+	assert.NoError(t, defaulter.Default(context.Background(), capp))
+
+	assert.Equal(t, []cappv1alpha1.CappTrafficTarget{{TrafficTarget: knativev1.TrafficTarget{Tag: "current", LatestRevision: ptr.To(true), Percent: ptr.To(int64(100))}}},
+		capp.Spec.RouteSpec.TrafficTargets)
+	assert.Empty(t, validateTrafficTargets(capp.Spec.RouteSpec, field.NewPath("spec", "routeSpec")))
+}
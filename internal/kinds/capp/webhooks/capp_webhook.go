@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-rcs-dana-io-v1alpha1-capp,mutating=false,failurePolicy=fail,sideEffects=None,groups=rcs.dana.io,resources=capps,verbs=create;update,versions=v1alpha1,name=vcapp.rcs.dana.io,admissionReviewVersions=v1
+
+// CappValidator validates Capp objects on creation and update.
+type CappValidator struct {
+	Client client.Client
+}
+
+// SetupWebhookWithManager registers the Capp validating webhook with the Manager.
+func (v *CappValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&cappv1alpha1.Capp{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates a Capp on creation.
+func (v *CappValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	capp, ok := obj.(*cappv1alpha1.Capp)
+	if !ok {
+		return nil, fmt.Errorf("expected a Capp but got a %T", obj)
+	}
+
+	return nil, v.validate(ctx, capp)
+}
+
+// ValidateUpdate validates a Capp on update. Capps which are being deleted are
+// not validated, so that their finalizer can always be removed.
+func (v *CappValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	capp, ok := newObj.(*cappv1alpha1.Capp)
+	if !ok {
+		return nil, fmt.Errorf("expected a Capp but got a %T", newObj)
+	}
+
+	if !capp.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	return nil, v.validate(ctx, capp)
+}
+
+// ValidateDelete does nothing, since deletion of a Capp is always allowed.
+func (v *CappValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate returns an Invalid error containing all the field errors found in the Capp.
+func (v *CappValidator) validate(ctx context.Context, capp *cappv1alpha1.Capp) error {
+	errs := ValidateCapp(ctx, v.Client, capp)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(cappv1alpha1.GroupVersion.WithKind("Capp").GroupKind(), capp.Name, errs)
+}
@@ -0,0 +1,471 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	dot = "."
+)
+
+// ValidateCapp validates the spec and annotations of a Capp and returns a list of the field errors found.
+func ValidateCapp(ctx context.Context, k8sClient client.Client, capp *cappv1alpha1.Capp) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateRevisionAnnotations(capp.Annotations, field.NewPath("metadata", "annotations"))...)
+
+	allErrs = append(allErrs, validateRouteSpec(ctx, k8sClient, capp.Spec.RouteSpec, getTagHostnameTags(capp.Spec), capp.Namespace, specPath.Child("routeSpec"))...)
+	allErrs = append(allErrs, validateTrafficTargets(capp.Spec.RouteSpec, specPath.Child("routeSpec"))...)
+	allErrs = append(allErrs, validateRolloutSpec(capp.Spec, specPath)...)
+	allErrs = append(allErrs, validateLogSpec(capp.Spec.LogSpec, specPath.Child("logSpec"))...)
+	allErrs = append(allErrs, validateVolumesSpec(capp.Spec.VolumesSpec, specPath.Child("volumesSpec"))...)
+
+	return allErrs
+}
+
+// validateRevisionAnnotations validates that the rollback, promotion and revision retention annotations, if set,
+// hold a positive revision number, a positive number of revisions and a non-negative duration.
+func validateRevisionAnnotations(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, annotation := range []string{cappv1alpha1.RollbackToRevisionAnnotation, cappv1alpha1.PromoteRevisionAnnotation, cappv1alpha1.RevisionsToKeepAnnotation} {
+		value, ok := annotations[annotation]
+		if !ok {
+			continue
+		}
+		if number, err := strconv.Atoi(value); err != nil || number < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(annotation), value, "must be a positive number"))
+		}
+	}
+
+	if value, ok := annotations[cappv1alpha1.RevisionMaxAgeAnnotation]; ok {
+		if maxAge, err := time.ParseDuration(value); err != nil || maxAge < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(cappv1alpha1.RevisionMaxAgeAnnotation), value, "must be a non-negative duration"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRouteSpec validates that TLS, tag hostnames and additional hostnames are only set together with a custom
+// hostname, and never on a cluster-local route, that the issuer of the Certificate is valid, that the custom and
+// additional hostnames, if set, fit a zone from the DNS ConfigMap which the namespace may use, that none of the
+// hostnames is used by a CappRouter and that the tag hostnames are valid.
+func validateRouteSpec(ctx context.Context, k8sClient client.Client, routeSpec cappv1alpha1.RouteSpec, tags []string, namespace string, fldPath *field.Path) field.ErrorList {
+	allErrs := validateCertificateIssuerRef(routeSpec, fldPath.Child("certificateIssuerRef"))
+
+	if utils.IsClusterLocal(routeSpec) {
+		return append(allErrs, validateClusterLocalRouteSpec(routeSpec, fldPath)...)
+	}
+
+	if !utils.IsCustomHostnameSet(routeSpec.Hostname) {
+		if routeSpec.TlsEnabled {
+			allErrs = append(allErrs, field.Required(fldPath.Child("hostname"), "hostname must be set when tlsEnabled is true"))
+		}
+		if routeSpec.TagHostnamesEnabled {
+			allErrs = append(allErrs, field.Required(fldPath.Child("hostname"), "hostname must be set when tagHostnamesEnabled is true"))
+		}
+		if len(routeSpec.AdditionalHostnames) > 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("hostname"), "hostname must be set when additionalHostnames is set"))
+		}
+		return allErrs
+	}
+
+	hostnamePath := fldPath.Child("hostname")
+	dnsConfig, err := utils.GetDNSConfig(ctx, k8sClient)
+	if err != nil {
+		return append(allErrs, field.InternalError(hostnamePath, err))
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	if err != nil {
+		return append(allErrs, field.InternalError(hostnamePath, err))
+	}
+
+	allErrs = append(allErrs, ValidateZoneHostname(routeSpec.Hostname, namespace, zones, hostnamePath)...)
+	allErrs = append(allErrs, validateAdditionalHostnames(routeSpec, tags, namespace, zones, fldPath.Child("additionalHostnames"))...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateRouterHostnames(ctx, k8sClient, routeSpec, tags, namespace, zones, fldPath)...)
+	if !routeSpec.TagHostnamesEnabled {
+		return allErrs
+	}
+
+	resourceName := zones.ResourceName(routeSpec.Hostname)
+	for i, trafficTarget := range routeSpec.TrafficTargets {
+		if trafficTarget.Tag == "" {
+			continue
+		}
+		tagHostname := utils.GenerateTagHostname(trafficTarget.Tag, resourceName)
+		label := strings.SplitN(tagHostname, dot, 2)[0]
+		for _, msg := range validation.IsDNS1123Label(label) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficTargets").Index(i).Child("tag"), trafficTarget.Tag,
+				fmt.Sprintf("%q is not a valid hostname: %s", tagHostname, msg)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateCertificateIssuerRef validates that the issuer of the Certificate is only set when TLS is enabled,
+// and that it is an Issuer in the namespace of the Capp, as the cluster-scoped issuers are shared by all
+// the tenants of the cluster and are only used through the zones of the DNS ConfigMap.
+func validateCertificateIssuerRef(routeSpec cappv1alpha1.RouteSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	issuerRef := routeSpec.CertificateIssuerRef
+	if issuerRef == nil {
+		return allErrs
+	}
+
+	if !routeSpec.TlsEnabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "certificateIssuerRef can only be set when tlsEnabled is true"))
+	}
+	if issuerRef.Kind != "" && issuerRef.Kind != utils.IssuerKind {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("kind"), issuerRef.Kind, []string{utils.IssuerKind}))
+	}
+
+	return allErrs
+}
+
+// validateClusterLocalRouteSpec validates that no custom hostname, TLS, tag hostnames or additional
+// hostnames are set on a cluster-local route, as it is not reachable from outside the cluster.
+func validateClusterLocalRouteSpec(routeSpec cappv1alpha1.RouteSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	msg := fmt.Sprintf("must not be set when visibility is %q", cappv1alpha1.RouteVisibilityClusterLocal)
+
+	if utils.IsCustomHostnameSet(routeSpec.Hostname) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("hostname"), msg))
+	}
+	if routeSpec.TlsEnabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("tlsEnabled"), msg))
+	}
+	if routeSpec.TagHostnamesEnabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("tagHostnamesEnabled"), msg))
+	}
+	if len(routeSpec.AdditionalHostnames) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalHostnames"), msg))
+	}
+
+	return allErrs
+}
+
+// validateAdditionalHostnames validates that every additional hostname is a valid hostname in a zone which the
+// namespace may use, and that it is neither the hostname, another additional hostname nor the tag hostname of
+// one of the given tags once the zone is appended to it.
+func validateAdditionalHostnames(routeSpec cappv1alpha1.RouteSpec, tags []string, namespace string, zones utils.DNSZones, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	hostname := zones.ResourceName(routeSpec.Hostname)
+	tagHostnames := map[string]string{}
+	for _, tag := range tags {
+		tagHostnames[utils.GenerateTagHostname(tag, hostname)] = tag
+	}
+
+	seen := map[string]bool{hostname: true}
+	for i, additionalHostname := range routeSpec.AdditionalHostnames {
+		if errs := ValidateZoneHostname(additionalHostname, namespace, zones, fldPath.Index(i)); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+			continue
+		}
+
+		resourceName := zones.ResourceName(additionalHostname)
+		if tag, ok := tagHostnames[resourceName]; ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), additionalHostname,
+				fmt.Sprintf("hostname is the tag hostname of tag %q", tag)))
+		} else if seen[resourceName] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), additionalHostname))
+		}
+		seen[resourceName] = true
+	}
+
+	return allErrs
+}
+
+// validateRouterHostnames validates that neither the hostname, the additional hostnames nor the tag hostnames of
+// the given tags are the hostname of a CappRouter in the namespace, as their HTTPRoutes would have the same name.
+func validateRouterHostnames(ctx context.Context, k8sClient client.Client, routeSpec cappv1alpha1.RouteSpec, tags []string, namespace string, zones utils.DNSZones, fldPath *field.Path) field.ErrorList {
+	routers := cappv1alpha1.CappRouterList{}
+	if err := k8sClient.List(ctx, &routers, client.InNamespace(namespace)); err != nil {
+		return field.ErrorList{field.InternalError(fldPath.Child("hostname"), fmt.Errorf("failed to list CappRouters: %w", err))}
+	}
+
+	routerNames := map[string]string{}
+	for _, router := range routers.Items {
+		routerNames[zones.ResourceName(router.Spec.Hostname)] = router.Name
+	}
+
+	var allErrs field.ErrorList
+	hostname := zones.ResourceName(routeSpec.Hostname)
+	if routerName, ok := routerNames[hostname]; ok {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hostname"), routeSpec.Hostname,
+			fmt.Sprintf("hostname is used by CappRouter %q", routerName)))
+	}
+
+	for i, additionalHostname := range routeSpec.AdditionalHostnames {
+		if routerName, ok := routerNames[zones.ResourceName(additionalHostname)]; ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("additionalHostnames").Index(i), additionalHostname,
+				fmt.Sprintf("hostname is used by CappRouter %q", routerName)))
+		}
+	}
+
+	for _, tag := range tags {
+		tagHostname := utils.GenerateTagHostname(tag, hostname)
+		if routerName, ok := routerNames[tagHostname]; ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("tagHostnamesEnabled"), routeSpec.TagHostnamesEnabled,
+				fmt.Sprintf("tag hostname %q is used by CappRouter %q", tagHostname, routerName)))
+		}
+	}
+
+	return allErrs
+}
+
+// getTagHostnameTags returns the tags which tag hostnames are generated for if they are enabled: the tags of the
+// revisions of a rollout if it is set, and the tags of the traffic targets otherwise.
+func getTagHostnameTags(spec cappv1alpha1.CappSpec) []string {
+	if !spec.RouteSpec.TagHostnamesEnabled {
+		return nil
+	}
+
+	if spec.RolloutSpec != nil {
+		return []string{rollout.CanaryTag, rollout.PreviewTag, rollout.PreviousTag}
+	}
+
+	var tags []string
+	for _, trafficTarget := range spec.RouteSpec.TrafficTargets {
+		if trafficTarget.Tag != "" {
+			tags = append(tags, trafficTarget.Tag)
+		}
+	}
+
+	return tags
+}
+
+// ValidateZoneHostname validates that a hostname fits the longest zone which it matches, or the default zone,
+// and that the namespace may use that zone.
+func ValidateZoneHostname(hostname, namespace string, zones utils.DNSZones, fldPath *field.Path) field.ErrorList {
+	zone := zones.Match(hostname)
+	if allErrs := ValidateHostname(hostname, zone.Name, fldPath); len(allErrs) > 0 {
+		return allErrs
+	}
+
+	if !zone.IsNamespaceAllowed(namespace) {
+		return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("namespace %q is not allowed to use zone %q", namespace, zone.Name))}
+	}
+
+	return nil
+}
+
+// ValidateHostname validates that a hostname is a valid DNS subdomain once the zone is
+// appended to it, and that it either ends with the zone on a label boundary or not at all.
+func ValidateHostname(hostname, zone string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	zoneWithoutTrailingDot := strings.TrimSuffix(zone, dot)
+
+	if hostname == zoneWithoutTrailingDot {
+		return append(allErrs, field.Invalid(fldPath, hostname, fmt.Sprintf("hostname must be a subdomain of zone %q and not the zone itself", zone)))
+	}
+
+	if strings.HasSuffix(hostname, zoneWithoutTrailingDot) && !strings.HasSuffix(hostname, dot+zoneWithoutTrailingDot) {
+		return append(allErrs, field.Invalid(fldPath, hostname, fmt.Sprintf("hostname does not fit in zone %q", zone)))
+	}
+
+	resourceName := utils.GenerateResourceName(hostname, zone)
+	for _, msg := range validation.IsDNS1123Subdomain(resourceName) {
+		allErrs = append(allErrs, field.Invalid(fldPath, hostname, fmt.Sprintf("%q is not a valid hostname: %s", resourceName, msg)))
+	}
+
+	return allErrs
+}
+
+// validateTrafficTargets validates that the deprecated TrafficTarget is not set together with TrafficTargets,
+// that every traffic target points at exactly one of a CappRevision, a Knative Revision or the latest revision,
+// and that the percentages of the traffic targets add up to 100.
+func validateTrafficTargets(routeSpec cappv1alpha1.RouteSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(routeSpec.TrafficTargets) > 0 && !equality.Semantic.DeepEqual(routeSpec.TrafficTarget, knativev1.TrafficTarget{}) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("trafficTarget"), "trafficTarget can not be set together with trafficTargets"))
+	}
+
+	var totalPercent int64
+	tags := map[string]bool{}
+	for i, trafficTarget := range routeSpec.TrafficTargets {
+		targetPath := fldPath.Child("trafficTargets").Index(i)
+
+		destinations := 0
+		if trafficTarget.CappRevisionNumber != 0 {
+			destinations++
+			if trafficTarget.CappRevisionNumber < 0 {
+				allErrs = append(allErrs, field.Invalid(targetPath.Child("cappRevisionNumber"), trafficTarget.CappRevisionNumber, "must be a positive revision number"))
+			}
+		}
+		if trafficTarget.RevisionName != "" {
+			destinations++
+		}
+		if trafficTarget.LatestRevision != nil && *trafficTarget.LatestRevision {
+			destinations++
+		}
+		if destinations != 1 {
+			allErrs = append(allErrs, field.Invalid(targetPath, trafficTarget,
+				"exactly one of cappRevisionNumber, revisionName or latestRevision must be set"))
+		}
+
+		if trafficTarget.ConfigurationName != "" {
+			allErrs = append(allErrs, field.Forbidden(targetPath.Child("configurationName"), "configurationName is not supported"))
+		}
+
+		if trafficTarget.Tag != "" {
+			if tags[trafficTarget.Tag] {
+				allErrs = append(allErrs, field.Duplicate(targetPath.Child("tag"), trafficTarget.Tag))
+			}
+			tags[trafficTarget.Tag] = true
+		}
+
+		if trafficTarget.Percent != nil {
+			if *trafficTarget.Percent < 0 || *trafficTarget.Percent > 100 {
+				allErrs = append(allErrs, field.Invalid(targetPath.Child("percent"), *trafficTarget.Percent, "must be between 0 and 100"))
+			}
+			totalPercent += *trafficTarget.Percent
+		}
+	}
+
+	if len(routeSpec.TrafficTargets) > 0 && totalPercent != 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficTargets"), totalPercent, "the percentages of the traffic targets must add up to 100"))
+	}
+
+	return allErrs
+}
+
+// validateRolloutSpec validates that a rollout is not set together with traffic targets, which it would override,
+// that only the block of its strategy is set, that its steps are increasing percentages and that the queries
+// of its analysis can be rendered.
+func validateRolloutSpec(spec cappv1alpha1.CappSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.RolloutSpec == nil {
+		return allErrs
+	}
+
+	rolloutPath := fldPath.Child("rolloutSpec")
+	if len(rmanagers.GetTrafficTargets(cappv1alpha1.Capp{Spec: spec})) > 0 {
+		allErrs = append(allErrs, field.Forbidden(rolloutPath, "rolloutSpec can not be set together with trafficTargets"))
+	}
+
+	canary := spec.RolloutSpec.Canary
+	canaryPath := rolloutPath.Child("canary")
+	if spec.RolloutSpec.Strategy != cappv1alpha1.RolloutStrategyBlueGreen && spec.RolloutSpec.BlueGreen != nil {
+		allErrs = append(allErrs, field.Forbidden(rolloutPath.Child("blueGreen"),
+			fmt.Sprintf("blueGreen can only be set when strategy is %q", cappv1alpha1.RolloutStrategyBlueGreen)))
+	}
+	if spec.RolloutSpec.Strategy != cappv1alpha1.RolloutStrategyCanary {
+		if canary != nil {
+			allErrs = append(allErrs, field.Forbidden(canaryPath, fmt.Sprintf("canary can only be set when strategy is %q", cappv1alpha1.RolloutStrategyCanary)))
+		}
+		return allErrs
+	}
+	if canary == nil {
+		return append(allErrs, field.Required(canaryPath, fmt.Sprintf("canary must be set when strategy is %q", cappv1alpha1.RolloutStrategyCanary)))
+	}
+
+	if len(canary.Steps) == 0 {
+		allErrs = append(allErrs, field.Required(canaryPath.Child("steps"), "at least one step must be set"))
+	}
+	var previousStep int64
+	for i, step := range canary.Steps {
+		if step < 1 || step > 99 {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("steps").Index(i), step, "must be between 1 and 99"))
+		} else if step <= previousStep {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("steps").Index(i), step, "steps must be in increasing order"))
+		}
+		previousStep = step
+	}
+
+	if canary.Analysis == nil {
+		return allErrs
+	}
+
+	analysisPath := canaryPath.Child("analysis")
+	if canary.Analysis.FailureLimit < 0 {
+		allErrs = append(allErrs, field.Invalid(analysisPath.Child("failureLimit"), canary.Analysis.FailureLimit, "must not be negative"))
+	}
+
+	names := map[string]bool{}
+	for i, metric := range canary.Analysis.Metrics {
+		metricPath := analysisPath.Child("metrics").Index(i)
+		if names[metric.Name] {
+			allErrs = append(allErrs, field.Duplicate(metricPath.Child("name"), metric.Name))
+		}
+		names[metric.Name] = true
+
+		if err := rollout.ValidateQuery(metric.Query); err != nil {
+			allErrs = append(allErrs, field.Invalid(metricPath.Child("query"), metric.Query, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateLogSpec validates that a LogSpec, if set, has a type and all the
+// fields required for that type.
+func validateLogSpec(logSpec cappv1alpha1.LogSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if logSpec == (cappv1alpha1.LogSpec{}) {
+		return allErrs
+	}
+
+	switch logSpec.Type {
+	case "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("type"), "type must be set when logSpec is specified"))
+	case rmanagers.LogTypeElastic:
+		if logSpec.Host == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("host"), "host is required for elastic logging"))
+		}
+		if logSpec.Index == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("index"), "index is required for elastic logging"))
+		}
+		if logSpec.PasswordSecret == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("passwordSecret"), "passwordSecret is required for elastic logging"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateVolumesSpec validates that every NFS volume has a unique and valid name.
+func validateVolumesSpec(volumesSpec cappv1alpha1.VolumesSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+
+	for i, nfsVolume := range volumesSpec.NFSVolumes {
+		namePath := fldPath.Child("nfsVolumes").Index(i).Child("name")
+
+		if names[nfsVolume.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, nfsVolume.Name))
+			continue
+		}
+		names[nfsVolume.Name] = true
+
+		for _, msg := range validation.IsDNS1123Subdomain(nfsVolume.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, nfsVolume.Name, msg))
+		}
+	}
+
+	return allErrs
+}
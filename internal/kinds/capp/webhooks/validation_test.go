@@ -0,0 +1,389 @@
+package webhooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testZone = "capp-zone.com."
+
+func newFakeClient() client.Client {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dns-config",
+			Namespace: utils.CappNS,
+		},
+		Data: map[string]string{
+			"zone":     testZone,
+			"cname":    "ingress.capp-zone.com.",
+			"provider": "dns-default",
+			"issuer":   "cert-issuer",
+			"zones":    "- name: internal.capp-zone.com.\n  namespaces: [test-ns]\n- name: restricted-zone.com.\n  namespaces: [other-ns]\n",
+		},
+	}
+
+	router := &cappv1alpha1.CappRouter{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-router", Namespace: "test-ns"},
+		Spec:       cappv1alpha1.CappRouterSpec{Hostname: "shop-web"},
+	}
+
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig, router).Build()
+}
+
+func newCapp() *cappv1alpha1.Capp {
+	return &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-capp",
+			Namespace: "test-ns",
+		},
+	}
+}
+
+func errorFields(errs field.ErrorList) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func newTaggedTrafficTargets(tag string) []cappv1alpha1.CappTrafficTarget {
+	latest := true
+	percent := int64(100)
+	return []cappv1alpha1.CappTrafficTarget{{TrafficTarget: knativev1.TrafficTarget{LatestRevision: &latest, Percent: &percent, Tag: tag}}}
+}
+
+func TestValidateCappRouteSpec(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+
+	tests := []struct {
+		name           string
+		routeSpec      cappv1alpha1.RouteSpec
+		expectedFields []string
+	}{
+		{name: "no hostname", routeSpec: cappv1alpha1.RouteSpec{}},
+		{name: "relative hostname", routeSpec: cappv1alpha1.RouteSpec{Hostname: "app", TlsEnabled: true}},
+		{name: "hostname in zone", routeSpec: cappv1alpha1.RouteSpec{Hostname: "app.capp-zone.com"}},
+		{
+			name:           "tls without hostname",
+			routeSpec:      cappv1alpha1.RouteSpec{TlsEnabled: true},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "hostname is the zone",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "capp-zone.com"},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "hostname ends with zone without a label boundary",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "appcapp-zone.com"},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "invalid hostname",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "App_1"},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "tag hostnames without hostname",
+			routeSpec:      cappv1alpha1.RouteSpec{TagHostnamesEnabled: true},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:      "tag hostnames",
+			routeSpec: cappv1alpha1.RouteSpec{Hostname: "app", TagHostnamesEnabled: true, TrafficTargets: newTaggedTrafficTargets("candidate")},
+		},
+		{
+			name: "tag hostname too long",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", TagHostnamesEnabled: true, TrafficTargets: newTaggedTrafficTargets(strings.Repeat("a", 60)),
+			},
+			expectedFields: []string{"spec.routeSpec.trafficTargets[0].tag"},
+		},
+		{
+			name:           "additional hostnames without hostname",
+			routeSpec:      cappv1alpha1.RouteSpec{AdditionalHostnames: []string{"legacy"}},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:      "additional hostnames",
+			routeSpec: cappv1alpha1.RouteSpec{Hostname: "app", AdditionalHostnames: []string{"legacy", "vanity.capp-zone.com"}},
+		},
+		{
+			name: "invalid and duplicate additional hostnames",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", AdditionalHostnames: []string{"app.capp-zone.com", "App_1", "legacy", "legacy"},
+			},
+			expectedFields: []string{
+				"spec.routeSpec.additionalHostnames[0]", "spec.routeSpec.additionalHostnames[1]", "spec.routeSpec.additionalHostnames[3]",
+			},
+		},
+		{
+			name: "additional hostname is a tag hostname",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", TagHostnamesEnabled: true, TrafficTargets: newTaggedTrafficTargets("candidate"),
+				AdditionalHostnames: []string{"candidate-app", "legacy"},
+			},
+			expectedFields: []string{"spec.routeSpec.additionalHostnames[0]"},
+		},
+		{
+			name: "additional hostname is a tag hostname without tag hostnames",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", TrafficTargets: newTaggedTrafficTargets("candidate"), AdditionalHostnames: []string{"candidate-app"},
+			},
+		},
+		{name: "hostname in a zone of the namespace", routeSpec: cappv1alpha1.RouteSpec{Hostname: "app.internal.capp-zone.com"}},
+		{
+			name:           "hostname is a zone of the namespace",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "internal.capp-zone.com"},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "hostname in a zone of another namespace",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "app.restricted-zone.com"},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "additional hostname in a zone of another namespace",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "app", AdditionalHostnames: []string{"app.internal.capp-zone.com", "app.restricted-zone.com"}},
+			expectedFields: []string{"spec.routeSpec.additionalHostnames[1]"},
+		},
+		{
+			name: "certificate issuer",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", TlsEnabled: true,
+				CertificateIssuerRef: &cappv1alpha1.CertificateIssuerReference{Name: "selfsigned", Kind: "Issuer", Group: "cert-manager.io"},
+			},
+		},
+		{
+			name: "certificate issuer without tls",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", CertificateIssuerRef: &cappv1alpha1.CertificateIssuerReference{Name: "selfsigned"},
+			},
+			expectedFields: []string{"spec.routeSpec.certificateIssuerRef"},
+		},
+		{
+			name: "unsupported kind of cert-manager issuer",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", TlsEnabled: true,
+				CertificateIssuerRef: &cappv1alpha1.CertificateIssuerReference{Name: "acme", Kind: "AcmeIssuer", Group: "cert-manager.io"},
+			},
+			expectedFields: []string{"spec.routeSpec.certificateIssuerRef.kind"},
+		},
+		{
+			name: "cluster issuer",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "app", TlsEnabled: true,
+				CertificateIssuerRef: &cappv1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+			},
+			expectedFields: []string{"spec.routeSpec.certificateIssuerRef.kind"},
+		},
+		{
+			name:           "hostname of a CappRouter",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "shop-web.capp-zone.com"},
+			expectedFields: []string{"spec.routeSpec.hostname"},
+		},
+		{
+			name:           "additional hostname of a CappRouter",
+			routeSpec:      cappv1alpha1.RouteSpec{Hostname: "app", AdditionalHostnames: []string{"www", "shop-web"}},
+			expectedFields: []string{"spec.routeSpec.additionalHostnames[1]"},
+		},
+		{
+			name: "tag hostname of a CappRouter",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname: "web", TagHostnamesEnabled: true, TrafficTargets: newTaggedTrafficTargets("shop"),
+			},
+			expectedFields: []string{"spec.routeSpec.tagHostnamesEnabled"},
+		},
+		{name: "cluster-local", routeSpec: cappv1alpha1.RouteSpec{Visibility: cappv1alpha1.RouteVisibilityClusterLocal}},
+		{
+			name: "cluster-local with hostnames",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Visibility: cappv1alpha1.RouteVisibilityClusterLocal, Hostname: "app", TlsEnabled: true, AdditionalHostnames: []string{"legacy"},
+			},
+			expectedFields: []string{"spec.routeSpec.hostname", "spec.routeSpec.tlsEnabled", "spec.routeSpec.additionalHostnames"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			capp := newCapp()
+			capp.Spec.RouteSpec = test.routeSpec
+			assert.Equal(t, test.expectedFields, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+		})
+	}
+}
+
+func TestValidateCappTrafficTargets(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+	percent := func(value int64) *int64 { return &value }
+	latest := true
+
+	capp := newCapp()
+	capp.Spec.RouteSpec.TrafficTargets = []cappv1alpha1.CappTrafficTarget{
+		{CappRevisionNumber: 1, TrafficTarget: knativev1.TrafficTarget{Percent: percent(80)}},
+		{TrafficTarget: knativev1.TrafficTarget{RevisionName: "test-capp-00002", Tag: "canary", Percent: percent(10)}},
+		{TrafficTarget: knativev1.TrafficTarget{LatestRevision: &latest, Percent: percent(10)}},
+	}
+	assert.Empty(t, ValidateCapp(ctx, k8sClient, capp))
+
+	capp.Spec.RouteSpec.TrafficTarget = knativev1.TrafficTarget{RevisionName: "test-capp-00001"}
+	capp.Spec.RouteSpec.TrafficTargets[0].RevisionName = "test-capp-00001"
+	capp.Spec.RouteSpec.TrafficTargets[2].Tag = "canary"
+	capp.Spec.RouteSpec.TrafficTargets[2].Percent = percent(20)
+	assert.Equal(t, []string{
+		"spec.routeSpec.trafficTarget",
+		"spec.routeSpec.trafficTargets[0]",
+		"spec.routeSpec.trafficTargets[2].tag",
+		"spec.routeSpec.trafficTargets",
+	}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+}
+
+func TestValidateCappRolloutSpec(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+
+	capp := newCapp()
+	capp.Spec.RolloutSpec = &cappv1alpha1.RolloutSpec{
+		Strategy: cappv1alpha1.RolloutStrategyCanary,
+		Canary: &cappv1alpha1.CanarySpec{
+			Steps: []int64{10, 50},
+			Analysis: &cappv1alpha1.AnalysisSpec{
+				Metrics: []cappv1alpha1.AnalysisMetric{
+					{Name: "error-rate", Query: `sum(rate(errors{revision="{{ .Revision }}"}[1m]))`, Max: resource.MustParse("0.05")},
+				},
+			},
+		},
+	}
+	assert.Empty(t, ValidateCapp(ctx, k8sClient, capp))
+
+	capp.Spec.RolloutSpec.Canary.Steps = []int64{50, 50, 100}
+	capp.Spec.RolloutSpec.Canary.Analysis.FailureLimit = -1
+	capp.Spec.RolloutSpec.Canary.Analysis.Metrics = append(capp.Spec.RolloutSpec.Canary.Analysis.Metrics,
+		cappv1alpha1.AnalysisMetric{Name: "error-rate", Query: "{{ .Pod }}"})
+	capp.Spec.RouteSpec.TrafficTargets = []cappv1alpha1.CappTrafficTarget{{CappRevisionNumber: 1}}
+	assert.Equal(t, []string{
+		"spec.routeSpec.trafficTargets",
+		"spec.rolloutSpec",
+		"spec.rolloutSpec.canary.steps[1]",
+		"spec.rolloutSpec.canary.steps[2]",
+		"spec.rolloutSpec.canary.analysis.failureLimit",
+		"spec.rolloutSpec.canary.analysis.metrics[1].name",
+		"spec.rolloutSpec.canary.analysis.metrics[1].query",
+	}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+
+	capp.Spec.RouteSpec.TrafficTargets = nil
+	capp.Spec.RolloutSpec.Canary = nil
+	assert.Equal(t, []string{"spec.rolloutSpec.canary"}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+
+	capp.Spec.RolloutSpec = &cappv1alpha1.RolloutSpec{
+		Strategy:  cappv1alpha1.RolloutStrategyBlueGreen,
+		BlueGreen: &cappv1alpha1.BlueGreenSpec{ScaleDownDelaySeconds: 300},
+	}
+	assert.Empty(t, ValidateCapp(ctx, k8sClient, capp))
+
+	capp.Spec.RolloutSpec.Canary = &cappv1alpha1.CanarySpec{Steps: []int64{10}}
+	assert.Equal(t, []string{"spec.rolloutSpec.canary"}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+
+	capp.Spec.RolloutSpec.Strategy = cappv1alpha1.RolloutStrategyCanary
+	assert.Equal(t, []string{"spec.rolloutSpec.blueGreen"}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+
+	// The tag hostnames of the revisions of a rollout can not be used as additional hostnames.
+	capp.Spec.RolloutSpec.Canary = nil
+	capp.Spec.RolloutSpec.Strategy = cappv1alpha1.RolloutStrategyBlueGreen
+	capp.Spec.RouteSpec = cappv1alpha1.RouteSpec{Hostname: "app", TagHostnamesEnabled: true, AdditionalHostnames: []string{"preview-app"}}
+	assert.Equal(t, []string{"spec.routeSpec.additionalHostnames[0]"}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+}
+
+func TestValidateCappLogSpec(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+
+	capp := newCapp()
+	capp.Spec.LogSpec = cappv1alpha1.LogSpec{Type: "elastic", Host: "1.2.3.4", Index: "main", PasswordSecret: "credentials"}
+	assert.Empty(t, ValidateCapp(ctx, k8sClient, capp))
+
+	capp.Spec.LogSpec = cappv1alpha1.LogSpec{Type: "elastic", User: "elastic"}
+	assert.Equal(t, []string{"spec.logSpec.host", "spec.logSpec.index", "spec.logSpec.passwordSecret"},
+		errorFields(ValidateCapp(ctx, k8sClient, capp)))
+
+	capp.Spec.LogSpec = cappv1alpha1.LogSpec{Host: "1.2.3.4"}
+	assert.Equal(t, []string{"spec.logSpec.type"}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+}
+
+func TestValidateCappVolumesSpec(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+
+	capp := newCapp()
+	capp.Spec.VolumesSpec.NFSVolumes = []cappv1alpha1.NFSVolume{{Name: "data"}, {Name: "logs"}}
+	assert.Empty(t, ValidateCapp(ctx, k8sClient, capp))
+
+	capp.Spec.VolumesSpec.NFSVolumes = append(capp.Spec.VolumesSpec.NFSVolumes, cappv1alpha1.NFSVolume{Name: "data"})
+	errs := ValidateCapp(ctx, k8sClient, capp)
+	assert.Equal(t, []string{"spec.volumesSpec.nfsVolumes[2].name"}, errorFields(errs))
+	assert.Equal(t, field.ErrorTypeDuplicate, errs[0].Type)
+}
+
+func TestValidateCappRevisionAnnotations(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+
+	capp := newCapp()
+	capp.Annotations = map[string]string{
+		cappv1alpha1.RollbackToRevisionAnnotation: "3",
+		cappv1alpha1.PromoteRevisionAnnotation:    "2",
+		cappv1alpha1.RevisionsToKeepAnnotation:    "5",
+		cappv1alpha1.RevisionMaxAgeAnnotation:     "720h",
+	}
+	assert.Empty(t, ValidateCapp(ctx, k8sClient, capp))
+
+	capp.Annotations[cappv1alpha1.PromoteRevisionAnnotation] = "preview"
+	capp.Annotations[cappv1alpha1.RevisionsToKeepAnnotation] = "0"
+	capp.Annotations[cappv1alpha1.RevisionMaxAgeAnnotation] = "30 days"
+	assert.Equal(t, []string{
+		"metadata.annotations[rcs.dana.io/promote-revision]",
+		"metadata.annotations[rcs.dana.io/revisions-to-keep]",
+		"metadata.annotations[rcs.dana.io/revision-max-age]",
+	}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+
+	capp.Annotations = map[string]string{}
+
+	for _, revision := range []string{"", "0", "-1", "latest"} {
+		capp.Annotations[cappv1alpha1.RollbackToRevisionAnnotation] = revision
+		assert.Equal(t, []string{"metadata.annotations[rcs.dana.io/rollback-to-revision]"}, errorFields(ValidateCapp(ctx, k8sClient, capp)))
+	}
+}
+
+func TestValidateUpdateOfDeletedCapp(t *testing.T) {
+	validator := CappValidator{Client: newFakeClient()}
+
+	capp := newCapp()
+	capp.Spec.RouteSpec.TlsEnabled = true
+	_, err := validator.ValidateUpdate(context.Background(), capp, capp)
+	assert.Error(t, err)
+
+	now := metav1.Now()
+	capp.DeletionTimestamp = &now
+	_, err = validator.ValidateUpdate(context.Background(), capp, capp)
+	assert.NoError(t, err)
+}
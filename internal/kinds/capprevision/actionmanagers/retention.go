@@ -0,0 +1,122 @@
+package actionmanagers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+// DefaultRevisionsToKeep is the number of unpinned CappRevisions kept for a Capp
+// when no other retention is configured.
+const DefaultRevisionsToKeep = 10
+
+// RetentionPolicy defines which CappRevisions of a Capp are kept.
+type RetentionPolicy struct {
+	// RevisionsToKeep is the maximum number of unpinned CappRevisions kept for a Capp.
+	RevisionsToKeep int
+
+	// MaxAge is the age after which unpinned CappRevisions are pruned. Zero disables age-based pruning.
+	MaxAge time.Duration
+}
+
+// Validate returns an error if the RetentionPolicy does not keep any CappRevision or has a negative MaxAge.
+func (p RetentionPolicy) Validate() error {
+	if p.RevisionsToKeep < 1 {
+		return fmt.Errorf("the number of CappRevisions to keep must be positive, got %d", p.RevisionsToKeep)
+	}
+	if p.MaxAge < 0 {
+		return fmt.Errorf("the maximum age of CappRevisions must not be negative, got %s", p.MaxAge)
+	}
+
+	return nil
+}
+
+// ResolveRetentionPolicy returns the RetentionPolicy of a Capp, overriding the given defaults
+// with the retention annotations set on the Capp. Invalid annotation values are ignored.
+func ResolveRetentionPolicy(capp cappv1alpha1.Capp, defaults RetentionPolicy, logger logr.Logger) RetentionPolicy {
+	policy := defaults
+
+	if value, ok := capp.Annotations[cappv1alpha1.RevisionsToKeepAnnotation]; ok {
+		revisionsToKeep, err := strconv.Atoi(value)
+		if err != nil || revisionsToKeep < 1 {
+			logger.Info("Ignoring invalid annotation", "annotation", cappv1alpha1.RevisionsToKeepAnnotation, "value", value)
+		} else {
+			policy.RevisionsToKeep = revisionsToKeep
+		}
+	}
+
+	if value, ok := capp.Annotations[cappv1alpha1.RevisionMaxAgeAnnotation]; ok {
+		maxAge, err := time.ParseDuration(value)
+		if err != nil || maxAge < 0 {
+			logger.Info("Ignoring invalid annotation", "annotation", cappv1alpha1.RevisionMaxAgeAnnotation, "value", value)
+		} else {
+			policy.MaxAge = maxAge
+		}
+	}
+
+	return policy
+}
+
+// isPinned returns a boolean indicating whether a CappRevision is pinned and should never be pruned.
+func isPinned(cappRevision cappv1alpha1.CappRevision) bool {
+	return cappRevision.Labels[cappv1alpha1.PinnedRevisionLabel] == "true"
+}
+
+// revisionsToPrune returns the CappRevisions, sorted from newest to oldest, which exceed the RetentionPolicy.
+// Pinned CappRevisions are never pruned and do not count towards RevisionsToKeep. The newest CappRevision
+// is never pruned by age if it is the current revision of the Capp; if a new CappRevision is about to be
+// created then room is made for it.
+func revisionsToPrune(cappRevisions []cappv1alpha1.CappRevision, policy RetentionPolicy, creatingRevision bool, now time.Time) []cappv1alpha1.CappRevision {
+	var toPrune []cappv1alpha1.CappRevision
+
+	revisionsToKeep := policy.RevisionsToKeep
+	if creatingRevision {
+		revisionsToKeep--
+	}
+
+	kept := 0
+	for i, cappRevision := range cappRevisions {
+		if isPinned(cappRevision) {
+			continue
+		}
+
+		isCurrent := i == 0 && !creatingRevision
+		tooOld := policy.MaxAge > 0 && now.Sub(cappRevision.CreationTimestamp.Time) > policy.MaxAge
+		if isCurrent || (kept < revisionsToKeep && !tooOld) {
+			kept++
+			continue
+		}
+
+		toPrune = append(toPrune, cappRevision)
+	}
+
+	return toPrune
+}
+
+// NextPruneAfter returns the duration after which the next unpinned CappRevision of a Capp becomes older than
+// the MaxAge of the RetentionPolicy, so that it is pruned even if the Capp is not changed until then. The current
+// CappRevision is never pruned by age, and zero is returned if age-based pruning is disabled.
+func NextPruneAfter(cappRevisions []cappv1alpha1.CappRevision, policy RetentionPolicy, now time.Time) time.Duration {
+	if policy.MaxAge <= 0 {
+		return 0
+	}
+
+	sortByCreationTime(cappRevisions)
+
+	next := policy.MaxAge
+	for i, cappRevision := range cappRevisions {
+		if i == 0 || isPinned(cappRevision) {
+			continue
+		}
+
+		pruneAfter := cappRevision.CreationTimestamp.Add(policy.MaxAge).Sub(now)
+		if pruneAfter > 0 && pruneAfter < next {
+			next = pruneAfter
+		}
+	}
+
+	return next
+}
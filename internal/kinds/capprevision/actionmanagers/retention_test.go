@@ -0,0 +1,123 @@
+package actionmanagers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var testNow = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+// newAgedRevisions returns CappRevisions sorted from newest to oldest, where the CappRevision
+// at index i is i days old and has revision number count-i.
+func newAgedRevisions(count int) []cappv1alpha1.CappRevision {
+	cappRevisions := make([]cappv1alpha1.CappRevision, 0, count)
+	for i := 0; i < count; i++ {
+		cappRevisions = append(cappRevisions, cappv1alpha1.CappRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("test-capp-%05d", count-i),
+				Namespace:         "test-ns",
+				CreationTimestamp: metav1.NewTime(testNow.Add(-time.Duration(i) * 24 * time.Hour)),
+			},
+			Spec: cappv1alpha1.CappRevisionSpec{RevisionNumber: count - i},
+		})
+	}
+	return cappRevisions
+}
+
+func revisionNames(cappRevisions []cappv1alpha1.CappRevision) []string {
+	var names []string
+	for _, cappRevision := range cappRevisions {
+		names = append(names, cappRevision.Name)
+	}
+	return names
+}
+
+func TestRevisionsToPrune(t *testing.T) {
+	cappRevisions := newAgedRevisions(5)
+
+	policy := RetentionPolicy{RevisionsToKeep: 3}
+	assert.Equal(t, []string{"test-capp-00002", "test-capp-00001"}, revisionNames(revisionsToPrune(cappRevisions, policy, false, testNow)))
+	assert.Equal(t, []string{"test-capp-00003", "test-capp-00002", "test-capp-00001"}, revisionNames(revisionsToPrune(cappRevisions, policy, true, testNow)))
+
+	cappRevisions[3].Labels = map[string]string{cappv1alpha1.PinnedRevisionLabel: "true"}
+	assert.Equal(t, []string{"test-capp-00001"}, revisionNames(revisionsToPrune(cappRevisions, policy, false, testNow)))
+
+	policy = RetentionPolicy{RevisionsToKeep: 10, MaxAge: 36 * time.Hour}
+	assert.Equal(t, []string{"test-capp-00003", "test-capp-00001"}, revisionNames(revisionsToPrune(cappRevisions, policy, false, testNow)))
+
+	policy = RetentionPolicy{RevisionsToKeep: 10, MaxAge: time.Hour}
+	assert.Equal(t, []string{"test-capp-00004"}, revisionNames(revisionsToPrune(cappRevisions[1:2], policy, true, testNow)))
+	assert.Empty(t, revisionsToPrune(cappRevisions[1:2], policy, false, testNow))
+}
+
+func TestNextPruneAfter(t *testing.T) {
+	cappRevisions := newAgedRevisions(3)
+
+	assert.Zero(t, NextPruneAfter(cappRevisions, RetentionPolicy{RevisionsToKeep: 10}, testNow))
+	assert.Equal(t, 12*time.Hour, NextPruneAfter(cappRevisions, RetentionPolicy{RevisionsToKeep: 10, MaxAge: 36 * time.Hour}, testNow))
+
+	// The current CappRevision is never pruned by age, so the MaxAge is used when no other CappRevision is left.
+	assert.Equal(t, time.Hour, NextPruneAfter(cappRevisions[:1], RetentionPolicy{RevisionsToKeep: 10, MaxAge: time.Hour}, testNow))
+
+	cappRevisions[1].Labels = map[string]string{cappv1alpha1.PinnedRevisionLabel: "true"}
+	assert.Equal(t, 48*time.Hour, NextPruneAfter(cappRevisions, RetentionPolicy{RevisionsToKeep: 10, MaxAge: 96 * time.Hour}, testNow))
+}
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	assert.NoError(t, RetentionPolicy{RevisionsToKeep: DefaultRevisionsToKeep}.Validate())
+	assert.NoError(t, RetentionPolicy{RevisionsToKeep: 1, MaxAge: time.Hour}.Validate())
+	assert.Error(t, RetentionPolicy{RevisionsToKeep: 0}.Validate())
+	assert.Error(t, RetentionPolicy{RevisionsToKeep: -1}.Validate())
+	assert.Error(t, RetentionPolicy{RevisionsToKeep: 1, MaxAge: -time.Hour}.Validate())
+}
+
+func TestResolveRetentionPolicy(t *testing.T) {
+	defaults := RetentionPolicy{RevisionsToKeep: DefaultRevisionsToKeep}
+
+	capp := cappv1alpha1.Capp{}
+	assert.Equal(t, defaults, ResolveRetentionPolicy(capp, defaults, logr.Discard()))
+
+	capp.Annotations = map[string]string{
+		cappv1alpha1.RevisionsToKeepAnnotation: "3",
+		cappv1alpha1.RevisionMaxAgeAnnotation:  "24h",
+	}
+	assert.Equal(t, RetentionPolicy{RevisionsToKeep: 3, MaxAge: 24 * time.Hour}, ResolveRetentionPolicy(capp, defaults, logr.Discard()))
+
+	capp.Annotations[cappv1alpha1.RevisionsToKeepAnnotation] = "none"
+	assert.Equal(t, DefaultRevisionsToKeep, ResolveRetentionPolicy(capp, defaults, logr.Discard()).RevisionsToKeep)
+}
+
+func TestHandleCappUpdatePrunesRevisions(t *testing.T) {
+	ctx := context.Background()
+	capp := newRollbackCapp("1")
+	delete(capp.Annotations, cappv1alpha1.RollbackToRevisionAnnotation)
+	k8sClient := newRollbackClient(capp)
+	eventRecorder := record.NewFakeRecorder(1)
+
+	cappRevisions := newAgedRevisions(3)
+	for i := range cappRevisions {
+		assert.NoError(t, k8sClient.Create(ctx, &cappRevisions[i]))
+	}
+
+	policy := RetentionPolicy{RevisionsToKeep: 2}
+	assert.NoError(t, HandleCappUpdate(ctx, k8sClient, *capp, logr.Discard(), eventRecorder, cappRevisions, policy))
+
+	remainingRevisions := cappv1alpha1.CappRevisionList{}
+	assert.NoError(t, k8sClient.List(ctx, &remainingRevisions, client.InNamespace("test-ns")))
+	assert.ElementsMatch(t, []string{"test-capp-00003", "test-capp-00004"}, revisionNames(remainingRevisions.Items))
+	for _, revision := range remainingRevisions.Items {
+		if revision.Name == "test-capp-00004" {
+			assert.Contains(t, revision.Status.ChangedFields, "spec.scaleMetric")
+		}
+	}
+	assert.Contains(t, <-eventRecorder.Events, eventCappRevisionsPruned)
+}
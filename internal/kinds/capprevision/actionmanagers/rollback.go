@@ -0,0 +1,98 @@
+package actionmanagers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventCappRolledBack     = "CappRolledBack"
+	eventCappRollbackFailed = "CappRollbackFailed"
+)
+
+// IsRollbackRequested returns a boolean indicating whether a rollback was requested on the Capp.
+func IsRollbackRequested(capp cappv1alpha1.Capp) bool {
+	_, ok := capp.Annotations[cappv1alpha1.RollbackToRevisionAnnotation]
+	return ok
+}
+
+// HandleCappRollback copies the template of the CappRevision requested in the rollback annotation back onto the Capp
+// and removes the annotation, setting the change cause of the Capp to the rollback. The control annotations of the
+// Capp keep their current values. The update of the Capp in turn records the rollback as a new CappRevision.
+// If the requested CappRevision does not exist, the annotation is removed and a warning event is emitted.
+func HandleCappRollback(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, eventRecorder record.EventRecorder, cappRevisions []cappv1alpha1.CappRevision) error {
+	requestedRevision := capp.Annotations[cappv1alpha1.RollbackToRevisionAnnotation]
+	cappRevision, err := findRevision(requestedRevision, cappRevisions)
+	if err != nil {
+		logger.Error(err, "failed to roll back Capp")
+		eventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappRollbackFailed, fmt.Sprintf("Failed to roll back Capp %q: %s", capp.Name, err.Error()))
+
+		delete(capp.Annotations, cappv1alpha1.RollbackToRevisionAnnotation)
+		return k8sClient.Update(ctx, &capp)
+	}
+
+	template := cappRevision.Spec.CappTemplate.DeepCopy()
+	capp.Spec = template.Spec
+	capp.Labels = template.Labels
+	capp.Annotations = rolledBackAnnotations(capp.Annotations, template.Annotations)
+	capp.Annotations[cappv1alpha1.ChangeCauseAnnotation] = fmt.Sprintf("rollback to CappRevision %q", cappRevision.Name)
+
+	logger.Info(fmt.Sprintf("Rolling back Capp to CappRevision %q", cappRevision.Name))
+	if err := k8sClient.Update(ctx, &capp); err != nil {
+		return err
+	}
+
+	eventRecorder.Event(&capp, corev1.EventTypeNormal, eventCappRolledBack,
+		fmt.Sprintf("Capp %q was rolled back to CappRevision %q", capp.Name, cappRevision.Name))
+	return nil
+}
+
+// rolledBackAnnotations returns the annotations of a CappRevision together with the current control annotations
+// of the Capp, other than the rollback annotation, which replace those of the CappRevision.
+func rolledBackAnnotations(current, revision map[string]string) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range revision {
+		if !isControlAnnotation(key) {
+			annotations[key] = value
+		}
+	}
+
+	for key, value := range current {
+		if isControlAnnotation(key) {
+			annotations[key] = value
+		}
+	}
+	delete(annotations, cappv1alpha1.RollbackToRevisionAnnotation)
+
+	return annotations
+}
+
+// isControlAnnotation returns a boolean indicating whether an annotation controls the operator or records the
+// configuration applied by kubectl, rather than describing the Capp, so that a rollback does not restore it.
+func isControlAnnotation(key string) bool {
+	return strings.HasPrefix(key, cappv1alpha1.GroupVersion.Group+"/") || key == corev1.LastAppliedConfigAnnotation
+}
+
+// findRevision returns the CappRevision whose RevisionNumber matches the requested revision.
+func findRevision(requestedRevision string, cappRevisions []cappv1alpha1.CappRevision) (cappv1alpha1.CappRevision, error) {
+	revisionNumber, err := strconv.Atoi(requestedRevision)
+	if err != nil {
+		return cappv1alpha1.CappRevision{}, fmt.Errorf("invalid revision number %q in annotation %q", requestedRevision, cappv1alpha1.RollbackToRevisionAnnotation)
+	}
+
+	for _, cappRevision := range cappRevisions {
+		if cappRevision.Spec.RevisionNumber == revisionNumber {
+			return cappRevision, nil
+		}
+	}
+
+	return cappv1alpha1.CappRevision{}, fmt.Errorf("CappRevision with revision number %d does not exist", revisionNumber)
+}
@@ -0,0 +1,112 @@
+package actionmanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newRollbackCapp(revision string) *cappv1alpha1.Capp {
+	return &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-capp",
+			Namespace:   "test-ns",
+			Labels:      map[string]string{"version": "2"},
+			Annotations: map[string]string{cappv1alpha1.RollbackToRevisionAnnotation: revision},
+		},
+		Spec: cappv1alpha1.CappSpec{ScaleMetric: "rps"},
+	}
+}
+
+func newRollbackRevisions() []cappv1alpha1.CappRevision {
+	return []cappv1alpha1.CappRevision{{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp-v1", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappRevisionSpec{
+			RevisionNumber: 1,
+			CappTemplate: cappv1alpha1.CappTemplate{
+				Spec:        cappv1alpha1.CappSpec{ScaleMetric: "cpu"},
+				Labels:      map[string]string{"version": "1"},
+				Annotations: map[string]string{"team": "a"},
+			},
+		},
+	}}
+}
+
+func newRollbackClient(capp *cappv1alpha1.Capp) client.Client {
+	s := runtime.NewScheme()
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = knativev1.AddToScheme(s)
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(capp).WithStatusSubresource(&cappv1alpha1.CappRevision{}).Build()
+}
+
+func TestHandleCappRollback(t *testing.T) {
+	ctx := context.Background()
+	capp := newRollbackCapp("1")
+	k8sClient := newRollbackClient(capp)
+	eventRecorder := record.NewFakeRecorder(1)
+
+	assert.NoError(t, HandleCappRollback(ctx, k8sClient, *capp, logr.Discard(), eventRecorder, newRollbackRevisions()))
+
+	rolledBackCapp := cappv1alpha1.Capp{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(capp), &rolledBackCapp))
+	assert.Equal(t, "cpu", rolledBackCapp.Spec.ScaleMetric)
+	assert.Equal(t, map[string]string{"version": "1"}, rolledBackCapp.Labels)
+	assert.Equal(t, map[string]string{"team": "a", cappv1alpha1.ChangeCauseAnnotation: `rollback to CappRevision "test-capp-v1"`}, rolledBackCapp.Annotations)
+	assert.Contains(t, <-eventRecorder.Events, eventCappRolledBack)
+}
+
+func TestHandleCappRollbackKeepsControlAnnotations(t *testing.T) {
+	ctx := context.Background()
+	capp := newRollbackCapp("1")
+	capp.Annotations[cappv1alpha1.RevisionsToKeepAnnotation] = "5"
+	capp.Annotations[corev1.LastAppliedConfigAnnotation] = "current"
+	k8sClient := newRollbackClient(capp)
+
+	cappRevisions := newRollbackRevisions()
+	cappRevisions[0].Spec.CappTemplate.Annotations = map[string]string{
+		"team":                                    "a",
+		cappv1alpha1.RevisionsToKeepAnnotation:    "2",
+		cappv1alpha1.RevisionMaxAgeAnnotation:     "24h",
+		corev1.LastAppliedConfigAnnotation:        "stale",
+		cappv1alpha1.RollbackToRevisionAnnotation: "3",
+	}
+	assert.NoError(t, HandleCappRollback(ctx, k8sClient, *capp, logr.Discard(), record.NewFakeRecorder(1), cappRevisions))
+
+	// The control annotations keep their current values, and those which the Capp does not set are not restored.
+	rolledBackCapp := cappv1alpha1.Capp{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(capp), &rolledBackCapp))
+	assert.Equal(t, map[string]string{
+		"team":                                 "a",
+		cappv1alpha1.RevisionsToKeepAnnotation: "5",
+		corev1.LastAppliedConfigAnnotation:     "current",
+		cappv1alpha1.ChangeCauseAnnotation:     `rollback to CappRevision "test-capp-v1"`,
+	}, rolledBackCapp.Annotations)
+}
+
+func TestHandleCappRollbackFailure(t *testing.T) {
+	ctx := context.Background()
+
+	for _, revision := range []string{"5", "latest"} {
+		capp := newRollbackCapp(revision)
+		k8sClient := newRollbackClient(capp)
+		eventRecorder := record.NewFakeRecorder(1)
+
+		assert.NoError(t, HandleCappRollback(ctx, k8sClient, *capp, logr.Discard(), eventRecorder, newRollbackRevisions()))
+
+		unchangedCapp := cappv1alpha1.Capp{}
+		assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(capp), &unchangedCapp))
+		assert.Equal(t, "rps", unchangedCapp.Spec.ScaleMetric)
+		assert.False(t, IsRollbackRequested(unchangedCapp))
+		assert.Contains(t, <-eventRecorder.Events, eventCappRollbackFailed)
+	}
+}
@@ -0,0 +1,157 @@
+package actionmanagers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// buildCappRevisionStatus returns the status of a CappRevision created for the current state of a Capp,
+// listing the fields which changed compared with the previous CappRevision, if there is one, and who made the change.
+func buildCappRevisionStatus(capp cappv1alpha1.Capp, previousRevision *cappv1alpha1.CappRevision) cappv1alpha1.CappRevisionStatus {
+	status := cappv1alpha1.CappRevisionStatus{
+		ChangeCause: capp.Annotations[cappv1alpha1.ChangeCauseAnnotation],
+		ModifiedBy:  getModifiedBy(capp),
+	}
+
+	if previousRevision != nil {
+		status.ChangedFields = changedFields(capp, previousRevision.Spec.CappTemplate)
+	}
+
+	return status
+}
+
+// getModifiedBy returns who made the last change to a Capp from its LastModifiedByAnnotation,
+// or nil if the annotation is not set or is malformed.
+func getModifiedBy(capp cappv1alpha1.Capp) *cappv1alpha1.ModifiedBy {
+	value, ok := capp.Annotations[cappv1alpha1.LastModifiedByAnnotation]
+	if !ok {
+		return nil
+	}
+
+	modifiedBy := &cappv1alpha1.ModifiedBy{}
+	if err := json.Unmarshal([]byte(value), modifiedBy); err != nil {
+		return nil
+	}
+
+	return modifiedBy
+}
+
+// changedFields returns the paths of the top-level fields of a Capp which differ from a CappTemplate.
+// The LastModifiedByAnnotation is ignored since it changes together with every other field.
+func changedFields(capp cappv1alpha1.Capp, template cappv1alpha1.CappTemplate) []string {
+	var fields []string
+
+	fieldPairs := []struct {
+		path              string
+		current, previous interface{}
+	}{
+		{"metadata.labels", capp.Labels, template.Labels},
+		{"metadata.annotations", utils.WithoutLastModifiedBy(capp.Annotations), utils.WithoutLastModifiedBy(template.Annotations)},
+		{"spec.scaleMetric", capp.Spec.ScaleMetric, template.Spec.ScaleMetric},
+		{"spec.site", capp.Spec.Site, template.Spec.Site},
+		{"spec.state", capp.Spec.State, template.Spec.State},
+		{"spec.configurationSpec", capp.Spec.ConfigurationSpec, template.Spec.ConfigurationSpec},
+		{"spec.routeSpec", capp.Spec.RouteSpec, template.Spec.RouteSpec},
+		{"spec.logSpec", capp.Spec.LogSpec, template.Spec.LogSpec},
+		{"spec.volumesSpec", capp.Spec.VolumesSpec, template.Spec.VolumesSpec},
+		{"spec.rolloutSpec", capp.Spec.RolloutSpec, template.Spec.RolloutSpec},
+	}
+
+	for _, pair := range fieldPairs {
+		if !equality.Semantic.DeepEqual(pair.current, pair.previous) {
+			fields = append(fields, pair.path)
+		}
+	}
+
+	return fields
+}
+
+// SyncCappRevisionStatus links the newest CappRevision of a Capp to the Knative Revision serving the Capp once
+// the change it records was applied, and marks the linked Knative Revisions which became Ready.
+// The CappRevisions are expected to be sorted from newest to oldest.
+func SyncCappRevisionStatus(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, cappRevisions []cappv1alpha1.CappRevision) error {
+	if len(cappRevisions) == 0 {
+		return nil
+	}
+
+	latestRevision := &cappRevisions[0]
+	if latestRevision.Status.KnativeRevisionName == "" && isEqual(capp, *latestRevision) {
+		knativeRevisionName, err := getAppliedKnativeRevisionName(ctx, k8sClient, capp)
+		if err != nil {
+			return err
+		}
+		if knativeRevisionName != "" {
+			latestRevision.Status.KnativeRevisionName = knativeRevisionName
+			if err := updateCappRevisionStatus(ctx, k8sClient, logger, latestRevision); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range cappRevisions {
+		cappRevision := &cappRevisions[i]
+		if cappRevision.Status.KnativeRevisionName == "" || cappRevision.Status.KnativeRevisionReady {
+			continue
+		}
+
+		knativeRevision := knativev1.Revision{}
+		key := types.NamespacedName{Name: cappRevision.Status.KnativeRevisionName, Namespace: cappRevision.Namespace}
+		if err := k8sClient.Get(ctx, key, &knativeRevision); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to get Knative Revision %q: %w", key.Name, err)
+			}
+			continue
+		}
+
+		if knativeRevision.IsReady() {
+			cappRevision.Status.KnativeRevisionReady = true
+			if err := updateCappRevisionStatus(ctx, k8sClient, logger, cappRevision); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getAppliedKnativeRevisionName returns the name of the latest Knative Revision created for the Knative Service
+// of a Capp, once both the Capp controller and Knative have observed the current generation. Otherwise, it
+// returns an empty string.
+func getAppliedKnativeRevisionName(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp) (string, error) {
+	if capp.Status.ObservedGeneration != capp.Generation {
+		return "", nil
+	}
+
+	knativeService := knativev1.Service{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: capp.Name, Namespace: capp.Namespace}, &knativeService); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to get Knative Service %q: %w", capp.Name, err)
+		}
+		return "", nil
+	}
+
+	if knativeService.Status.ObservedGeneration != knativeService.Generation {
+		return "", nil
+	}
+
+	return knativeService.Status.LatestCreatedRevisionName, nil
+}
+
+// updateCappRevisionStatus updates the status of a CappRevision, ignoring CappRevisions which were deleted meanwhile.
+func updateCappRevisionStatus(ctx context.Context, k8sClient client.Client, logger logr.Logger, cappRevision *cappv1alpha1.CappRevision) error {
+	if err := k8sClient.Status().Update(ctx, cappRevision); client.IgnoreNotFound(err) != nil {
+		logger.Error(err, fmt.Sprintf("Failed to update status of CappRevision %q.", cappRevision.Name))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package actionmanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestBuildCappRevisionStatus(t *testing.T) {
+	capp := newRollbackCapp("1")
+	capp.Annotations = map[string]string{
+		cappv1alpha1.ChangeCauseAnnotation:    "bump scale metric",
+		cappv1alpha1.LastModifiedByAnnotation: `{"username":"alice","groups":["devs"],"timestamp":"2024-01-31T00:00:00Z"}`,
+	}
+
+	status := buildCappRevisionStatus(*capp, nil)
+	assert.Equal(t, "bump scale metric", status.ChangeCause)
+	assert.Empty(t, status.ChangedFields)
+	assert.Equal(t, "alice", status.ModifiedBy.Username)
+	assert.Equal(t, []string{"devs"}, status.ModifiedBy.Groups)
+
+	previousRevision := cappv1alpha1.CappRevision{Spec: cappv1alpha1.CappRevisionSpec{CappTemplate: cappv1alpha1.CappTemplate{
+		Spec:        cappv1alpha1.CappSpec{ScaleMetric: "cpu"},
+		Labels:      capp.Labels,
+		Annotations: map[string]string{cappv1alpha1.ChangeCauseAnnotation: "bump scale metric"},
+	}}}
+	assert.Equal(t, []string{"spec.scaleMetric"}, buildCappRevisionStatus(*capp, &previousRevision).ChangedFields)
+
+	capp.Spec.RolloutSpec = &cappv1alpha1.RolloutSpec{Strategy: cappv1alpha1.RolloutStrategyBlueGreen}
+	assert.Equal(t, []string{"spec.scaleMetric", "spec.rolloutSpec"}, buildCappRevisionStatus(*capp, &previousRevision).ChangedFields)
+}
+
+func TestSyncCappRevisionStatus(t *testing.T) {
+	ctx := context.Background()
+	capp := newRollbackCapp("1")
+	capp.Annotations = nil
+	capp.Generation = 2
+	capp.Status.ObservedGeneration = 2
+	k8sClient := newRollbackClient(capp)
+
+	cappRevision := cappv1alpha1.CappRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp-00001", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappRevisionSpec{RevisionNumber: 1, CappTemplate: cappv1alpha1.CappTemplate{
+			Spec:   capp.Spec,
+			Labels: capp.Labels,
+		}},
+	}
+	assert.NoError(t, k8sClient.Create(ctx, &cappRevision))
+
+	knativeService := knativev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	knativeService.Status.LatestCreatedRevisionName = "test-capp-00002"
+	assert.NoError(t, k8sClient.Create(ctx, &knativeService))
+
+	knativeRevision := knativev1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "test-capp-00002", Namespace: "test-ns"}}
+	assert.NoError(t, k8sClient.Create(ctx, &knativeRevision))
+
+	cappRevisions := []cappv1alpha1.CappRevision{cappRevision}
+	assert.NoError(t, SyncCappRevisionStatus(ctx, k8sClient, *capp, logr.Discard(), cappRevisions))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(&cappRevision), &cappRevision))
+	assert.Equal(t, "test-capp-00002", cappRevision.Status.KnativeRevisionName)
+	assert.False(t, cappRevision.Status.KnativeRevisionReady)
+
+	knativeRevision.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+	assert.NoError(t, k8sClient.Update(ctx, &knativeRevision))
+
+	cappRevisions = []cappv1alpha1.CappRevision{cappRevision}
+	assert.NoError(t, SyncCappRevisionStatus(ctx, k8sClient, *capp, logr.Discard(), cappRevisions))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(&cappRevision), &cappRevision))
+	assert.True(t, cappRevision.Status.KnativeRevisionReady)
+}
@@ -2,31 +2,22 @@ package actionmanagers
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/dana-team/container-app-operator/internal/kinds/capprevision/adapters"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const revisionsToKeep = 10
-
-// splitRevisionsAtIndex splits a slice of CappRevisions into two slices:
-// one containing the elements before the specified index (exclusive),
-// and one containing the elements after the specified index (inclusive).
-// If the index is out of bounds, it adjusts to return appropriate slices.
-func splitRevisionsAtIndex(revisions []cappv1alpha1.CappRevision, index int) ([]cappv1alpha1.CappRevision, []cappv1alpha1.CappRevision) {
-	if index <= 0 {
-		return []cappv1alpha1.CappRevision{}, revisions
-	}
-	if index >= len(revisions) {
-		return revisions, []cappv1alpha1.CappRevision{}
-	}
-	return revisions[:index], revisions[index:]
-}
+const eventCappRevisionsPruned = "CappRevisionsPruned"
 
 // sortByCreationTime sorts a slice of CappRevision by the CreatedAt field.
 func sortByCreationTime(cappRevisions []cappv1alpha1.CappRevision) {
@@ -59,26 +50,29 @@ func isEqual(capp cappv1alpha1.Capp, revision cappv1alpha1.CappRevision) bool {
 }
 
 // HandleCappUpdate manages the flow of CappRevision when a Capp is updated. It ensures that a CappRevision is created for every update.
-// It also maintains a limit of only revisionsToKeep CappRevisions in the same namespace as the Capp.
-func HandleCappUpdate(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, cappRevisions []cappv1alpha1.CappRevision) error {
+// It also prunes the CappRevisions of the Capp which exceed the given RetentionPolicy and emits an event listing them.
+func HandleCappUpdate(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, eventRecorder record.EventRecorder, cappRevisions []cappv1alpha1.CappRevision, policy RetentionPolicy) error {
 	sortByCreationTime(cappRevisions)
-	numOfRevisions := len(cappRevisions)
 
 	latestRevision := cappRevisions[0]
-	if isEqual(capp, latestRevision) {
-		return nil
-	}
+	creatingRevision := !isEqual(capp, latestRevision)
 
-	if numOfRevisions < revisionsToKeep {
-		return adapters.CreateCappRevision(ctx, k8sClient, logger, capp, cappRevisions[0].Spec.RevisionNumber+1)
+	prunedRevisions := revisionsToPrune(cappRevisions, policy, creatingRevision, time.Now())
+	if len(prunedRevisions) > 0 {
+		names := make([]string, 0, len(prunedRevisions))
+		for _, revision := range prunedRevisions {
+			if err := adapters.DeleteCappRevision(ctx, k8sClient, logger, &revision); err != nil {
+				return err
+			}
+			names = append(names, revision.Name)
+		}
+		eventRecorder.Event(&capp, corev1.EventTypeNormal, eventCappRevisionsPruned,
+			fmt.Sprintf("Pruned CappRevisions %s of Capp %q", strings.Join(names, ", "), capp.Name))
 	}
-	relevantRevision, revisionsToDelete := splitRevisionsAtIndex(cappRevisions, revisionsToKeep-1)
 
-	for _, revision := range revisionsToDelete {
-		if err := adapters.DeleteCappRevision(ctx, k8sClient, logger, &revision); err != nil {
-			return err
-		}
+	if !creatingRevision {
+		return nil
 	}
 
-	return adapters.CreateCappRevision(ctx, k8sClient, logger, capp, relevantRevision[0].Spec.RevisionNumber+1)
+	return adapters.CreateCappRevision(ctx, k8sClient, logger, capp, latestRevision.Spec.RevisionNumber+1, buildCappRevisionStatus(capp, &latestRevision))
 }
@@ -6,9 +6,11 @@ import (
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/util/retry"
 	"knative.dev/pkg/kmeta"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -44,8 +46,9 @@ func GetCappRevisions(ctx context.Context, r client.Client, capp cappv1alpha1.Ca
 	return cappRevisions.Items, err
 }
 
-// CreateCappRevision initializes and creates a CappRevision.
-func CreateCappRevision(ctx context.Context, k8sClient client.Client, logger logr.Logger, capp cappv1alpha1.Capp, revisionNumber int) error {
+// CreateCappRevision initializes and creates a CappRevision, then sets its status to the given status,
+// retrying the update of the status on conflicts.
+func CreateCappRevision(ctx context.Context, k8sClient client.Client, logger logr.Logger, capp cappv1alpha1.Capp, revisionNumber int, status cappv1alpha1.CappRevisionStatus) error {
 	cappRevision := cappv1alpha1.CappRevision{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
@@ -73,6 +76,27 @@ func CreateCappRevision(ctx context.Context, k8sClient client.Client, logger log
 		return err
 	}
 
+	// The status is set right after the creation on the created object, since the cache may not have seen it
+	// yet, and is retried on conflicts since nothing sets it again once the CappRevision exists. A CappRevision
+	// which is not found when it is fetched again is not yet in the cache, so that is retried as well.
+	created := true
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return errors.IsConflict(err) || errors.IsNotFound(err)
+	}, func() error {
+		if !created {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&cappRevision), &cappRevision); err != nil {
+				return err
+			}
+		}
+		created = false
+		cappRevision.Status = status
+		return k8sClient.Status().Update(ctx, &cappRevision)
+	})
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to update status of CappRevision %q.", cappRevision.Name))
+		return err
+	}
+
 	logger.Info(fmt.Sprintf("Successfully created CappRevision %q", cappRevision.Name))
 	return nil
 }
@@ -29,6 +29,9 @@ type CappRevisionReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	EventRecorder record.EventRecorder
+
+	// RetentionPolicy is the default retention of CappRevisions, which can be overridden per Capp.
+	RetentionPolicy actionmanagers.RetentionPolicy
 }
 
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps,verbs=get;list;watch;create;update;patch;delete
@@ -36,6 +39,8 @@ type CappRevisionReconciler struct {
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps/finalizers,verbs=update
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capprevisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capprevisions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=serving.knative.dev,resources=revisions,verbs=get;list;watch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CappRevisionReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -60,26 +65,42 @@ func (r *CappRevisionReconciler) Reconcile(ctx context.Context, req reconcile.Re
 	if !capp.DeletionTimestamp.IsZero() {
 		return ctrl.Result{}, nil
 	}
-	if err := syncCappRevision(ctx, r.Client, capp, logger); err != nil {
+	pruneAfter, err := r.syncCappRevision(ctx, capp, logger)
+	if err != nil {
 		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
 			logger.Info(fmt.Sprintf("Conflict detected requeuing: %s", err.Error()))
 			return ctrl.Result{RequeueAfter: RequeueTime}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to sync Capp: %s", err.Error())
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: pruneAfter}, nil
 }
 
-// syncCappRevision manages the lifecycle of CappRevisions based on the state of a Capp, handling creation, update, or deletion.
-func syncCappRevision(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger) error {
-	cappRevisions, err := adapters.GetCappRevisions(ctx, k8sClient, capp)
+// syncCappRevision manages the lifecycle of CappRevisions based on the state of a Capp, handling creation, update, deletion
+// or rollback to a previous CappRevision, and keeps the status of the CappRevisions up to date. It returns the duration
+// after which the next CappRevision is pruned by age, as nothing else triggers a reconciliation of an idle Capp.
+func (r *CappRevisionReconciler) syncCappRevision(ctx context.Context, capp cappv1alpha1.Capp, logger logr.Logger) (time.Duration, error) {
+	cappRevisions, err := adapters.GetCappRevisions(ctx, r.Client, capp)
 	if err != nil {
 		logger.Error(err, "could not fetch cappRevisions")
-		return err
+		return 0, err
+	}
+
+	if actionmanagers.IsRollbackRequested(capp) {
+		return 0, actionmanagers.HandleCappRollback(ctx, r.Client, capp, logger, r.EventRecorder, cappRevisions)
 	}
 
 	if len(cappRevisions) == 0 {
-		return actionmanagers.HandleCappCreation(ctx, k8sClient, capp, logger)
+		return 0, actionmanagers.HandleCappCreation(ctx, r.Client, capp, logger)
 	}
-	return actionmanagers.HandleCappUpdate(ctx, k8sClient, capp, logger, cappRevisions)
+
+	policy := actionmanagers.ResolveRetentionPolicy(capp, r.RetentionPolicy, logger)
+	if err := actionmanagers.HandleCappUpdate(ctx, r.Client, capp, logger, r.EventRecorder, cappRevisions, policy); err != nil {
+		return 0, err
+	}
+	if err := actionmanagers.SyncCappRevisionStatus(ctx, r.Client, capp, logger, cappRevisions); err != nil {
+		return 0, err
+	}
+
+	return actionmanagers.NextPruneAfter(cappRevisions, policy, time.Now()), nil
 }
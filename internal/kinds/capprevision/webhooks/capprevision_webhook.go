@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-rcs-dana-io-v1alpha1-capprevision,mutating=false,failurePolicy=fail,sideEffects=None,groups=rcs.dana.io,resources=capprevisions,verbs=update,versions=v1alpha1,name=vcapprevision.rcs.dana.io,admissionReviewVersions=v1
+
+// CappRevisionValidator validates CappRevision objects on update.
+type CappRevisionValidator struct{}
+
+// SetupWebhookWithManager registers the CappRevision validating webhook with the Manager.
+func (v *CappRevisionValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&cappv1alpha1.CappRevision{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate does nothing, since any CappRevision spec is allowed on creation.
+func (v *CappRevisionValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate makes sure the spec of a CappRevision is not changed after its creation.
+func (v *CappRevisionValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCappRevision, ok := oldObj.(*cappv1alpha1.CappRevision)
+	if !ok {
+		return nil, fmt.Errorf("expected a CappRevision but got a %T", oldObj)
+	}
+
+	newCappRevision, ok := newObj.(*cappv1alpha1.CappRevision)
+	if !ok {
+		return nil, fmt.Errorf("expected a CappRevision but got a %T", newObj)
+	}
+
+	if errs := ValidateCappRevisionUpdate(oldCappRevision, newCappRevision); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(cappv1alpha1.GroupVersion.WithKind("CappRevision").GroupKind(), newCappRevision.Name, errs)
+	}
+
+	return nil, nil
+}
+
+// ValidateDelete does nothing, since deletion of a CappRevision is always allowed.
+func (v *CappRevisionValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateCappRevisionUpdate returns a field error if the spec of the CappRevision was changed.
+func ValidateCappRevisionUpdate(oldCappRevision, newCappRevision *cappv1alpha1.CappRevision) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !equality.Semantic.DeepEqual(oldCappRevision.Spec, newCappRevision.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "spec is immutable after creation"))
+	}
+
+	return allErrs
+}
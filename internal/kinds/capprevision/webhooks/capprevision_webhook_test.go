@@ -0,0 +1,85 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCappRevision() *cappv1alpha1.CappRevision {
+	return &cappv1alpha1.CappRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp-00001", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappRevisionSpec{
+			RevisionNumber: 1,
+			CappTemplate: cappv1alpha1.CappTemplate{
+				Spec: cappv1alpha1.CappSpec{ScaleMetric: "concurrency"},
+			},
+		},
+	}
+}
+
+func TestValidateCappRevisionUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		mutate         func(cappRevision *cappv1alpha1.CappRevision)
+		expectedFields []string
+	}{
+		{name: "unchanged", mutate: func(*cappv1alpha1.CappRevision) {}},
+		{
+			name: "labels and status changed",
+			mutate: func(cappRevision *cappv1alpha1.CappRevision) {
+				cappRevision.Labels = map[string]string{"team": "a"}
+				cappRevision.Status.ChangeCause = "scale up"
+			},
+		},
+		{
+			name:           "revision number changed",
+			mutate:         func(cappRevision *cappv1alpha1.CappRevision) { cappRevision.Spec.RevisionNumber = 2 },
+			expectedFields: []string{"spec"},
+		},
+		{
+			name:           "capp template changed",
+			mutate:         func(cappRevision *cappv1alpha1.CappRevision) { cappRevision.Spec.CappTemplate.Spec.ScaleMetric = "rps" },
+			expectedFields: []string{"spec"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldCappRevision := newCappRevision()
+			newCappRevision := oldCappRevision.DeepCopy()
+			test.mutate(newCappRevision)
+
+			var fields []string
+			for _, err := range ValidateCappRevisionUpdate(oldCappRevision, newCappRevision) {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, test.expectedFields, fields)
+		})
+	}
+}
+
+func TestValidateCappRevision(t *testing.T) {
+	validator := CappRevisionValidator{}
+	cappRevision := newCappRevision()
+
+	_, err := validator.ValidateCreate(context.Background(), cappRevision)
+	assert.NoError(t, err)
+
+	_, err = validator.ValidateUpdate(context.Background(), cappRevision, cappRevision.DeepCopy())
+	assert.NoError(t, err)
+
+	changedCappRevision := cappRevision.DeepCopy()
+	changedCappRevision.Spec.RevisionNumber = 2
+	_, err = validator.ValidateUpdate(context.Background(), cappRevision, changedCappRevision)
+	assert.Error(t, err)
+
+	_, err = validator.ValidateUpdate(context.Background(), &cappv1alpha1.Capp{}, changedCappRevision)
+	assert.Error(t, err)
+
+	_, err = validator.ValidateDelete(context.Background(), cappRevision)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/dana-team/container-app-operator/internal/kinds/capprouter/status"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	cappRouterControllerName   = "CappRouterController"
+	FinalizerCleanupCappRouter = "dana.io/capprouter-cleanup"
+	RequeueTime                = 5 * time.Second
+	// hostnameIndexKey is the field index of the hostname in the status of CappRouters.
+	hostnameIndexKey = "status.hostname"
+)
+
+// CappRouterReconciler reconciles a CappRouter object
+type CappRouterReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	Routing       rmanagers.RoutingConfig
+}
+
+// routerEventRecorder records the events which the resource managers emit for the Capp standing for a
+// CappRouter on the CappRouter itself.
+type routerEventRecorder struct {
+	record.EventRecorder
+	router *cappv1alpha1.CappRouter
+}
+
+// Event records an event on the CappRouter.
+func (e routerEventRecorder) Event(_ runtime.Object, eventType, reason, message string) {
+	e.EventRecorder.Event(e.router, eventType, reason, message)
+}
+
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=capprouters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=capprouters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=capprouters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=capps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=hostnameclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=hostnameclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;update;create;patch
+// +kubebuilder:rbac:groups="record.dns.crossplane.io",resources=cnamerecords,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="recordset.dns.crossplane.io",resources=arecordsets;aaaarecordsets;txtrecordsets,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="externaldns.k8s.io",resources=dnsendpoints,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes,verbs=get;list;watch;update;create;delete
+
+// SetupWithManager sets up the controller with the Manager. The objects of CappRouters are only watched when
+// the Gateway API routing backend is used, as CappRouters are not routed otherwise.
+func (r *CappRouterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&cappv1alpha1.CappRouter{}).
+		Named(cappRouterControllerName).
+		Watches(
+			&cappv1alpha1.Capp{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappRoutersFromCapp),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+
+	if r.Routing.Backend == rmanagers.RoutingBackendGatewayAPI {
+		dnsRecordObjects, err := rmanagers.InstalledDNSRecordObjects(mgr.GetRESTMapper())
+		if err != nil {
+			return err
+		}
+
+		for _, object := range append([]client.Object{&gatewayv1.HTTPRoute{}, &cmapi.Certificate{}}, dnsRecordObjects...) {
+			controllerBuilder = controllerBuilder.Watches(
+				object,
+				handler.EnqueueRequestsFromMapFunc(r.findCappRouterFromLabels),
+				builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+			)
+		}
+
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &cappv1alpha1.CappRouter{}, hostnameIndexKey, indexCappRouterHostname); err != nil {
+			return err
+		}
+
+		controllerBuilder = controllerBuilder.Watches(
+			&cappv1alpha1.HostnameClaim{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappRoutersFromHostnameClaim),
+			builder.WithPredicates(rmanagers.HostnameClaimReleasedPredicate),
+		)
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// findCappRoutersFromCapp maps a Capp to reconciliation requests of the CappRouters in its namespace which route to it.
+func (r *CappRouterReconciler) findCappRoutersFromCapp(ctx context.Context, object client.Object) []reconcile.Request {
+	routers := cappv1alpha1.CappRouterList{}
+	if err := r.List(ctx, &routers, client.InNamespace(object.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CappRouters")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, router := range routers.Items {
+		for _, route := range router.Spec.Routes {
+			if route.CappName == object.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: router.Namespace, Name: router.Name}})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+// findCappRouterFromLabels maps an object of a CappRouter to a reconciliation request of the CappRouter.
+// Objects which are not in the namespace of their CappRouter carry its namespace in a label.
+func (r *CappRouterReconciler) findCappRouterFromLabels(_ context.Context, object client.Object) []reconcile.Request {
+	labels := object.GetLabels()
+
+	name, ok := labels[utils.RouterResourceKey]
+	if !ok {
+		return nil
+	}
+
+	namespace := object.GetNamespace()
+	if routerNamespace, ok := labels[utils.CappNamespaceKey]; ok {
+		namespace = routerNamespace
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+// indexCappRouterHostname returns the hostname in the status of a CappRouter, by which CappRouters are indexed.
+func indexCappRouterHostname(object client.Object) []string {
+	router := object.(*cappv1alpha1.CappRouter)
+	if router.Status.Hostname == "" {
+		return nil
+	}
+
+	return []string{router.Status.Hostname}
+}
+
+// findCappRoutersFromHostnameClaim maps a released HostnameClaim to reconciliation requests of the CappRouters
+// which could not claim its hostname, so that they may claim it instead.
+func (r *CappRouterReconciler) findCappRoutersFromHostnameClaim(ctx context.Context, object client.Object) []reconcile.Request {
+	routers := cappv1alpha1.CappRouterList{}
+	if err := r.List(ctx, &routers, client.MatchingFields{hostnameIndexKey: object.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CappRouters")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, router := range routers.Items {
+		if router.Status.Hostname == object.GetName() && meta.IsStatusConditionTrue(router.Status.Conditions, cappv1alpha1.ConditionTypeHostnameConflict) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: router.Namespace, Name: router.Name}})
+		}
+	}
+
+	return requests
+}
+
+func (r *CappRouterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("CappRouterName", req.Name, "CappRouterNamespace", req.Namespace)
+	logger.Info("Starting Reconcile")
+	router := cappv1alpha1.CappRouter{}
+	if err := r.Client.Get(ctx, req.NamespacedName, &router); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("CappRouter does not exist")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get CappRouter: %s", err.Error())
+	}
+
+	if !router.DeletionTimestamp.IsZero() {
+		if err := r.handleDeletion(ctx, router, logger); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to handle CappRouter deletion: %s", err.Error())
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(&router, FinalizerCleanupCappRouter) {
+		controllerutil.AddFinalizer(&router, FinalizerCleanupCappRouter)
+		if err := r.Update(ctx, &router); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to ensure finalizer in CappRouter: %s", err.Error())
+		}
+	}
+
+	if err := r.syncRouter(ctx, router, logger); err != nil {
+		if errors.IsConflict(err) {
+			logger.Info(fmt.Sprintf("Conflict detected, requeuing: %s", err.Error()))
+			return ctrl.Result{RequeueAfter: RequeueTime}, nil
+		}
+		if stderrors.Is(err, rmanagers.ErrHostnameOwnershipUnknown) {
+			logger.Info(fmt.Sprintf("Hostname ownership is unknown, requeuing: %s", err.Error()))
+			return ctrl.Result{RequeueAfter: RequeueTime}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to sync CappRouter: %s", err.Error())
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resourceManagers returns the managers of the DNS record, Certificate and HTTPRoute of a CappRouter. The DNS record
+// and Certificate are managed like those of a Capp, with their resources labelled with the name of the CappRouter.
+func (r *CappRouterReconciler) resourceManagers(ctx context.Context, router *cappv1alpha1.CappRouter, logger logr.Logger) (rmanagers.DNSRecordManager, rmanagers.CertificateManager, rmanagers.CappRouterManager) {
+	eventRecorder := routerEventRecorder{EventRecorder: r.EventRecorder, router: router}
+
+	return rmanagers.DNSRecordManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: eventRecorder, ParentKey: utils.RouterResourceKey},
+		rmanagers.CertificateManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: eventRecorder, ParentKey: utils.RouterResourceKey},
+		rmanagers.CappRouterManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: eventRecorder, Routing: r.Routing}
+}
+
+// syncRouter manages the DNS record, Certificate and HTTPRoute of a CappRouter and synchronizes its status.
+// If the routing backend does not support CappRouters, then their resources are cleaned up instead. When the
+// ownership of the hostname is unknown, the other resources and the status are still synchronized before
+// ErrHostnameOwnershipUnknown is returned.
+func (r *CappRouterReconciler) syncRouter(ctx context.Context, router cappv1alpha1.CappRouter, logger logr.Logger) error {
+	dnsRecordManager, certificateManager, routerManager := r.resourceManagers(ctx, &router, logger)
+	capp := rmanagers.RouterCapp(router)
+
+	var ownershipErr error
+	if routerManager.IsRequired() {
+		if err := dnsRecordManager.Manage(capp); err != nil {
+			if !stderrors.Is(err, rmanagers.ErrHostnameOwnershipUnknown) {
+				return err
+			}
+			ownershipErr = err
+		}
+		if err := certificateManager.Manage(capp); err != nil {
+			return err
+		}
+		if err := routerManager.Manage(router); err != nil {
+			return err
+		}
+	} else if err := r.cleanUp(ctx, router, logger); err != nil {
+		return err
+	}
+
+	if err := status.SyncStatus(ctx, router, logger, r.Client, routerManager.IsRequired()); err != nil {
+		return err
+	}
+
+	return ownershipErr
+}
+
+// handleDeletion cleans up the resources of a CappRouter which is being deleted and removes its finalizer.
+func (r *CappRouterReconciler) handleDeletion(ctx context.Context, router cappv1alpha1.CappRouter, logger logr.Logger) error {
+	if !controllerutil.ContainsFinalizer(&router, FinalizerCleanupCappRouter) {
+		return nil
+	}
+
+	if err := r.cleanUp(ctx, router, logger); err != nil {
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(&router, FinalizerCleanupCappRouter)
+	return r.Update(ctx, &router)
+}
+
+// cleanUp deletes the DNS record, Certificate and HTTPRoute of a CappRouter.
+func (r *CappRouterReconciler) cleanUp(ctx context.Context, router cappv1alpha1.CappRouter, logger logr.Logger) error {
+	dnsRecordManager, certificateManager, routerManager := r.resourceManagers(ctx, &router, logger)
+	capp := rmanagers.RouterCapp(router)
+
+	if err := routerManager.CleanUp(router); err != nil {
+		return err
+	}
+	if err := certificateManager.CleanUp(capp); err != nil {
+		return err
+	}
+
+	return dnsRecordManager.CleanUp(capp)
+}
@@ -0,0 +1,259 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	cappstatus "github.com/dana-team/container-app-operator/internal/kinds/capp/status"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	ReasonRoutingBackendUnsupported = "RoutingBackendUnsupported"
+	reasonReady                     = "Ready"
+	reasonBackendsNotReady          = "BackendsNotReady"
+	reasonBackendsPending           = "BackendsPending"
+	httpScheme                      = "http"
+	httpsScheme                     = "https"
+)
+
+// conditionTypes are the condition types which are aggregated into the Ready condition of a CappRouter, in order.
+var conditionTypes = []string{
+	cappv1alpha1.ConditionTypeDNSRecordReady,
+	cappv1alpha1.ConditionTypeCertificateReady,
+	cappv1alpha1.ConditionTypeHTTPRouteReady,
+	cappv1alpha1.ConditionTypeBackendsReady,
+}
+
+// SyncStatus synchronizes the status of a CappRouter with its DNS record, Certificate, HTTPRoute and Capps,
+// and patches it only if it has changed. If the routing backend does not support CappRouters, then only
+// the status of its routes is kept and the Ready condition is false.
+func SyncStatus(ctx context.Context, router cappv1alpha1.CappRouter, log logr.Logger, r client.Client, routingSupported bool) error {
+	routerObject := cappv1alpha1.CappRouter{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: router.Namespace, Name: router.Name}, &routerObject); err != nil {
+		return err
+	}
+
+	originalRouterObject := routerObject.DeepCopy()
+
+	routesStatus, err := buildRoutesStatus(ctx, r, router)
+	if err != nil {
+		return err
+	}
+	routerObject.Status.Routes = routesStatus
+
+	if routingSupported {
+		if err := buildRoutingStatus(ctx, r, router, &routerObject.Status); err != nil {
+			return err
+		}
+	} else {
+		routerObject.Status.Hostname = ""
+		routerObject.Status.URL = ""
+		routerObject.Status.DNSBackend = ""
+		for _, conditionType := range conditionTypes {
+			meta.RemoveStatusCondition(&routerObject.Status.Conditions, conditionType)
+		}
+		meta.RemoveStatusCondition(&routerObject.Status.Conditions, cappv1alpha1.ConditionTypeHostnameConflict)
+		meta.SetStatusCondition(&routerObject.Status.Conditions, metav1.Condition{
+			Type:               cappv1alpha1.ConditionTypeReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonRoutingBackendUnsupported,
+			Message:            "CappRouters are only supported by the gatewayAPI routing backend",
+			ObservedGeneration: router.Generation,
+		})
+	}
+
+	routerObject.Status.ObservedGeneration = router.Generation
+
+	if equality.Semantic.DeepEqual(originalRouterObject.Status, routerObject.Status) {
+		return nil
+	}
+
+	if err := r.Status().Patch(ctx, &routerObject, client.MergeFrom(originalRouterObject)); err != nil {
+		log.Error(err, "failed to patch CappRouter status")
+		return err
+	}
+
+	return nil
+}
+
+// buildRoutingStatus sets the hostname and URL of a CappRouter, together with the conditions of its DNS record,
+// Certificate, HTTPRoute and Capps, which are aggregated into its Ready condition, and its HostnameConflict
+// condition. The transition time of a condition only changes with its status.
+func buildRoutingStatus(ctx context.Context, r client.Client, router cappv1alpha1.CappRouter, routerStatus *cappv1alpha1.CappRouterStatus) error {
+	dnsConfig, err := utils.GetDNSConfig(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	if err != nil {
+		return err
+	}
+
+	hostname := zones.ResourceName(router.Spec.Hostname)
+	routerStatus.Hostname = hostname
+	routerStatus.URL = fmt.Sprintf("%s://%s", httpScheme, hostname)
+	if router.Spec.TlsEnabled {
+		routerStatus.URL = fmt.Sprintf("%s://%s", httpsScheme, hostname)
+	}
+
+	conditions := map[string]*metav1.Condition{}
+
+	hostnameStatus := cappv1alpha1.HostnameStatus{Hostname: hostname}
+	hostnameStatus.ClaimedBy, err = rmanagers.GetHostnameConflict(ctx, r, hostname, rmanagers.RouterCapp(router), utils.RouterResourceKey)
+	if err != nil {
+		return err
+	}
+
+	// The DNS record of a hostname which is owned by others is not looked up, its condition is overridden below.
+	dnsRecordStatus := cappv1alpha1.DNSRecordObjectStatus{}
+	if hostnameStatus.ClaimedBy == "" {
+		dnsRecordStatus, err = rmanagers.GetDNSRecordStatus(ctx, r, hostname, router.Namespace)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	routerStatus.DNSBackend = dnsRecordStatus.Backend
+	conditions[cappv1alpha1.ConditionTypeDNSRecordReady] = cappstatus.DNSRecordCondition(dnsRecordStatus)
+
+	if router.Spec.TlsEnabled {
+		certificate := &cmapi.Certificate{}
+		if err := getIfExists(ctx, r, types.NamespacedName{Namespace: router.Namespace, Name: hostname}, certificate); err != nil {
+			return err
+		}
+		conditions[cappv1alpha1.ConditionTypeCertificateReady] = cappstatus.CertificateCondition(certificate.Status)
+	}
+
+	httpRoute := &gatewayv1.HTTPRoute{}
+	if err := getIfExists(ctx, r, types.NamespacedName{Namespace: router.Namespace, Name: hostname}, httpRoute); err != nil {
+		return err
+	}
+	conditions[cappv1alpha1.ConditionTypeHTTPRouteReady] = cappstatus.HTTPRouteCondition(httpRoute.Status)
+	conditions[cappv1alpha1.ConditionTypeBackendsReady] = backendsCondition(routerStatus.Routes)
+
+	notAllowed := !zones.Match(hostname).IsNamespaceAllowed(router.Namespace)
+	if notAllowed || hostnameStatus.ClaimedBy != "" {
+		for _, conditionType := range []string{cappv1alpha1.ConditionTypeDNSRecordReady, cappv1alpha1.ConditionTypeCertificateReady,
+			cappv1alpha1.ConditionTypeHTTPRouteReady} {
+			if conditions[conditionType] == nil {
+				continue
+			}
+			if notAllowed {
+				conditions[conditionType] = cappstatus.HostnameNotAllowedRouteCondition(conditionType, hostname)
+			} else {
+				conditions[conditionType] = cappstatus.HostnameConflictRouteCondition(conditionType, hostname, hostnameStatus.ClaimedBy)
+			}
+		}
+	}
+
+	for _, conditionType := range conditionTypes {
+		condition := conditions[conditionType]
+		if condition == nil {
+			meta.RemoveStatusCondition(&routerStatus.Conditions, conditionType)
+			continue
+		}
+		condition.ObservedGeneration = router.Generation
+		meta.SetStatusCondition(&routerStatus.Conditions, *condition)
+	}
+
+	hostnameConflict := cappstatus.HostnameConflictCondition([]cappv1alpha1.HostnameStatus{hostnameStatus})
+	hostnameConflict.ObservedGeneration = router.Generation
+	meta.SetStatusCondition(&routerStatus.Conditions, hostnameConflict)
+
+	ready := cappstatus.ReadyCondition(routerStatus.Conditions, conditionTypes)
+	ready.ObservedGeneration = router.Generation
+	meta.SetStatusCondition(&routerStatus.Conditions, ready)
+
+	return nil
+}
+
+// buildRoutesStatus returns the status of the backend of every route of a CappRouter, which is the
+// Ready condition of its Capp.
+func buildRoutesStatus(ctx context.Context, r client.Client, router cappv1alpha1.CappRouter) ([]cappv1alpha1.CappRouterRouteStatus, error) {
+	routesStatus := make([]cappv1alpha1.CappRouterRouteStatus, 0, len(router.Spec.Routes))
+
+	for _, route := range router.Spec.Routes {
+		routeStatus := cappv1alpha1.CappRouterRouteStatus{PathPrefix: route.PathPrefix, CappName: route.CappName}
+
+		capp := cappv1alpha1.Capp{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: router.Namespace, Name: route.CappName}, &capp); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			routeStatus.Ready = metav1.ConditionFalse
+			routeStatus.Message = fmt.Sprintf("Capp %q does not exist", route.CappName)
+			routesStatus = append(routesStatus, routeStatus)
+			continue
+		}
+
+		ready := meta.FindStatusCondition(capp.Status.Conditions, cappv1alpha1.ConditionTypeReady)
+		if ready == nil {
+			routeStatus.Ready = metav1.ConditionUnknown
+			routeStatus.Message = "waiting for the Capp to report its readiness"
+		} else {
+			routeStatus.Ready = ready.Status
+			if ready.Status != metav1.ConditionTrue {
+				routeStatus.Message = ready.Message
+			}
+		}
+
+		routesStatus = append(routesStatus, routeStatus)
+	}
+
+	return routesStatus, nil
+}
+
+// backendsCondition sets the BackendsReady condition according to the readiness of the Capps of the routes.
+// It is false if any Capp is not ready or does not exist, unknown if any Capp is pending, and true otherwise.
+func backendsCondition(routesStatus []cappv1alpha1.CappRouterRouteStatus) *metav1.Condition {
+	var notReady, pending []string
+
+	for _, routeStatus := range routesStatus {
+		backend := fmt.Sprintf("%s (%s)", routeStatus.PathPrefix, routeStatus.CappName)
+		switch routeStatus.Ready {
+		case metav1.ConditionFalse:
+			notReady = append(notReady, backend)
+		case metav1.ConditionUnknown:
+			pending = append(pending, backend)
+		}
+	}
+
+	condition := metav1.Condition{Type: cappv1alpha1.ConditionTypeBackendsReady}
+	switch {
+	case len(notReady) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonBackendsNotReady
+		condition.Message = fmt.Sprintf("Capps are not ready: %s", strings.Join(notReady, ", "))
+	case len(pending) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = reasonBackendsPending
+		condition.Message = fmt.Sprintf("waiting for Capps: %s", strings.Join(pending, ", "))
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonReady
+	}
+
+	return &condition
+}
+
+// getIfExists gets an object, leaving it empty if it does not exist.
+func getIfExists(ctx context.Context, r client.Client, key types.NamespacedName, obj client.Object) error {
+	if err := r.Get(ctx, key, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns/apis/record/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newRouterClient(objects ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	_ = cmapi.AddToScheme(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	_ = gatewayv1.Install(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": "capp-zone.com.", "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(append(objects, dnsConfig)...).
+		WithStatusSubresource(&cappv1alpha1.CappRouter{}).Build()
+}
+
+func TestSyncStatus(t *testing.T) {
+	router := &cappv1alpha1.CappRouter{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-router", Namespace: "test-ns", Generation: 2},
+		Spec: cappv1alpha1.CappRouterSpec{
+			Hostname: "shop",
+			Routes: []cappv1alpha1.CappRouterRoute{
+				{PathPrefix: "/orders", CappName: "orders"},
+				{PathPrefix: "/", CappName: "storefront"},
+			},
+		},
+	}
+	orders := &cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test-ns"}}
+	orders.Status.Conditions = []metav1.Condition{{Type: cappv1alpha1.ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "AllSubsystemsReady"}}
+	httpRoute := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "shop.capp-zone.com", Namespace: "test-ns"}}
+	httpRoute.Status.Parents = []gatewayv1.RouteParentStatus{{Conditions: []metav1.Condition{
+		{Type: string(gatewayv1.RouteConditionAccepted), Status: metav1.ConditionTrue},
+		{Type: string(gatewayv1.RouteConditionResolvedRefs), Status: metav1.ConditionTrue},
+	}}}
+	claim := &cappv1alpha1.HostnameClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop.capp-zone.com"},
+		Spec:       cappv1alpha1.HostnameClaimSpec{Owner: cappv1alpha1.HostnameClaimOwner{Kind: "Capp", Namespace: "other-ns", Name: "shop"}},
+	}
+	k8sClient := newRouterClient(router, orders, httpRoute, claim)
+	ctx := context.Background()
+
+	assert.NoError(t, SyncStatus(ctx, *router, logr.Discard(), k8sClient, true))
+
+	routerObject := cappv1alpha1.CappRouter{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(router), &routerObject))
+	assert.Equal(t, "shop.capp-zone.com", routerObject.Status.Hostname)
+	assert.Equal(t, "http://shop.capp-zone.com", routerObject.Status.URL)
+	assert.Equal(t, int64(2), routerObject.Status.ObservedGeneration)
+	assert.Equal(t, []cappv1alpha1.CappRouterRouteStatus{
+		{PathPrefix: "/orders", CappName: "orders", Ready: metav1.ConditionTrue},
+		{PathPrefix: "/", CappName: "storefront", Ready: metav1.ConditionFalse, Message: `Capp "storefront" does not exist`},
+	}, routerObject.Status.Routes)
+
+	conditions := routerObject.Status.Conditions
+	assert.Nil(t, meta.FindStatusCondition(conditions, cappv1alpha1.ConditionTypeCertificateReady))
+	// The HTTPRoute of a hostname which is claimed by another owner is not managed.
+	httpRouteReady := meta.FindStatusCondition(conditions, cappv1alpha1.ConditionTypeHTTPRouteReady)
+	assert.Equal(t, metav1.ConditionFalse, httpRouteReady.Status)
+	assert.Equal(t, "hostname shop.capp-zone.com is claimed by Capp other-ns/shop", httpRouteReady.Message)
+	assert.True(t, meta.IsStatusConditionFalse(conditions, cappv1alpha1.ConditionTypeBackendsReady))
+	assert.True(t, meta.IsStatusConditionFalse(conditions, cappv1alpha1.ConditionTypeReady))
+	assert.True(t, meta.IsStatusConditionFalse(conditions, cappv1alpha1.ConditionTypeDNSRecordReady))
+	hostnameConflict := meta.FindStatusCondition(conditions, cappv1alpha1.ConditionTypeHostnameConflict)
+	assert.Equal(t, metav1.ConditionTrue, hostnameConflict.Status)
+	assert.Equal(t, "shop.capp-zone.com is claimed by Capp other-ns/shop", hostnameConflict.Message)
+
+	assert.NoError(t, SyncStatus(ctx, *router, logr.Discard(), k8sClient, false))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(router), &routerObject))
+	assert.Empty(t, routerObject.Status.Hostname)
+	assert.Nil(t, meta.FindStatusCondition(routerObject.Status.Conditions, cappv1alpha1.ConditionTypeHTTPRouteReady))
+	assert.Nil(t, meta.FindStatusCondition(routerObject.Status.Conditions, cappv1alpha1.ConditionTypeHostnameConflict))
+	ready := meta.FindStatusCondition(routerObject.Status.Conditions, cappv1alpha1.ConditionTypeReady)
+	assert.Equal(t, ReasonRoutingBackendUnsupported, ready.Reason)
+}
+
+func TestBackendsCondition(t *testing.T) {
+	condition := backendsCondition([]cappv1alpha1.CappRouterRouteStatus{
+		{PathPrefix: "/orders", CappName: "orders", Ready: metav1.ConditionTrue},
+		{PathPrefix: "/", CappName: "storefront", Ready: metav1.ConditionUnknown},
+	})
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, "waiting for Capps: / (storefront)", condition.Message)
+
+	condition = backendsCondition([]cappv1alpha1.CappRouterRouteStatus{{PathPrefix: "/", CappName: "storefront", Ready: metav1.ConditionTrue}})
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+}
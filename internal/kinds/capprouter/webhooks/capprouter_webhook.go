@@ -0,0 +1,141 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	cappwebhooks "github.com/dana-team/container-app-operator/internal/kinds/capp/webhooks"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-rcs-dana-io-v1alpha1-capprouter,mutating=false,failurePolicy=fail,sideEffects=None,groups=rcs.dana.io,resources=capprouters,verbs=create;update,versions=v1alpha1,name=vcapprouter.rcs.dana.io,admissionReviewVersions=v1
+
+// CappRouterValidator validates CappRouter objects on creation and update.
+type CappRouterValidator struct {
+	Client client.Client
+}
+
+// SetupWebhookWithManager registers the CappRouter validating webhook with the Manager.
+func (v *CappRouterValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&cappv1alpha1.CappRouter{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates a CappRouter on creation.
+func (v *CappRouterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	router, ok := obj.(*cappv1alpha1.CappRouter)
+	if !ok {
+		return nil, fmt.Errorf("expected a CappRouter but got a %T", obj)
+	}
+
+	return nil, v.validate(ctx, router)
+}
+
+// ValidateUpdate validates a CappRouter on update. CappRouters which are being deleted are
+// not validated, so that their finalizer can always be removed.
+func (v *CappRouterValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	router, ok := newObj.(*cappv1alpha1.CappRouter)
+	if !ok {
+		return nil, fmt.Errorf("expected a CappRouter but got a %T", newObj)
+	}
+
+	if !router.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	return nil, v.validate(ctx, router)
+}
+
+// ValidateDelete does nothing, since deletion of a CappRouter is always allowed.
+func (v *CappRouterValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate returns an Invalid error containing all the field errors found in the CappRouter.
+func (v *CappRouterValidator) validate(ctx context.Context, router *cappv1alpha1.CappRouter) error {
+	errs := ValidateCappRouter(ctx, v.Client, router)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(cappv1alpha1.GroupVersion.WithKind("CappRouter").GroupKind(), router.Name, errs)
+}
+
+// ValidateCappRouter validates that the hostname of a CappRouter fits a zone from the DNS ConfigMap which its namespace may use,
+// that it is not used by a Capp or another CappRouter in the namespace and that its path prefixes are unique, and returns a list
+// of the field errors found.
+func ValidateCappRouter(ctx context.Context, k8sClient client.Client, router *cappv1alpha1.CappRouter) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	hostnamePath := specPath.Child("hostname")
+
+	dnsConfig, err := utils.GetDNSConfig(ctx, k8sClient)
+	if err != nil {
+		return append(allErrs, field.InternalError(hostnamePath, err))
+	}
+
+	zones, err := utils.GetDNSZonesFromConfig(dnsConfig)
+	if err != nil {
+		return append(allErrs, field.InternalError(hostnamePath, err))
+	}
+
+	allErrs = append(allErrs, cappwebhooks.ValidateZoneHostname(router.Spec.Hostname, router.Namespace, zones, hostnamePath)...)
+	if len(allErrs) == 0 {
+		allErrs = append(allErrs, validateHostnameUnused(ctx, k8sClient, router, zones, hostnamePath)...)
+	}
+
+	pathPrefixes := map[string]bool{}
+	for i, route := range router.Spec.Routes {
+		if pathPrefixes[route.PathPrefix] {
+			allErrs = append(allErrs, field.Duplicate(specPath.Child("routes").Index(i).Child("pathPrefix"), route.PathPrefix))
+		}
+		pathPrefixes[route.PathPrefix] = true
+	}
+
+	return allErrs
+}
+
+// validateHostnameUnused validates that the hostname of a CappRouter is neither one of the hostnames of a Capp nor the
+// hostname of another CappRouter in its namespace, as their HTTPRoutes would have the same name.
+func validateHostnameUnused(ctx context.Context, k8sClient client.Client, router *cappv1alpha1.CappRouter, zones utils.DNSZones, fldPath *field.Path) field.ErrorList {
+	hostname := zones.ResourceName(router.Spec.Hostname)
+
+	capps := cappv1alpha1.CappList{}
+	if err := k8sClient.List(ctx, &capps, client.InNamespace(router.Namespace)); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, fmt.Errorf("failed to list Capps: %w", err))}
+	}
+
+	for _, capp := range capps.Items {
+		if utils.IsClusterLocal(capp.Spec.RouteSpec) || !utils.IsCustomHostnameSet(capp.Spec.RouteSpec.Hostname) {
+			continue
+		}
+		for _, routeHostname := range rmanagers.GetRouteHostnames(capp, zones) {
+			if routeHostname.Hostname == hostname {
+				return field.ErrorList{field.Invalid(fldPath, router.Spec.Hostname, fmt.Sprintf("hostname is used by Capp %q", capp.Name))}
+			}
+		}
+	}
+
+	routers := cappv1alpha1.CappRouterList{}
+	if err := k8sClient.List(ctx, &routers, client.InNamespace(router.Namespace)); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, fmt.Errorf("failed to list CappRouters: %w", err))}
+	}
+
+	for _, other := range routers.Items {
+		if other.Name != router.Name && zones.ResourceName(other.Spec.Hostname) == hostname {
+			return field.ErrorList{field.Invalid(fldPath, router.Spec.Hostname, fmt.Sprintf("hostname is used by CappRouter %q", other.Name))}
+		}
+	}
+
+	return nil
+}
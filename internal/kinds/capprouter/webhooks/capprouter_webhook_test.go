@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateCappRouter(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = cappv1alpha1.AddToScheme(s)
+	dnsConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns-config", Namespace: utils.CappNS},
+		Data:       map[string]string{"zone": "capp-zone.com.", "cname": "ingress.capp-zone.com.", "provider": "dns-default", "issuer": "cert-issuer"},
+	}
+	capp := &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			RouteSpec: cappv1alpha1.RouteSpec{Hostname: "app.capp-zone.com", AdditionalHostnames: []string{"www.capp-zone.com"}},
+		},
+	}
+	otherRouter := &cappv1alpha1.CappRouter{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-router", Namespace: "test-ns"},
+		Spec:       cappv1alpha1.CappRouterSpec{Hostname: "blog"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dnsConfig, capp, otherRouter).Build()
+
+	router := &cappv1alpha1.CappRouter{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-router", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappRouterSpec{
+			Hostname: "shop.capp-zone.com",
+			Routes: []cappv1alpha1.CappRouterRoute{
+				{PathPrefix: "/orders", CappName: "orders"},
+				{PathPrefix: "/", CappName: "storefront"},
+			},
+		},
+	}
+	assert.Empty(t, ValidateCappRouter(context.Background(), k8sClient, router))
+
+	router.Spec.Hostname = "capp-zone.com"
+	router.Spec.Routes = append(router.Spec.Routes, cappv1alpha1.CappRouterRoute{PathPrefix: "/orders", CappName: "payments"})
+	errs := ValidateCappRouter(context.Background(), k8sClient, router)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "spec.hostname", errs[0].Field)
+	assert.Equal(t, "spec.routes[2].pathPrefix", errs[1].Field)
+
+	// The hostname must not be used by a Capp or another CappRouter in the namespace.
+	router.Spec.Routes = router.Spec.Routes[:2]
+	for _, hostname := range []string{"app.capp-zone.com", "www", "blog.capp-zone.com"} {
+		router.Spec.Hostname = hostname
+		errs = ValidateCappRouter(context.Background(), k8sClient, router)
+		assert.Len(t, errs, 1, hostname)
+		assert.Equal(t, "spec.hostname", errs[0].Field)
+	}
+
+	// A CappRouter does not collide with itself.
+	assert.Empty(t, ValidateCappRouter(context.Background(), k8sClient, otherRouter))
+}
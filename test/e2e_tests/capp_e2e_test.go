@@ -5,7 +5,7 @@ import (
 
 	"k8s.io/client-go/util/retry"
 
-	_ "github.com/dana-team/container-app-operator/api/v1alpha1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/dana-team/container-app-operator/test/e2e_tests/mocks"
 	"github.com/dana-team/container-app-operator/test/e2e_tests/testconsts"
 	utilst "github.com/dana-team/container-app-operator/test/e2e_tests/utils"
@@ -25,6 +25,27 @@ var _ = Describe("Validate capp creation", func() {
 		Expect(k8sClient.Create(context.Background(), baseCapp)).ShouldNot(Equal(nil))
 	})
 
+	It("Should reject invalid capp spec in admission", func() {
+		baseCapp := mocks.CreateBaseCapp()
+		baseCapp.Name = utilst.GenerateCappName()
+
+		By("Creating Capp with TLS enabled and no hostname")
+		tlsCapp := baseCapp.DeepCopy()
+		tlsCapp.Spec.RouteSpec.TlsEnabled = true
+		Expect(k8sClient.Create(context.Background(), tlsCapp)).ShouldNot(Succeed())
+
+		By("Creating Capp with elastic logging and no host")
+		logCapp := baseCapp.DeepCopy()
+		logCapp.Spec.LogSpec = mocks.CreateElasticLogSpec()
+		logCapp.Spec.LogSpec.Host = ""
+		Expect(k8sClient.Create(context.Background(), logCapp)).ShouldNot(Succeed())
+
+		By("Creating Capp with duplicate NFS volume names")
+		volumesCapp := baseCapp.DeepCopy()
+		volumesCapp.Spec.VolumesSpec.NFSVolumes = []cappv1alpha1.NFSVolume{{Name: "data"}, {Name: "data"}}
+		Expect(k8sClient.Create(context.Background(), volumesCapp)).ShouldNot(Succeed())
+	})
+
 	It("Should succeed all adapter functions", func() {
 		baseCapp := mocks.CreateBaseCapp()
 		desiredCapp := utilst.CreateCapp(k8sClient, baseCapp)
@@ -118,6 +118,7 @@ var _ = Describe("Validate Certificate functionality", func() {
 			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 				toBeUpdatedCapp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
 				toBeUpdatedCapp.Spec.RouteSpec.Hostname = ""
+				toBeUpdatedCapp.Spec.RouteSpec.TlsEnabled = false
 
 				return utilst.UpdateResource(k8sClient, toBeUpdatedCapp)
 			})